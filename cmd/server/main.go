@@ -6,8 +6,12 @@ import (
 
 	"go-blog-api/internal/model"
 	"go-blog-api/internal/router"
+	"go-blog-api/internal/service"
+	"go-blog-api/pkg/cache"
 	"go-blog-api/pkg/config"
 	"go-blog-api/pkg/db"
+	"go-blog-api/pkg/storage"
+	"go-blog-api/pkg/util"
 
 	_ "go-blog-api/docs" // Swagger docs
 )
@@ -39,13 +43,33 @@ func main() {
 	// 2. 初始化数据库连接
 	db.InitDB()
 
+	// 初始化文章读取缓存（需在创建 repository 之前）
+	if err := cache.InitCache(); err != nil {
+		panic(err)
+	}
+
 	// 3. 自动迁移数据表（等创建Model后再启用）
-	db.AutoMigrate(&model.User{}, &model.Article{}, &model.Comment{})
+	db.AutoMigrate(&model.User{}, &model.Article{}, &model.ArticleSlugRedirect{}, &model.Comment{}, &model.RecoveryCode{}, &model.UserIdentity{}, &model.APIKey{}, &model.Session{}, &model.Media{}, &model.MediaBlob{}, &model.MediaVariant{}, &model.Reaction{}, &model.ReactionCount{}, &model.Bookmark{}, &model.ReadingList{}, &model.ReadingListItem{}, &model.Follow{}, &model.Notification{}, &model.NotificationPreference{}, &model.Webhook{}, &model.WebhookDelivery{}, &model.OutboxEvent{}, &model.BroadcastMessage{}, &model.IdempotencyKey{}, &model.ModerationAction{}, &model.AuditLog{}, &model.Tag{})
+
+	// 4. 加载 JWT 签名密钥并启动定期轮换
+	util.InitJWTKeys()
+	util.StartJWTKeyRotation()
+
+	// 5. 启动图片处理、webhook 投递等后台任务，最后启动领域事件 relay
+	store, err := storage.New(config.AppConfig.Storage)
+	if err != nil {
+		panic(err)
+	}
+	service.StartMediaWorkers(store)
+	service.StartWebhookWorkers()
+	service.StartIdempotencyCleanup()
+	service.StartBroadcastListener()
+	service.StartOutboxRelay()
 
-	// 4. 初始化 Gin 路由
+	// 6. 初始化 Gin 路由
 	r := router.InitRouter()
 
-	// 5. 启动服务
+	// 7. 启动服务
 	addr := ":" + config.AppConfig.Server.Port
 	fmt.Printf("Server starting on %s\n", addr)
 
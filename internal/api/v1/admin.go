@@ -0,0 +1,347 @@
+package v1
+
+import (
+	"context"
+	"encoding/csv"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/internal/service"
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminController 负责处理管理员用户处置和审计日志相关的 HTTP 请求
+type AdminController struct {
+	adminService *service.AdminService
+	auditService *service.AuditService
+}
+
+func NewAdminController() *AdminController {
+	sessionService := service.NewSessionService(repository.NewSessionRepository())
+	auditService := service.NewAuditService(repository.NewAuditRepository())
+	svc := service.NewAdminService(repository.NewUserRepository(), repository.NewCachedArticleRepository(),
+		repository.NewModerationRepository(), sessionService, auditService, repository.NewTransactor())
+	return &AdminController{adminService: svc, auditService: auditService}
+}
+
+// ListUsers 按条件查询用户
+// @Summary      查询用户（管理员）
+// @Description  按状态、角色、注册时间筛选用户，deleted=true 时只查已注销的用户
+// @Tags         管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      dto.AdminListUsersRequest  true  "筛选条件"
+// @Success      200      {object}  util.Response{data=dto.AdminUserPageResponse}
+// @Failure      403      {object}  util.Response  "需要管理员权限"
+// @Router       /admin/users/list [post]
+func (ctrl *AdminController) ListUsers(c *gin.Context) {
+	var req dto.AdminListUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	resp, err := ctrl.adminService.ListUsers(c.Request.Context(), &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
+
+// SuspendUser 暂停用户
+// @Summary      暂停用户（管理员）
+// @Description  暂停期间不能登录和访问接口，到期后自动恢复；until 为空表示直到手动解除
+// @Tags         管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                     true  "用户 ID"
+// @Param        request  body      dto.SuspendUserRequest  true  "原因和到期时间"
+// @Success      200      {object}  util.Response{data=dto.AdminUserResponse}
+// @Failure      403      {object}  util.Response  "需要管理员权限 / 不能处置管理员"
+// @Failure      404      {object}  util.Response  "用户不存在"
+// @Router       /admin/users/{id}/suspend [post]
+func (ctrl *AdminController) SuspendUser(c *gin.Context) {
+	moderate(c, ctrl.adminService.Suspend)
+}
+
+// BanUser 封禁用户
+// @Summary      封禁用户（管理员）
+// @Description  封禁期间不能登录和访问接口，并立即吊销全部会话；until 为空表示永久封禁
+// @Tags         管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                     true  "用户 ID"
+// @Param        request  body      dto.SuspendUserRequest  true  "原因和到期时间"
+// @Success      200      {object}  util.Response{data=dto.AdminUserResponse}
+// @Failure      403      {object}  util.Response  "需要管理员权限 / 不能处置管理员"
+// @Failure      404      {object}  util.Response  "用户不存在"
+// @Router       /admin/users/{id}/ban [post]
+func (ctrl *AdminController) BanUser(c *gin.Context) {
+	moderate(c, ctrl.adminService.Ban)
+}
+
+// UnsuspendUser 解除暂停或封禁
+// @Summary      解除暂停/封禁（管理员）
+// @Tags         管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                          true  "用户 ID"
+// @Param        request  body      dto.ModerationReasonRequest  true  "原因"
+// @Success      200      {object}  util.Response{data=dto.AdminUserResponse}
+// @Failure      409      {object}  util.Response  "用户未被暂停或封禁"
+// @Router       /admin/users/{id}/unsuspend [post]
+func (ctrl *AdminController) UnsuspendUser(c *gin.Context) {
+	moderate(c, ctrl.adminService.Unsuspend)
+}
+
+// ForcePasswordReset 强制重置密码
+// @Summary      强制重置密码（管理员）
+// @Description  吊销用户全部会话，用户下次登录时只拿到重置密码 token，需调用 /auth/password/reset 设置新密码
+// @Tags         管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                          true  "用户 ID"
+// @Param        request  body      dto.ModerationReasonRequest  true  "原因"
+// @Success      200      {object}  util.Response{data=dto.AdminUserResponse}
+// @Router       /admin/users/{id}/force-password-reset [post]
+func (ctrl *AdminController) ForcePasswordReset(c *gin.Context) {
+	moderate(c, ctrl.adminService.ForcePasswordReset)
+}
+
+// RestoreUser 恢复已注销的用户
+// @Summary      恢复注销账号（管理员）
+// @Description  恢复已注销的用户及注销时一并删除的文章，收藏和阅读清单条目无法恢复
+// @Tags         管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                          true  "用户 ID"
+// @Param        request  body      dto.ModerationReasonRequest  true  "原因"
+// @Success      200      {object}  util.Response{data=dto.AdminUserResponse}
+// @Failure      409      {object}  util.Response  "用户未注销"
+// @Router       /admin/users/{id}/restore [post]
+func (ctrl *AdminController) RestoreUser(c *gin.Context) {
+	moderate(c, ctrl.adminService.Restore)
+}
+
+// SetUserRole 设置用户角色
+// @Summary      设置角色（管理员）
+// @Tags         管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                 true  "用户 ID"
+// @Param        request  body      dto.SetRoleRequest  true  "角色和原因"
+// @Success      200      {object}  util.Response{data=dto.AdminUserResponse}
+// @Router       /admin/users/{id}/role [put]
+func (ctrl *AdminController) SetUserRole(c *gin.Context) {
+	moderate(c, ctrl.adminService.SetRole)
+}
+
+// ImpersonateUser 代登录用户
+// @Summary      代登录（管理员）
+// @Description  签发短期有效的代登录 token，用于排查用户问题；使用该 token 的请求在响应头 X-Impersonated-By 中标记管理员 ID，
+// @Description  不能访问管理接口及 API Key、会话、两步验证等账号安全相关的接口
+// @Tags         管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                          true  "用户 ID"
+// @Param        request  body      dto.ModerationReasonRequest  true  "原因"
+// @Success      200      {object}  util.Response{data=dto.ImpersonationResponse}
+// @Failure      403      {object}  util.Response  "需要管理员权限 / 不能代登录管理员 / 账号不可用"
+// @Router       /admin/users/{id}/impersonate [post]
+func (ctrl *AdminController) ImpersonateUser(c *gin.Context) {
+	client := clientInfo(c)
+	moderate(c, func(ctx context.Context, adminID, userID uint, req *dto.ModerationReasonRequest) (*dto.ImpersonationResponse, error) {
+		return ctrl.adminService.Impersonate(ctx, adminID, userID, req, client)
+	})
+}
+
+// ListModerationActions 获取用户的处置记录
+// @Summary      处置记录（管理员）
+// @Tags         管理
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      int  true   "用户 ID"
+// @Param        page       query     int  false  "页码"
+// @Param        page_size  query     int  false  "每页条数"
+// @Success      200        {object}  util.Response{data=dto.ModerationActionPageResponse}
+// @Router       /admin/users/{id}/actions [get]
+func (ctrl *AdminController) ListModerationActions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的用户 ID"))
+		return
+	}
+
+	var req dto.PageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	resp, err := ctrl.adminService.ListActions(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
+
+// ListAuditLogs 查询审计日志
+// @Summary      查询审计日志（管理员）
+// @Description  按操作者、动作、对象和时间范围筛选审计日志，最新的在前
+// @Tags         管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      dto.AuditLogQuery  true  "筛选条件"
+// @Success      200      {object}  util.Response{data=dto.AuditLogPageResponse}
+// @Failure      400      {object}  util.Response  "参数错误"
+// @Failure      403      {object}  util.Response  "需要管理员权限"
+// @Router       /admin/audit-logs/list [post]
+func (ctrl *AdminController) ListAuditLogs(c *gin.Context) {
+	var req dto.AuditLogQuery
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	resp, err := ctrl.auditService.List(c.Request.Context(), &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
+
+// ExportAuditLogs 导出审计日志
+// @Summary      导出审计日志（管理员）
+// @Description  按筛选条件导出全部审计日志为 CSV（UTF-8 带 BOM），按时间先后排列，忽略分页参数
+// @Tags         管理
+// @Accept       json
+// @Produce      text/csv
+// @Security     BearerAuth
+// @Param        request  body      dto.AuditLogQuery  true  "筛选条件"
+// @Success      200      {file}    file
+// @Failure      400      {object}  util.Response  "参数错误"
+// @Failure      403      {object}  util.Response  "需要管理员权限"
+// @Router       /admin/audit-logs/export [post]
+func (ctrl *AdminController) ExportAuditLogs(c *gin.Context) {
+	var req dto.AuditLogQuery
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	// 第一批数据查询成功后才写响应头，之前的错误仍以 JSON 返回
+	w := csv.NewWriter(c.Writer)
+	started := false
+	start := func() {
+		started = true
+		filename := "audit-logs-" + time.Now().Format("20060102150405") + ".csv"
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		c.Status(http.StatusOK)
+		_, _ = c.Writer.WriteString("\uFEFF")
+		_ = w.Write([]string{"id", "created_at", "actor_id", "impersonator_id", "action", "target_type", "target_id",
+			"changes", "detail", "ip", "user_agent", "request_id"})
+	}
+
+	err := ctrl.auditService.Export(c.Request.Context(), &req, func(logs []model.AuditLog) error {
+		if !started {
+			start()
+		}
+		for i := range logs {
+			_ = w.Write(auditLogRecord(&logs[i]))
+		}
+		w.Flush()
+		return w.Error()
+	})
+	if err != nil && !started {
+		util.HandleError(c, err)
+		return
+	}
+	if err != nil {
+		// 响应已经开始，只能中断，客户端收到的文件不完整
+		log.Printf("export audit logs failed: %v", err)
+		return
+	}
+	if !started {
+		start()
+	}
+	w.Flush()
+}
+
+// auditLogRecord 转换为 CSV 的一行
+func auditLogRecord(l *model.AuditLog) []string {
+	return []string{
+		strconv.FormatUint(uint64(l.ID), 10),
+		l.CreatedAt.Format(time.RFC3339),
+		strconv.FormatUint(uint64(l.ActorID), 10),
+		strconv.FormatUint(uint64(l.ImpersonatorID), 10),
+		l.Action,
+		l.TargetType,
+		strconv.FormatUint(uint64(l.TargetID), 10),
+		csvCell(string(l.Changes)),
+		csvCell(l.Detail),
+		csvCell(l.IP),
+		csvCell(l.UserAgent),
+		csvCell(l.RequestID),
+	}
+}
+
+// csvCell 用户可控的内容以公式字符开头时加上单引号，防止在表格软件中被当作公式执行
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// moderate 处置接口的公共流程：解析路径中的用户 ID 和请求体，以当前管理员身份执行处置
+func moderate[Req, Resp any](c *gin.Context, fn func(ctx context.Context, adminID, userID uint, req *Req) (Resp, error)) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的用户 ID"))
+		return
+	}
+
+	var req Req
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	resp, err := fn(c.Request.Context(), adminID.(uint), uint(id), &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
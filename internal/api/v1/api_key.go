@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"strconv"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/repository"
+	"go-blog-api/internal/service"
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyController 负责处理个人 API Key 相关的 HTTP 请求
+type APIKeyController struct {
+	apiKeyService *service.APIKeyService
+}
+
+func NewAPIKeyController() *APIKeyController {
+	repo := repository.NewAPIKeyRepository()
+	svc := service.NewAPIKeyService(repo)
+	return &APIKeyController{apiKeyService: svc}
+}
+
+// ListAPIKeys 获取当前用户的 API Key 列表
+// @Summary      API Key 列表
+// @Description  获取当前用户创建的 API Key（不含密钥）
+// @Tags         API Key
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  util.Response{data=[]model.APIKey}
+// @Failure      401  {object}  util.Response  "未授权"
+// @Router       /me/api-keys [get]
+func (ctrl *APIKeyController) ListAPIKeys(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	keys, err := ctrl.apiKeyService.List(c.Request.Context(), userID.(uint))
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, keys)
+}
+
+// CreateAPIKey 创建 API Key
+// @Summary      创建 API Key
+// @Description  创建个人 API Key，使用 "Authorization: ApiKey <key>" 访问接口；完整 key 只返回一次
+// @Tags         API Key
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      dto.CreateAPIKeyRequest  true  "名称、权限范围和有效期"
+// @Success      200      {object}  util.Response{data=dto.CreateAPIKeyResponse}
+// @Failure      400      {object}  util.Response  "参数错误"
+// @Failure      401      {object}  util.Response  "未授权"
+// @Router       /me/api-keys [post]
+func (ctrl *APIKeyController) CreateAPIKey(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	resp, err := ctrl.apiKeyService.Create(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
+
+// RevokeAPIKey 吊销 API Key
+// @Summary      吊销 API Key
+// @Description  删除指定的 API Key，立即失效
+// @Tags         API Key
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "API Key ID"
+// @Success      200  {object}  util.Response  "吊销成功"
+// @Failure      401  {object}  util.Response  "未授权"
+// @Failure      404  {object}  util.Response  "API Key 不存在"
+// @Router       /me/api-keys/{id} [delete]
+func (ctrl *APIKeyController) RevokeAPIKey(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的 API Key ID"))
+		return
+	}
+
+	if err := ctrl.apiKeyService.Revoke(c.Request.Context(), userID.(uint), uint(id)); err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, nil)
+}
@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"net/http"
+	"net/url"
 	"strconv"
 
 	"go-blog-api/internal/dto"
@@ -13,18 +15,20 @@ import (
 
 // ArticleController 负责处理文章相关的 HTTP 请求
 type ArticleController struct {
-	articleService *service.ArticleService
+	articleService  *service.ArticleService
+	reactionService *service.ReactionService
 }
 
 func NewArticleController() *ArticleController {
-	repo := repository.NewArticleRepository()
-	svc := service.NewArticleService(repo)
-	return &ArticleController{articleService: svc}
+	repo := repository.NewCachedArticleRepository()
+	svc := service.NewArticleService(repo, repository.NewTagRepository(), repository.NewOutboxRepository(), service.NewAuditService(repository.NewAuditRepository()), repository.NewTransactor())
+	reactionSvc := service.NewReactionService(repository.NewReactionRepository(), repo, repository.NewCommentRepository(), repository.NewOutboxRepository(), repository.NewTransactor())
+	return &ArticleController{articleService: svc, reactionService: reactionSvc}
 }
 
 // GetArticle 获取单篇文章
 // @Summary      获取文章详情
-// @Description  根据文章 ID 获取文章详情
+// @Description  根据文章 ID 获取文章详情，包含回应汇总
 // @Tags         文章
 // @Accept       json
 // @Produce      json
@@ -42,11 +46,41 @@ func (ctrl *ArticleController) GetArticle(c *gin.Context) {
 		return
 	}
 
-	article, err := ctrl.articleService.GetByID(uint(id))
+	article, err := ctrl.articleService.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
 		util.HandleError(c, err)
 		return
 	}
+	ctrl.reactionService.FillArticle(c.Request.Context(), article, c.GetUint("userID"))
+
+	util.Success(c, article)
+}
+
+// GetArticleBySlug 根据 slug 获取文章
+// @Summary      根据 slug 获取文章详情
+// @Description  使用当前 slug 直接返回文章；使用历史 slug 时 301 跳转到当前 slug
+// @Tags         文章
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        slug  path      string  true  "文章 slug"
+// @Success      200   {object}  util.Response{data=model.Article}
+// @Success      301   "跳转到当前 slug"
+// @Failure      401   {object}  util.Response  "未授权"
+// @Failure      404   {object}  util.Response  "文章不存在"
+// @Router       /articles/slug/{slug} [get]
+func (ctrl *ArticleController) GetArticleBySlug(c *gin.Context) {
+	article, canonical, err := ctrl.articleService.GetBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	if canonical != "" {
+		c.Redirect(http.StatusMovedPermanently, "/api/v1/articles/slug/"+url.PathEscape(canonical))
+		return
+	}
+	ctrl.reactionService.FillArticle(c.Request.Context(), article, c.GetUint("userID"))
 
 	util.Success(c, article)
 }
@@ -70,23 +104,59 @@ func (ctrl *ArticleController) ListArticles(c *gin.Context) {
 		return
 	}
 
-	resp, err := ctrl.articleService.List(&req)
+	resp, err := ctrl.articleService.List(c.Request.Context(), &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+	ctrl.reactionService.FillArticles(c.Request.Context(), resp.List, c.GetUint("userID"))
+
+	util.Success(c, resp)
+}
+
+// ListUserArticles 获取作者的文章列表
+// @Summary      作者文章列表
+// @Description  公开接口，无需登录；分页获取某个作者的文章
+// @Tags         文章
+// @Produce      json
+// @Param        id         path      int  true   "作者 ID"
+// @Param        page       query     int  false  "页码"
+// @Param        page_size  query     int  false  "每页条数"
+// @Success      200        {object}  util.Response{data=dto.ArticlePageResponse}
+// @Failure      400        {object}  util.Response  "参数错误"
+// @Router       /users/{id}/articles [get]
+func (ctrl *ArticleController) ListUserArticles(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的用户 ID"))
+		return
+	}
+
+	var req dto.ListArticlesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	resp, err := ctrl.articleService.ListByUser(c.Request.Context(), uint(id), &req)
 	if err != nil {
 		util.HandleError(c, err)
 		return
 	}
+	ctrl.reactionService.FillArticles(c.Request.Context(), resp.List, 0)
 
 	util.Success(c, resp)
 }
 
 // CreateArticle 创建新文章
 // @Summary      创建文章
-// @Description  创建一篇新文章，需要登录
+// @Description  创建一篇新文章，需要登录；正文支持 markdown/html/plain，服务端渲染为过滤后的 content_html
 // @Tags         文章
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        request  body      dto.CreateArticleRequest  true  "文章内容"
+// @Param        Idempotency-Key  header  string  false  "幂等键，重试时携带相同的值不会重复创建"
 // @Success      200      {object}  util.Response{data=model.Article}
 // @Failure      400      {object}  util.Response  "参数错误"
 // @Failure      401      {object}  util.Response  "未授权"
@@ -105,7 +175,7 @@ func (ctrl *ArticleController) CreateArticle(c *gin.Context) {
 		return
 	}
 
-	article, err := ctrl.articleService.Create(userID.(uint), &req)
+	article, err := ctrl.articleService.Create(c.Request.Context(), userID.(uint), &req)
 	if err != nil {
 		util.HandleError(c, err)
 		return
@@ -148,7 +218,7 @@ func (ctrl *ArticleController) UpdateArticle(c *gin.Context) {
 		return
 	}
 
-	article, err := ctrl.articleService.Update(uint(id), userID.(uint), &req)
+	article, err := ctrl.articleService.Update(c.Request.Context(), uint(id), userID.(uint), &req)
 	if err != nil {
 		util.HandleError(c, err)
 		return
@@ -184,7 +254,7 @@ func (ctrl *ArticleController) DeleteArticle(c *gin.Context) {
 		return
 	}
 
-	if err := ctrl.articleService.Delete(uint(id), userID.(uint)); err != nil {
+	if err := ctrl.articleService.Delete(c.Request.Context(), uint(id), userID.(uint)); err != nil {
 		util.HandleError(c, err)
 		return
 	}
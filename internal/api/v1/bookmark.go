@@ -0,0 +1,118 @@
+package v1
+
+import (
+	"strconv"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/repository"
+	"go-blog-api/internal/service"
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BookmarkController 负责处理文章收藏相关的 HTTP 请求
+type BookmarkController struct {
+	bookmarkService *service.BookmarkService
+}
+
+func NewBookmarkController() *BookmarkController {
+	svc := service.NewBookmarkService(repository.NewBookmarkRepository(), repository.NewCachedArticleRepository())
+	return &BookmarkController{bookmarkService: svc}
+}
+
+// ListBookmarks 获取收藏列表
+// @Summary      收藏列表
+// @Description  分页获取当前用户收藏的文章，最近收藏的在前
+// @Tags         收藏
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      dto.ListBookmarksRequest  true  "分页参数"
+// @Success      200      {object}  util.Response{data=dto.BookmarkPageResponse}
+// @Failure      401      {object}  util.Response  "未授权"
+// @Router       /me/bookmarks/list [post]
+func (ctrl *BookmarkController) ListBookmarks(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	var req dto.ListBookmarksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	resp, err := ctrl.bookmarkService.List(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
+
+// AddBookmark 收藏文章
+// @Summary      收藏文章
+// @Description  重复收藏不报错
+// @Tags         收藏
+// @Produce      json
+// @Security     BearerAuth
+// @Param        articleId  path      int  true  "文章 ID"
+// @Success      200        {object}  util.Response  "收藏成功"
+// @Failure      401        {object}  util.Response  "未授权"
+// @Failure      404        {object}  util.Response  "文章不存在"
+// @Router       /me/bookmarks/{articleId} [put]
+func (ctrl *BookmarkController) AddBookmark(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	articleID, err := strconv.ParseUint(c.Param("articleId"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的文章 ID"))
+		return
+	}
+
+	if err := ctrl.bookmarkService.Add(c.Request.Context(), userID.(uint), uint(articleID)); err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, nil)
+}
+
+// RemoveBookmark 取消收藏
+// @Summary      取消收藏
+// @Description  未收藏时不报错
+// @Tags         收藏
+// @Produce      json
+// @Security     BearerAuth
+// @Param        articleId  path      int  true  "文章 ID"
+// @Success      200        {object}  util.Response  "取消成功"
+// @Failure      401        {object}  util.Response  "未授权"
+// @Router       /me/bookmarks/{articleId} [delete]
+func (ctrl *BookmarkController) RemoveBookmark(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	articleID, err := strconv.ParseUint(c.Param("articleId"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的文章 ID"))
+		return
+	}
+
+	if err := ctrl.bookmarkService.Remove(c.Request.Context(), userID.(uint), uint(articleID)); err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, nil)
+}
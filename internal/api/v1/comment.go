@@ -0,0 +1,139 @@
+package v1
+
+import (
+	"strconv"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/repository"
+	"go-blog-api/internal/service"
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CommentController 负责处理评论相关的 HTTP 请求
+type CommentController struct {
+	commentService  *service.CommentService
+	reactionService *service.ReactionService
+}
+
+func NewCommentController() *CommentController {
+	commentRepo := repository.NewCommentRepository()
+	articleRepo := repository.NewCachedArticleRepository()
+	return &CommentController{
+		commentService:  service.NewCommentService(commentRepo, articleRepo, repository.NewOutboxRepository(), repository.NewTransactor()),
+		reactionService: service.NewReactionService(repository.NewReactionRepository(), articleRepo, commentRepo, repository.NewOutboxRepository(), repository.NewTransactor()),
+	}
+}
+
+// ListComments 获取文章的评论列表
+// @Summary      获取评论列表
+// @Description  分页获取文章的评论，按发表时间正序，包含回应汇总
+// @Tags         评论
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                      true  "文章 ID"
+// @Param        request  body      dto.ListCommentsRequest  true  "分页参数"
+// @Success      200      {object}  util.Response{data=dto.CommentPageResponse}
+// @Failure      400      {object}  util.Response  "参数错误"
+// @Failure      401      {object}  util.Response  "未授权"
+// @Failure      404      {object}  util.Response  "文章不存在"
+// @Router       /articles/{id}/comments/list [post]
+func (ctrl *CommentController) ListComments(c *gin.Context) {
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的文章 ID"))
+		return
+	}
+
+	var req dto.ListCommentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	resp, err := ctrl.commentService.List(c.Request.Context(), uint(articleID), &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+	ctrl.reactionService.FillComments(c.Request.Context(), resp.List, c.GetUint("userID"))
+
+	util.Success(c, resp)
+}
+
+// CreateComment 发表评论
+// @Summary      发表评论
+// @Tags         评论
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                       true  "文章 ID"
+// @Param        request  body      dto.CreateCommentRequest  true  "评论内容"
+// @Param        Idempotency-Key  header  string  false  "幂等键，重试时携带相同的值不会重复创建"
+// @Success      200      {object}  util.Response{data=model.Comment}
+// @Failure      400      {object}  util.Response  "参数错误"
+// @Failure      401      {object}  util.Response  "未授权"
+// @Failure      404      {object}  util.Response  "文章不存在"
+// @Router       /articles/{id}/comments [post]
+func (ctrl *CommentController) CreateComment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的文章 ID"))
+		return
+	}
+
+	var req dto.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	comment, err := ctrl.commentService.Create(c.Request.Context(), userID.(uint), uint(articleID), &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, comment)
+}
+
+// DeleteComment 删除评论
+// @Summary      删除评论
+// @Description  评论者本人或文章作者可以删除评论
+// @Tags         评论
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "评论 ID"
+// @Success      200  {object}  util.Response  "删除成功"
+// @Failure      401  {object}  util.Response  "未授权"
+// @Failure      403  {object}  util.Response  "无权限"
+// @Failure      404  {object}  util.Response  "评论不存在"
+// @Router       /comments/{id} [delete]
+func (ctrl *CommentController) DeleteComment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的评论 ID"))
+		return
+	}
+
+	if err := ctrl.commentService.Delete(c.Request.Context(), uint(id), userID.(uint)); err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, nil)
+}
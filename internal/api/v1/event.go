@@ -0,0 +1,289 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/repository"
+	"go-blog-api/internal/service"
+	"go-blog-api/pkg/events"
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	eventHeartbeatInterval = 25 * time.Second // 小于常见反向代理的空闲超时
+	eventWriteTimeout      = 10 * time.Second // 单次写入超时，客户端长时间不读取时断开
+	eventRetryMillis       = 3000             // 建议 EventSource 断线后的重连间隔
+)
+
+// EventController 负责实时事件推送（SSE 和 WebSocket）
+type EventController struct {
+	eventService   *service.EventService
+	sessionService *service.SessionService
+}
+
+func NewEventController() *EventController {
+	return &EventController{
+		eventService:   service.NewEventService(),
+		sessionService: service.NewSessionService(repository.NewSessionRepository()),
+	}
+}
+
+// IssueTicket 获取实时事件连接凭证
+// @Summary      获取连接凭证
+// @Description  浏览器的 EventSource 和 WebSocket 无法设置 Authorization 头，先用本接口获取凭证，再通过 ?ticket= 连接
+// @Tags         实时事件
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  util.Response{data=dto.EventTicketResponse}
+// @Failure      401  {object}  util.Response  "未授权"
+// @Router       /events/ticket [post]
+func (ctrl *EventController) IssueTicket(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	util.Success(c, ctrl.eventService.IssueTicket(userID.(uint), c.GetString("sessionID")))
+}
+
+// Stream 通过 SSE 推送实时事件
+// @Summary      实时事件（SSE）
+// @Description  text/event-stream 推送文章、评论和通知事件。topics 为逗号分隔的主题（articles、article:{id}、author:{id}、user:{自己的 id}），默认为 articles 和自己的通知。
+// @Description  断线重连时携带 Last-Event-ID 请求头（或 last_event_id 参数）补发错过的事件；无法补齐时先推送 reset 事件，客户端应重新拉取数据。
+// @Tags         实时事件
+// @Produce      text/event-stream
+// @Security     BearerAuth
+// @Param        topics         query  string  false  "订阅主题，逗号分隔"
+// @Param        ticket         query  string  false  "连接凭证（无法设置 Authorization 头时使用）"
+// @Param        last_event_id  query  int     false  "最后收到的事件 ID"
+// @Success      200
+// @Failure      400  {object}  util.Response  "无效的主题"
+// @Failure      401  {object}  util.Response  "未授权"
+// @Failure      403  {object}  util.Response  "不能订阅其他用户的通知"
+// @Router       /events [get]
+func (ctrl *EventController) Stream(c *gin.Context) {
+	sub, replay, complete, ok := ctrl.subscribe(c)
+	if !ok {
+		return
+	}
+	defer ctrl.eventService.Unsubscribe(sub)
+
+	h := c.Writer.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no") // 关闭 nginx 缓冲
+	c.Status(http.StatusOK)
+
+	rc := http.NewResponseController(c.Writer)
+	write := func(format string, args ...any) bool {
+		_ = rc.SetWriteDeadline(time.Now().Add(eventWriteTimeout))
+		if _, err := fmt.Fprintf(c.Writer, format, args...); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+	send := func(ev events.Event) bool {
+		data, _ := json.Marshal(ev)
+		return write("id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, data)
+	}
+
+	if !write("retry: %d\n\n", eventRetryMillis) {
+		return
+	}
+	// reset 事件不带 id，不会改变客户端记录的 Last-Event-ID
+	if !complete && !write("event: reset\ndata: {}\n\n") {
+		return
+	}
+	for _, ev := range replay {
+		if !send(ev) {
+			return
+		}
+	}
+
+	ctrl.loop(sub, &streamConn{
+		ctx:       c.Request.Context(),
+		userID:    c.GetUint("userID"),
+		sessionID: c.GetString("sessionID"),
+		send:      send,
+		ping:      func() bool { return write(": ping\n\n") },
+	})
+}
+
+// StreamWebSocket 通过 WebSocket 推送实时事件
+// @Summary      实时事件（WebSocket）
+// @Description  每条消息为一个 JSON 事件（id、type、topics、data、time），另有 reset、ping、error 控制消息。
+// @Description  客户端可发送 {"action":"subscribe|unsubscribe","topics":[...]} 调整订阅；参数与 SSE 接口相同。
+// @Tags         实时事件
+// @Security     BearerAuth
+// @Param        topics         query  string  false  "订阅主题，逗号分隔"
+// @Param        ticket         query  string  false  "连接凭证"
+// @Param        last_event_id  query  int     false  "最后收到的事件 ID"
+// @Success      101
+// @Failure      401  {object}  util.Response  "未授权"
+// @Router       /events/ws [get]
+func (ctrl *EventController) StreamWebSocket(c *gin.Context) {
+	sub, replay, complete, ok := ctrl.subscribe(c)
+	if !ok {
+		return
+	}
+	defer ctrl.eventService.Unsubscribe(sub)
+	userID, sessionID := c.GetUint("userID"), c.GetString("sessionID")
+
+	// 认证依赖 Authorization 头或一次性获取的凭证，浏览器不会自动携带，因此不校验 Origin
+	server := websocket.Server{Handler: func(ws *websocket.Conn) {
+		write := func(v any) bool {
+			_ = ws.SetWriteDeadline(time.Now().Add(eventWriteTimeout))
+			return websocket.JSON.Send(ws, v) == nil
+		}
+
+		if !complete && !write(gin.H{"type": "reset"}) {
+			return
+		}
+		for _, ev := range replay {
+			if !write(ev) {
+				return
+			}
+		}
+
+		// 读取客户端的订阅变更，回复交给写协程发送，保证同一时刻只有一个协程写连接
+		ctx, cancel := context.WithCancel(c.Request.Context())
+		defer cancel()
+		replies := make(chan any, 8)
+		go func() {
+			defer cancel()
+			for {
+				var msg dto.EventStreamMessage
+				if err := websocket.JSON.Receive(ws, &msg); err != nil {
+					return
+				}
+				if reply := ctrl.handleMessage(userID, sub, &msg); reply != nil {
+					select {
+					case replies <- reply:
+					default:
+					}
+				}
+			}
+		}()
+
+		ctrl.loop(sub, &streamConn{
+			ctx:       ctx,
+			userID:    userID,
+			sessionID: sessionID,
+			send:      func(ev events.Event) bool { return write(ev) },
+			ping:      func() bool { return write(gin.H{"type": "ping"}) },
+			replies:   replies,
+			reply:     write,
+		})
+	}}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// streamConn 一条推送连接：各协议的写入方式，以及 WebSocket 客户端消息的回复
+type streamConn struct {
+	ctx       context.Context
+	userID    uint
+	sessionID string
+	send      func(events.Event) bool
+	ping      func() bool
+	replies   <-chan any // 仅 WebSocket
+	reply     func(any) bool
+}
+
+// subscribe 解析订阅参数并订阅，失败时已写入错误响应
+func (ctrl *EventController) subscribe(c *gin.Context) (*events.Subscription, []events.Event, bool, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return nil, nil, false, false
+	}
+
+	var requested []string
+	for _, t := range strings.Split(c.Query("topics"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			requested = append(requested, t)
+		}
+	}
+	topics, err := ctrl.eventService.ResolveTopics(userID.(uint), requested)
+	if err != nil {
+		util.HandleError(c, err)
+		return nil, nil, false, false
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var lastID uint64
+	if lastEventID != "" {
+		if lastID, err = strconv.ParseUint(lastEventID, 10, 64); err != nil {
+			util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的 Last-Event-ID"))
+			return nil, nil, false, false
+		}
+	}
+
+	sub, replay, complete := ctrl.eventService.Subscribe(topics, lastID)
+	return sub, replay, complete, true
+}
+
+// loop 推送事件并定时发送心跳，直到客户端断开、订阅被断开（积压过多）或登录会话失效
+func (ctrl *EventController) loop(sub *events.Subscription, conn *streamConn) {
+	ticker := time.NewTicker(eventHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-conn.ctx.Done():
+			return
+		case <-sub.Done():
+			// 客户端处理过慢被断开，重连时携带最后的事件 ID 即可补齐
+			return
+		case ev := <-sub.Events():
+			if !conn.send(ev) {
+				return
+			}
+		case msg := <-conn.replies:
+			if !conn.reply(msg) {
+				return
+			}
+		case <-ticker.C:
+			if conn.sessionID != "" && !ctrl.sessionService.Validate(conn.ctx, conn.sessionID, conn.userID) {
+				return
+			}
+			if !conn.ping() {
+				return
+			}
+		}
+	}
+}
+
+// handleMessage 处理 WebSocket 客户端的订阅变更，返回需要回复的消息
+func (ctrl *EventController) handleMessage(userID uint, sub *events.Subscription, msg *dto.EventStreamMessage) any {
+	switch msg.Action {
+	case "subscribe":
+		if len(msg.Topics) == 0 {
+			return gin.H{"type": "error", "message": "topics 不能为空"}
+		}
+		topics, err := ctrl.eventService.ResolveTopics(userID, msg.Topics)
+		if err != nil {
+			return gin.H{"type": "error", "message": err.Error()}
+		}
+		sub.Add(topics...)
+	case "unsubscribe":
+		sub.Remove(msg.Topics...)
+	default:
+		return gin.H{"type": "error", "message": "未知的 action: " + msg.Action}
+	}
+	return gin.H{"type": "subscribed", "topics": sub.Topics()}
+}
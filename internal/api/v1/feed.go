@@ -0,0 +1,167 @@
+package v1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+
+	"go-blog-api/internal/repository"
+	"go-blog-api/internal/service"
+	"go-blog-api/pkg/feed"
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeedController 负责输出 RSS / Atom / JSON Feed 订阅源
+type FeedController struct {
+	feedService *service.FeedService
+}
+
+func NewFeedController() *FeedController {
+	svc := service.NewFeedService(repository.NewCachedArticleRepository(), repository.NewUserRepository(), repository.NewTagRepository())
+	return &FeedController{feedService: svc}
+}
+
+type feedEncoder struct {
+	contentType string
+	encode      func(*feed.Feed) ([]byte, error)
+}
+
+var (
+	rssEncoder  = feedEncoder{"application/rss+xml; charset=utf-8", feed.RSS}
+	atomEncoder = feedEncoder{"application/atom+xml; charset=utf-8", feed.Atom}
+	jsonEncoder = feedEncoder{"application/feed+json; charset=utf-8", feed.JSON}
+)
+
+// RSS 全站 RSS 2.0 订阅
+// @Summary      RSS 订阅
+// @Description  全站最新文章的 RSS 2.0 订阅源，支持 ETag / Last-Modified 条件请求
+// @Tags         订阅
+// @Produce      xml
+// @Success      200
+// @Success      304  "未修改"
+// @Router       /feed.xml [get]
+func (ctrl *FeedController) RSS(c *gin.Context) { ctrl.serve(c, rssEncoder, ctrl.buildSite) }
+
+// Atom 全站 Atom 订阅
+// @Summary      Atom 订阅
+// @Description  全站最新文章的 Atom 1.0 订阅源，支持 ETag / Last-Modified 条件请求
+// @Tags         订阅
+// @Produce      xml
+// @Success      200
+// @Success      304  "未修改"
+// @Router       /atom.xml [get]
+func (ctrl *FeedController) Atom(c *gin.Context) { ctrl.serve(c, atomEncoder, ctrl.buildSite) }
+
+// JSON 全站 JSON Feed 订阅
+// @Summary      JSON Feed 订阅
+// @Description  全站最新文章的 JSON Feed 1.1 订阅源，支持 ETag / Last-Modified 条件请求
+// @Tags         订阅
+// @Produce      json
+// @Success      200
+// @Success      304  "未修改"
+// @Router       /feed.json [get]
+func (ctrl *FeedController) JSON(c *gin.Context) { ctrl.serve(c, jsonEncoder, ctrl.buildSite) }
+
+// AuthorRSS 作者 RSS 订阅
+// @Summary      作者 RSS 订阅
+// @Tags         订阅
+// @Produce      xml
+// @Param        id   path  int  true  "作者 ID"
+// @Success      200
+// @Failure      404  {object}  util.Response  "用户不存在"
+// @Router       /users/{id}/feed.xml [get]
+func (ctrl *FeedController) AuthorRSS(c *gin.Context) { ctrl.serve(c, rssEncoder, ctrl.buildSite) }
+
+// AuthorAtom 作者 Atom 订阅
+// @Summary      作者 Atom 订阅
+// @Tags         订阅
+// @Produce      xml
+// @Param        id   path  int  true  "作者 ID"
+// @Success      200
+// @Failure      404  {object}  util.Response  "用户不存在"
+// @Router       /users/{id}/atom.xml [get]
+func (ctrl *FeedController) AuthorAtom(c *gin.Context) { ctrl.serve(c, atomEncoder, ctrl.buildSite) }
+
+// AuthorJSON 作者 JSON Feed 订阅
+// @Summary      作者 JSON Feed 订阅
+// @Tags         订阅
+// @Produce      json
+// @Param        id   path  int  true  "作者 ID"
+// @Success      200
+// @Failure      404  {object}  util.Response  "用户不存在"
+// @Router       /users/{id}/feed.json [get]
+func (ctrl *FeedController) AuthorJSON(c *gin.Context) { ctrl.serve(c, jsonEncoder, ctrl.buildSite) }
+
+// TagRSS 标签 RSS 订阅
+// @Summary      标签 RSS 订阅
+// @Tags         订阅
+// @Produce      xml
+// @Param        slug  path  string  true  "标签 slug"
+// @Success      200
+// @Failure      404  {object}  util.Response  "标签不存在"
+// @Router       /tags/{slug}/feed.xml [get]
+func (ctrl *FeedController) TagRSS(c *gin.Context) { ctrl.serve(c, rssEncoder, ctrl.buildTag) }
+
+// TagAtom 标签 Atom 订阅
+// @Summary      标签 Atom 订阅
+// @Tags         订阅
+// @Produce      xml
+// @Param        slug  path  string  true  "标签 slug"
+// @Success      200
+// @Failure      404  {object}  util.Response  "标签不存在"
+// @Router       /tags/{slug}/atom.xml [get]
+func (ctrl *FeedController) TagAtom(c *gin.Context) { ctrl.serve(c, atomEncoder, ctrl.buildTag) }
+
+// TagJSON 标签 JSON Feed 订阅
+// @Summary      标签 JSON Feed 订阅
+// @Tags         订阅
+// @Produce      json
+// @Param        slug  path  string  true  "标签 slug"
+// @Success      200
+// @Failure      404  {object}  util.Response  "标签不存在"
+// @Router       /tags/{slug}/feed.json [get]
+func (ctrl *FeedController) TagJSON(c *gin.Context) { ctrl.serve(c, jsonEncoder, ctrl.buildTag) }
+
+// buildSite 构建全站或作者订阅源（路由带 id 参数时为作者）
+func (ctrl *FeedController) buildSite(c *gin.Context) (*feed.Feed, error) {
+	var authorID uint
+	if idParam := c.Param("id"); idParam != "" {
+		id, err := strconv.ParseUint(idParam, 10, 32)
+		if err != nil {
+			return nil, util.ErrInvalidParam.WithMsg("无效的用户 ID")
+		}
+		authorID = uint(id)
+	}
+	return ctrl.feedService.Build(c.Request.Context(), authorID)
+}
+
+// buildTag 构建标签订阅源
+func (ctrl *FeedController) buildTag(c *gin.Context) (*feed.Feed, error) {
+	return ctrl.feedService.BuildTag(c.Request.Context(), c.Param("slug"))
+}
+
+func (ctrl *FeedController) serve(c *gin.Context, enc feedEncoder, build func(*gin.Context) (*feed.Feed, error)) {
+	f, err := build(c)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+	f.FeedURL = siteURL(c.Request.URL.Path)
+
+	body, err := enc.encode(f)
+	if err != nil {
+		util.HandleError(c, util.ErrInternal)
+		return
+	}
+
+	// ETag 由输出内容计算，条目、配置或格式任一变化都会使其改变
+	sum := sha256.Sum256(body)
+	c.Header("Cache-Control", "public, max-age=300")
+	if util.CheckNotModified(c, `"`+hex.EncodeToString(sum[:16])+`"`, f.Updated) {
+		return
+	}
+	c.Data(http.StatusOK, enc.contentType, body)
+}
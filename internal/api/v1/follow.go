@@ -0,0 +1,206 @@
+package v1
+
+import (
+	"context"
+	"strconv"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/internal/service"
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FollowController 负责处理关注关系和首页时间线相关的 HTTP 请求
+type FollowController struct {
+	followService   *service.FollowService
+	reactionService *service.ReactionService
+}
+
+func NewFollowController() *FollowController {
+	articleRepo := repository.NewCachedArticleRepository()
+	return &FollowController{
+		followService:   service.NewFollowService(repository.NewFollowRepository(), repository.NewUserRepository(), articleRepo, repository.NewOutboxRepository(), repository.NewTransactor()),
+		reactionService: service.NewReactionService(repository.NewReactionRepository(), articleRepo, repository.NewCommentRepository(), repository.NewOutboxRepository(), repository.NewTransactor()),
+	}
+}
+
+// Follow 关注用户
+// @Summary      关注用户
+// @Description  重复关注不报错
+// @Tags         关注
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "用户 ID"
+// @Success      200  {object}  util.Response  "关注成功"
+// @Failure      400  {object}  util.Response  "不能关注自己"
+// @Failure      401  {object}  util.Response  "未授权"
+// @Failure      404  {object}  util.Response  "用户不存在"
+// @Router       /users/{id}/follow [put]
+func (ctrl *FollowController) Follow(c *gin.Context) {
+	userID, id, ok := followParams(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.followService.Follow(c.Request.Context(), userID, id); err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, nil)
+}
+
+// Unfollow 取消关注
+// @Summary      取消关注
+// @Description  未关注时不报错
+// @Tags         关注
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "用户 ID"
+// @Success      200  {object}  util.Response  "取消成功"
+// @Failure      401  {object}  util.Response  "未授权"
+// @Router       /users/{id}/follow [delete]
+func (ctrl *FollowController) Unfollow(c *gin.Context) {
+	userID, id, ok := followParams(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.followService.Unfollow(c.Request.Context(), userID, id); err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, nil)
+}
+
+// FollowStats 关注统计
+// @Summary      关注统计
+// @Description  粉丝数、关注数，以及当前用户是否已关注
+// @Tags         关注
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "用户 ID"
+// @Success      200  {object}  util.Response{data=dto.FollowStatsResponse}
+// @Failure      401  {object}  util.Response  "未授权"
+// @Failure      404  {object}  util.Response  "用户不存在"
+// @Router       /users/{id}/follow-stats [get]
+func (ctrl *FollowController) FollowStats(c *gin.Context) {
+	userID, id, ok := followParams(c)
+	if !ok {
+		return
+	}
+
+	stats, err := ctrl.followService.Stats(c.Request.Context(), id, userID)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, stats)
+}
+
+// ListFollowers 粉丝列表
+// @Summary      粉丝列表
+// @Tags         关注
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                     true  "用户 ID"
+// @Param        request  body      dto.ListFollowsRequest  true  "分页参数"
+// @Success      200      {object}  util.Response{data=dto.UserPageResponse}
+// @Failure      401      {object}  util.Response  "未授权"
+// @Router       /users/{id}/followers/list [post]
+func (ctrl *FollowController) ListFollowers(c *gin.Context) {
+	ctrl.listFollows(c, ctrl.followService.Followers)
+}
+
+// ListFollowing 关注列表
+// @Summary      关注列表
+// @Tags         关注
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                     true  "用户 ID"
+// @Param        request  body      dto.ListFollowsRequest  true  "分页参数"
+// @Success      200      {object}  util.Response{data=dto.UserPageResponse}
+// @Failure      401      {object}  util.Response  "未授权"
+// @Router       /users/{id}/following/list [post]
+func (ctrl *FollowController) ListFollowing(c *gin.Context) {
+	ctrl.listFollows(c, ctrl.followService.Following)
+}
+
+func (ctrl *FollowController) listFollows(c *gin.Context, list func(context.Context, uint, *dto.ListFollowsRequest) (*dto.PageResponse[model.User], error)) {
+	_, id, ok := followParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ListFollowsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	resp, err := list(c.Request.Context(), id, &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
+
+// Feed 首页时间线
+// @Summary      首页时间线
+// @Description  关注作者的最新文章（不含正文），使用 next_cursor 获取下一页
+// @Tags         关注
+// @Produce      json
+// @Security     BearerAuth
+// @Param        cursor  query     string  false  "游标，第一页为空"
+// @Param        limit   query     int     false  "每页条数（默认 20，最大 50）"
+// @Success      200     {object}  util.Response{data=dto.ArticleCursorResponse}
+// @Failure      400     {object}  util.Response  "参数错误"
+// @Failure      401     {object}  util.Response  "未授权"
+// @Router       /me/feed [get]
+func (ctrl *FollowController) Feed(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	var req dto.CursorRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	resp, err := ctrl.followService.Feed(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+	ctrl.reactionService.FillArticles(c.Request.Context(), resp.List, userID.(uint))
+
+	util.Success(c, resp)
+}
+
+// followParams 解析当前用户 ID 和路径中的目标用户 ID，失败时已写入错误响应
+func followParams(c *gin.Context) (uint, uint, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return 0, 0, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的用户 ID"))
+		return 0, 0, false
+	}
+	return userID.(uint), uint(id), true
+}
@@ -0,0 +1,23 @@
+package v1
+
+import (
+	"strings"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/pkg/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// clientInfo 提取客户端信息，用于记录登录会话
+func clientInfo(c *gin.Context) dto.ClientInfo {
+	return dto.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+	}
+}
+
+// siteURL 站点地址下的完整地址；响应允许公共缓存时不能使用请求中可伪造的 Host 生成链接
+func siteURL(path string) string {
+	return strings.TrimRight(config.AppConfig.Site.URL, "/") + path
+}
@@ -0,0 +1,22 @@
+package v1
+
+import (
+	"net/http"
+
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKS 发布用于验证本服务签发的 JWT 的公钥集合，供其他服务离线校验 token
+// @Summary      JWKS 公钥集合
+// @Description  返回 JSON Web Key Set，包含当前签名密钥和仍在有效期内的旧密钥
+// @Tags         认证
+// @Produce      json
+// @Success      200  {object}  oidc.JSONWebKeySet
+// @Router       /.well-known/jwks.json [get]
+func JWKS(c *gin.Context) {
+	// 允许短时间缓存；密钥轮换后新 kid 会在缓存过期后被拉取
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, util.PublicJWKS())
+}
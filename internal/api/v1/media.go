@@ -0,0 +1,270 @@
+package v1
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/internal/service"
+	"go-blog-api/pkg/config"
+	"go-blog-api/pkg/storage"
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead multipart 表单除文件内容外的额外开销上限
+const multipartOverhead = 1 << 20
+
+// MediaController 负责处理文件上传与下载相关的 HTTP 请求
+type MediaController struct {
+	mediaService *service.MediaService
+}
+
+func NewMediaController() *MediaController {
+	store, err := storage.New(config.AppConfig.Storage)
+	if err != nil {
+		log.Fatalf("Failed to init storage: %v", err)
+	}
+	svc := service.NewMediaService(repository.NewMediaRepository(), repository.NewUserRepository(), store, repository.NewTransactor())
+	return &MediaController{mediaService: svc}
+}
+
+// UploadAvatar 上传头像
+// @Summary      上传头像
+// @Description  multipart 上传头像（jpeg/png/gif/webp），成功后自动设为当前用户头像
+// @Tags         文件
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file  formData  file  true  "头像文件"
+// @Success      200   {object}  util.Response{data=model.Media}
+// @Failure      400   {object}  util.Response  "参数错误"
+// @Failure      401   {object}  util.Response  "未授权"
+// @Failure      413   {object}  util.Response  "文件过大"
+// @Failure      415   {object}  util.Response  "不支持的文件类型"
+// @Router       /media/avatar [post]
+func (ctrl *MediaController) UploadAvatar(c *gin.Context) {
+	ctrl.upload(c, model.MediaKindAvatar)
+}
+
+// UploadAttachment 上传文章附件
+// @Summary      上传附件
+// @Description  multipart 上传文章插图或附件，返回带有效期的签名下载地址
+// @Tags         文件
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file  formData  file  true  "附件文件"
+// @Success      200   {object}  util.Response{data=model.Media}
+// @Failure      400   {object}  util.Response  "参数错误"
+// @Failure      401   {object}  util.Response  "未授权"
+// @Failure      413   {object}  util.Response  "文件过大"
+// @Failure      415   {object}  util.Response  "不支持的文件类型"
+// @Router       /media [post]
+func (ctrl *MediaController) UploadAttachment(c *gin.Context) {
+	ctrl.upload(c, model.MediaKindAttachment)
+}
+
+func (ctrl *MediaController) upload(c *gin.Context, kind string) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	// 限制请求体大小，超出后读取会直接失败，避免大文件占满内存
+	limit := service.MaxUploadSize(kind)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			util.HandleError(c, util.ErrFileTooLarge)
+			return
+		}
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("缺少上传文件 file"))
+		return
+	}
+	if fileHeader.Size > limit {
+		util.HandleError(c, util.ErrFileTooLarge)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无法读取上传文件"))
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, limit+1))
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无法读取上传文件"))
+		return
+	}
+
+	media, err := ctrl.mediaService.Upload(c.Request.Context(), userID.(uint), kind, fileHeader.Filename, data)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, media)
+}
+
+// ListMedia 获取我上传的文件
+// @Summary      文件列表
+// @Description  分页获取当前用户上传的文件
+// @Tags         文件
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      dto.PageRequest  true  "分页参数"
+// @Success      200      {object}  util.Response{data=dto.MediaPageResponse}
+// @Failure      401      {object}  util.Response  "未授权"
+// @Router       /media/list [post]
+func (ctrl *MediaController) ListMedia(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	var req dto.PageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	resp, err := ctrl.mediaService.List(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
+
+// GetMedia 获取文件详情
+// @Summary      文件详情
+// @Description  获取文件信息和新的签名下载地址
+// @Tags         文件
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "文件 ID"
+// @Success      200  {object}  util.Response{data=model.Media}
+// @Failure      401  {object}  util.Response  "未授权"
+// @Failure      404  {object}  util.Response  "文件不存在"
+// @Router       /media/{id} [get]
+func (ctrl *MediaController) GetMedia(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的文件 ID"))
+		return
+	}
+
+	media, err := ctrl.mediaService.Get(c.Request.Context(), userID.(uint), uint(id))
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, media)
+}
+
+// DeleteMedia 删除文件
+// @Summary      删除文件
+// @Description  删除自己上传的文件
+// @Tags         文件
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "文件 ID"
+// @Success      200  {object}  util.Response  "删除成功"
+// @Failure      401  {object}  util.Response  "未授权"
+// @Failure      404  {object}  util.Response  "文件不存在"
+// @Router       /media/{id} [delete]
+func (ctrl *MediaController) DeleteMedia(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的文件 ID"))
+		return
+	}
+
+	if err := ctrl.mediaService.Delete(c.Request.Context(), userID.(uint), uint(id)); err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, nil)
+}
+
+// DownloadMedia 下载文件
+// @Summary      下载文件
+// @Description  头像可直接访问；附件需要携带 expires 和 signature 签名参数。指定 variant 时返回派生尺寸，处理未完成时回退到原图
+// @Tags         文件
+// @Produce      octet-stream
+// @Param        id         path   int     true   "文件 ID"
+// @Param        variant    path   string  false  "派生尺寸（avatar_64/avatar_128/avatar_256/thumbnail/medium/large）"
+// @Param        expires    query  int     false  "过期时间（Unix 秒）"
+// @Param        signature  query  string  false  "签名"
+// @Success      200
+// @Failure      403  {object}  util.Response  "链接无效或已过期"
+// @Failure      404  {object}  util.Response  "文件不存在"
+// @Router       /media/{id}/file [get]
+// @Router       /media/{id}/file/{variant} [get]
+func (ctrl *MediaController) DownloadMedia(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的文件 ID"))
+		return
+	}
+
+	file, err := ctrl.mediaService.Open(c.Request.Context(), uint(id), c.Param("variant"), c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+	defer file.Reader.Close()
+
+	// 图片内联展示，其他类型强制下载；禁止浏览器再次嗅探类型
+	disposition := "attachment"
+	if strings.HasPrefix(file.MimeType, "image/") {
+		disposition = "inline"
+	}
+	// 派生图的存储键由内容决定，生成后不再变化；回退到原图的响应不能长期缓存
+	cacheControl := "private, max-age=300"
+	if file.Media.Kind == model.MediaKindAvatar {
+		cacheControl = "public, max-age=86400"
+		if file.Variant != "" {
+			cacheControl = "public, max-age=31536000, immutable"
+		}
+	}
+	headers := map[string]string{
+		"Content-Disposition":    mime.FormatMediaType(disposition, map[string]string{"filename": file.Media.OriginalName}),
+		"Cache-Control":          cacheControl,
+		"ETag":                   `"` + file.StorageKey + `"`,
+		"X-Content-Type-Options": "nosniff",
+	}
+
+	c.DataFromReader(http.StatusOK, file.Size, file.MimeType, file.Reader, headers)
+}
@@ -0,0 +1,195 @@
+package v1
+
+import (
+	"strconv"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/repository"
+	"go-blog-api/internal/service"
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationController 负责处理站内通知相关的 HTTP 请求
+type NotificationController struct {
+	notificationService *service.NotificationService
+}
+
+func NewNotificationController() *NotificationController {
+	svc := service.NewNotificationService(repository.NewNotificationRepository(), repository.NewUserRepository(),
+		repository.NewCachedArticleRepository(), repository.NewCommentRepository())
+	return &NotificationController{notificationService: svc}
+}
+
+// ListNotifications 获取通知列表
+// @Summary      通知列表
+// @Description  分页获取当前用户的通知，最新的在前，附带未读总数
+// @Tags         通知
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      dto.ListNotificationsRequest  true  "分页参数"
+// @Success      200      {object}  util.Response{data=dto.NotificationListResponse}
+// @Failure      401      {object}  util.Response  "未授权"
+// @Router       /me/notifications/list [post]
+func (ctrl *NotificationController) ListNotifications(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	var req dto.ListNotificationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	resp, err := ctrl.notificationService.List(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
+
+// UnreadCount 获取未读通知数
+// @Summary      未读通知数
+// @Description  获取当前用户的未读通知数，供前端轮询角标
+// @Tags         通知
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  util.Response{data=dto.UnreadCountResponse}
+// @Failure      401  {object}  util.Response  "未授权"
+// @Router       /me/notifications/unread-count [get]
+func (ctrl *NotificationController) UnreadCount(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	resp, err := ctrl.notificationService.UnreadCount(c.Request.Context(), userID.(uint))
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
+
+// MarkRead 标记通知已读
+// @Summary      标记已读
+// @Description  标记单条通知为已读，重复标记或通知不存在时不报错
+// @Tags         通知
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "通知 ID"
+// @Success      200  {object}  util.Response  "标记成功"
+// @Failure      401  {object}  util.Response  "未授权"
+// @Router       /me/notifications/{id}/read [put]
+func (ctrl *NotificationController) MarkRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的通知 ID"))
+		return
+	}
+
+	if err := ctrl.notificationService.MarkRead(c.Request.Context(), userID.(uint), uint(id)); err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, nil)
+}
+
+// MarkAllRead 全部标记已读
+// @Summary      全部已读
+// @Description  将当前用户的所有未读通知标记为已读
+// @Tags         通知
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  util.Response  "标记成功"
+// @Failure      401  {object}  util.Response  "未授权"
+// @Router       /me/notifications/read-all [put]
+func (ctrl *NotificationController) MarkAllRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	if err := ctrl.notificationService.MarkAllRead(c.Request.Context(), userID.(uint)); err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, nil)
+}
+
+// GetPreference 获取通知偏好
+// @Summary      通知偏好
+// @Description  获取各类通知的开关，未设置过时返回默认值（全部开启）
+// @Tags         通知
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  util.Response{data=model.NotificationPreference}
+// @Failure      401  {object}  util.Response  "未授权"
+// @Router       /me/notification-preferences [get]
+func (ctrl *NotificationController) GetPreference(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	pref, err := ctrl.notificationService.GetPreference(c.Request.Context(), userID.(uint))
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, pref)
+}
+
+// UpdatePreference 更新通知偏好
+// @Summary      更新通知偏好
+// @Description  只更新请求中出现的开关
+// @Tags         通知
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      dto.UpdateNotificationPreferenceRequest  true  "通知开关"
+// @Success      200      {object}  util.Response{data=model.NotificationPreference}
+// @Failure      400      {object}  util.Response  "参数错误"
+// @Failure      401      {object}  util.Response  "未授权"
+// @Router       /me/notification-preferences [put]
+func (ctrl *NotificationController) UpdatePreference(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	var req dto.UpdateNotificationPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	pref, err := ctrl.notificationService.UpdatePreference(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, pref)
+}
@@ -0,0 +1,115 @@
+package v1
+
+import (
+	"net/http"
+
+	"go-blog-api/internal/repository"
+	"go-blog-api/internal/service"
+	"go-blog-api/pkg/config"
+	"go-blog-api/pkg/oidc"
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oidcStateCookie 保存授权状态（state/nonce/code_verifier）的加密 Cookie
+const oidcStateCookie = "oidc_state"
+
+// OIDCController 负责处理第三方登录（OAuth2 / OIDC）相关的 HTTP 请求
+type OIDCController struct {
+	oidcService *service.OIDCService
+}
+
+func NewOIDCController() *OIDCController {
+	providers := oidc.NewProviders(config.AppConfig.OIDC.Providers, nil)
+	sessionService := service.NewSessionService(repository.NewSessionRepository())
+	svc := service.NewOIDCService(providers, repository.NewUserRepository(), repository.NewUserIdentityRepository(), sessionService, service.NewAuditService(repository.NewAuditRepository()), repository.NewTransactor())
+	return &OIDCController{oidcService: svc}
+}
+
+// ListProviders 获取可用的第三方登录方式
+// @Summary      第三方登录方式
+// @Description  列出已启用的 OAuth2 / OIDC 登录提供方
+// @Tags         认证
+// @Produce      json
+// @Success      200  {object}  util.Response{data=dto.OIDCProvidersResponse}
+// @Router       /auth/oidc/providers [get]
+func (ctrl *OIDCController) ListProviders(c *gin.Context) {
+	util.Success(c, ctrl.oidcService.Providers())
+}
+
+// Login 跳转到第三方登录页
+// @Summary      发起第三方登录
+// @Description  使用授权码 + PKCE 流程，302 跳转到提供方的授权页面
+// @Tags         认证
+// @Param        provider  path  string  true  "提供方名称"
+// @Success      302
+// @Failure      404  {object}  util.Response  "不支持的登录方式"
+// @Router       /auth/oidc/{provider}/login [get]
+func (ctrl *OIDCController) Login(c *gin.Context) {
+	authURL, sealedState, err := ctrl.oidcService.Begin(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, sealedState, int(service.OIDCStateTTL.Seconds()), "/api/v1/auth/oidc", "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback 第三方登录回调
+// @Summary      第三方登录回调
+// @Description  校验 state / nonce 后换取令牌，首次登录自动创建用户，返回 JWT Token
+// @Tags         认证
+// @Produce      json
+// @Param        provider  path      string  true  "提供方名称"
+// @Param        code      query     string  true  "授权码"
+// @Param        state     query     string  true  "state"
+// @Success      200       {object}  util.Response{data=dto.LoginResponse}
+// @Failure      400       {object}  util.Response  "state 校验失败"
+// @Failure      401       {object}  util.Response  "第三方登录失败"
+// @Router       /auth/oidc/{provider}/callback [get]
+func (ctrl *OIDCController) Callback(c *gin.Context) {
+	sealedState, _ := c.Cookie(oidcStateCookie)
+	// state 只能使用一次，无论成功与否都清除
+	c.SetCookie(oidcStateCookie, "", -1, "/api/v1/auth/oidc", "", c.Request.TLS != nil, true)
+
+	if c.Query("error") != "" {
+		util.HandleError(c, util.ErrOAuthFailed.WithMsg("第三方登录已取消或被拒绝"))
+		return
+	}
+
+	resp, err := ctrl.oidcService.Complete(c.Request.Context(), c.Param("provider"), sealedState, c.Query("state"), c.Query("code"), clientInfo(c))
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
+
+// ListIdentities 获取当前用户绑定的第三方身份
+// @Summary      已绑定的第三方身份
+// @Description  列出当前用户绑定的第三方登录身份
+// @Tags         认证
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  util.Response{data=[]model.UserIdentity}
+// @Failure      401  {object}  util.Response  "未授权"
+// @Router       /auth/oidc/identities [get]
+func (ctrl *OIDCController) ListIdentities(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	identities, err := ctrl.oidcService.ListIdentities(c.Request.Context(), userID.(uint))
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, identities)
+}
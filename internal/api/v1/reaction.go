@@ -0,0 +1,125 @@
+package v1
+
+import (
+	"strconv"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/internal/service"
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReactionController 负责处理文章和评论的点赞与表情回应
+type ReactionController struct {
+	reactionService *service.ReactionService
+}
+
+func NewReactionController() *ReactionController {
+	svc := service.NewReactionService(repository.NewReactionRepository(), repository.NewCachedArticleRepository(), repository.NewCommentRepository(), repository.NewOutboxRepository(), repository.NewTransactor())
+	return &ReactionController{reactionService: svc}
+}
+
+// ListReactionTypes 获取可用的回应类型
+// @Summary      回应类型
+// @Description  like 始终可用，其余表情回应由服务端配置
+// @Tags         回应
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  util.Response{data=[]string}
+// @Router       /reactions/types [get]
+func (ctrl *ReactionController) ListReactionTypes(c *gin.Context) {
+	util.Success(c, ctrl.reactionService.Types())
+}
+
+// AddArticleReaction 回应文章
+// @Summary      回应文章
+// @Description  添加点赞或表情回应，重复添加不报错
+// @Tags         回应
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int     true  "文章 ID"
+// @Param        type  path      string  true  "回应类型"
+// @Success      200   {object}  util.Response{data=model.ReactionSummary}
+// @Failure      400   {object}  util.Response  "不支持的回应类型"
+// @Failure      404   {object}  util.Response  "文章不存在"
+// @Router       /articles/{id}/reactions/{type} [put]
+func (ctrl *ReactionController) AddArticleReaction(c *gin.Context) {
+	ctrl.react(c, model.ReactionTargetArticle, true)
+}
+
+// RemoveArticleReaction 取消文章回应
+// @Summary      取消文章回应
+// @Description  取消点赞或表情回应，未回应过时不报错
+// @Tags         回应
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int     true  "文章 ID"
+// @Param        type  path      string  true  "回应类型"
+// @Success      200   {object}  util.Response{data=model.ReactionSummary}
+// @Failure      400   {object}  util.Response  "不支持的回应类型"
+// @Failure      404   {object}  util.Response  "文章不存在"
+// @Router       /articles/{id}/reactions/{type} [delete]
+func (ctrl *ReactionController) RemoveArticleReaction(c *gin.Context) {
+	ctrl.react(c, model.ReactionTargetArticle, false)
+}
+
+// AddCommentReaction 回应评论
+// @Summary      回应评论
+// @Description  添加点赞或表情回应，重复添加不报错
+// @Tags         回应
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int     true  "评论 ID"
+// @Param        type  path      string  true  "回应类型"
+// @Success      200   {object}  util.Response{data=model.ReactionSummary}
+// @Failure      400   {object}  util.Response  "不支持的回应类型"
+// @Failure      404   {object}  util.Response  "评论不存在"
+// @Router       /comments/{id}/reactions/{type} [put]
+func (ctrl *ReactionController) AddCommentReaction(c *gin.Context) {
+	ctrl.react(c, model.ReactionTargetComment, true)
+}
+
+// RemoveCommentReaction 取消评论回应
+// @Summary      取消评论回应
+// @Description  取消点赞或表情回应，未回应过时不报错
+// @Tags         回应
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int     true  "评论 ID"
+// @Param        type  path      string  true  "回应类型"
+// @Success      200   {object}  util.Response{data=model.ReactionSummary}
+// @Failure      400   {object}  util.Response  "不支持的回应类型"
+// @Failure      404   {object}  util.Response  "评论不存在"
+// @Router       /comments/{id}/reactions/{type} [delete]
+func (ctrl *ReactionController) RemoveCommentReaction(c *gin.Context) {
+	ctrl.react(c, model.ReactionTargetComment, false)
+}
+
+func (ctrl *ReactionController) react(c *gin.Context, targetType string, add bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的 ID"))
+		return
+	}
+
+	var summary *model.ReactionSummary
+	if add {
+		summary, err = ctrl.reactionService.Add(c.Request.Context(), userID.(uint), targetType, uint(id), c.Param("type"))
+	} else {
+		summary, err = ctrl.reactionService.Remove(c.Request.Context(), userID.(uint), targetType, uint(id), c.Param("type"))
+	}
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, summary)
+}
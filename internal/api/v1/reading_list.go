@@ -0,0 +1,293 @@
+package v1
+
+import (
+	"strconv"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/repository"
+	"go-blog-api/internal/service"
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadingListController 负责处理阅读清单相关的 HTTP 请求
+type ReadingListController struct {
+	listService *service.ReadingListService
+}
+
+func NewReadingListController() *ReadingListController {
+	svc := service.NewReadingListService(repository.NewReadingListRepository(), repository.NewCachedArticleRepository())
+	return &ReadingListController{listService: svc}
+}
+
+// ListReadingLists 获取阅读清单列表
+// @Summary      阅读清单列表
+// @Description  获取当前用户的所有阅读清单（不含文章）
+// @Tags         阅读清单
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  util.Response{data=[]model.ReadingList}
+// @Failure      401  {object}  util.Response  "未授权"
+// @Router       /me/lists [get]
+func (ctrl *ReadingListController) ListReadingLists(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	lists, err := ctrl.listService.List(c.Request.Context(), userID.(uint))
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, lists)
+}
+
+// CreateReadingList 创建阅读清单
+// @Summary      创建阅读清单
+// @Tags         阅读清单
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      dto.CreateReadingListRequest  true  "清单信息"
+// @Param        Idempotency-Key  header  string  false  "幂等键，重试时携带相同的值不会重复创建"
+// @Success      200      {object}  util.Response{data=model.ReadingList}
+// @Failure      400      {object}  util.Response  "参数错误或数量已达上限"
+// @Failure      401      {object}  util.Response  "未授权"
+// @Router       /me/lists [post]
+func (ctrl *ReadingListController) CreateReadingList(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	var req dto.CreateReadingListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	list, err := ctrl.listService.Create(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, list)
+}
+
+// GetReadingList 获取阅读清单详情
+// @Summary      阅读清单详情
+// @Description  包含按顺序排列的文章
+// @Tags         阅读清单
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "清单 ID"
+// @Success      200  {object}  util.Response{data=model.ReadingList}
+// @Failure      401  {object}  util.Response  "未授权"
+// @Failure      404  {object}  util.Response  "阅读清单不存在"
+// @Router       /me/lists/{id} [get]
+func (ctrl *ReadingListController) GetReadingList(c *gin.Context) {
+	userID, id, ok := listParams(c)
+	if !ok {
+		return
+	}
+
+	list, err := ctrl.listService.Get(c.Request.Context(), userID, id)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, list)
+}
+
+// UpdateReadingList 更新阅读清单
+// @Summary      更新阅读清单
+// @Description  修改名称、描述或公开状态
+// @Tags         阅读清单
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                           true  "清单 ID"
+// @Param        request  body      dto.UpdateReadingListRequest  true  "更新内容"
+// @Success      200      {object}  util.Response{data=model.ReadingList}
+// @Failure      400      {object}  util.Response  "参数错误"
+// @Failure      404      {object}  util.Response  "阅读清单不存在"
+// @Router       /me/lists/{id} [put]
+func (ctrl *ReadingListController) UpdateReadingList(c *gin.Context) {
+	userID, id, ok := listParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateReadingListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	list, err := ctrl.listService.Update(c.Request.Context(), userID, id, &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, list)
+}
+
+// DeleteReadingList 删除阅读清单
+// @Summary      删除阅读清单
+// @Tags         阅读清单
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "清单 ID"
+// @Success      200  {object}  util.Response  "删除成功"
+// @Failure      404  {object}  util.Response  "阅读清单不存在"
+// @Router       /me/lists/{id} [delete]
+func (ctrl *ReadingListController) DeleteReadingList(c *gin.Context) {
+	userID, id, ok := listParams(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.listService.Delete(c.Request.Context(), userID, id); err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, nil)
+}
+
+// AddReadingListItem 添加文章到阅读清单
+// @Summary      添加文章到阅读清单
+// @Description  追加到清单末尾，已在清单中时不报错
+// @Tags         阅读清单
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      int  true  "清单 ID"
+// @Param        articleId  path      int  true  "文章 ID"
+// @Success      200        {object}  util.Response  "添加成功"
+// @Failure      400        {object}  util.Response  "文章数量已达上限"
+// @Failure      404        {object}  util.Response  "阅读清单或文章不存在"
+// @Router       /me/lists/{id}/items/{articleId} [put]
+func (ctrl *ReadingListController) AddReadingListItem(c *gin.Context) {
+	userID, id, ok := listParams(c)
+	if !ok {
+		return
+	}
+	articleID, err := strconv.ParseUint(c.Param("articleId"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的文章 ID"))
+		return
+	}
+
+	if err := ctrl.listService.AddItem(c.Request.Context(), userID, id, uint(articleID)); err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, nil)
+}
+
+// RemoveReadingListItem 从阅读清单移除文章
+// @Summary      从阅读清单移除文章
+// @Tags         阅读清单
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      int  true  "清单 ID"
+// @Param        articleId  path      int  true  "文章 ID"
+// @Success      200        {object}  util.Response  "移除成功"
+// @Failure      404        {object}  util.Response  "阅读清单不存在"
+// @Router       /me/lists/{id}/items/{articleId} [delete]
+func (ctrl *ReadingListController) RemoveReadingListItem(c *gin.Context) {
+	userID, id, ok := listParams(c)
+	if !ok {
+		return
+	}
+	articleID, err := strconv.ParseUint(c.Param("articleId"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的文章 ID"))
+		return
+	}
+
+	if err := ctrl.listService.RemoveItem(c.Request.Context(), userID, id, uint(articleID)); err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, nil)
+}
+
+// ReorderReadingList 调整阅读清单顺序
+// @Summary      调整阅读清单顺序
+// @Description  按传入的文章 ID 顺序排列，未传入的文章排在最后
+// @Tags         阅读清单
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                            true  "清单 ID"
+// @Param        request  body      dto.ReorderReadingListRequest  true  "文章顺序"
+// @Success      200      {object}  util.Response{data=model.ReadingList}
+// @Failure      400      {object}  util.Response  "参数错误"
+// @Failure      404      {object}  util.Response  "阅读清单不存在"
+// @Router       /me/lists/{id}/order [put]
+func (ctrl *ReadingListController) ReorderReadingList(c *gin.Context) {
+	userID, id, ok := listParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ReorderReadingListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	list, err := ctrl.listService.Reorder(c.Request.Context(), userID, id, &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, list)
+}
+
+// GetSharedReadingList 查看公开分享的阅读清单
+// @Summary      查看分享的阅读清单
+// @Description  无需登录；清单未公开时返回 404
+// @Tags         阅读清单
+// @Produce      json
+// @Param        token  path      string  true  "分享 token"
+// @Success      200    {object}  util.Response{data=model.ReadingList}
+// @Failure      404    {object}  util.Response  "阅读清单不存在"
+// @Router       /shared/lists/{token} [get]
+func (ctrl *ReadingListController) GetSharedReadingList(c *gin.Context) {
+	list, err := ctrl.listService.GetShared(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, list)
+}
+
+// listParams 解析当前用户 ID 和路径中的清单 ID，失败时已写入错误响应
+func listParams(c *gin.Context) (uint, uint, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return 0, 0, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的清单 ID"))
+		return 0, 0, false
+	}
+	return userID.(uint), uint(id), true
+}
@@ -0,0 +1,103 @@
+package v1
+
+import (
+	"strconv"
+
+	"go-blog-api/internal/repository"
+	"go-blog-api/internal/service"
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionController 负责处理登录会话（设备）管理相关的 HTTP 请求
+type SessionController struct {
+	sessionService *service.SessionService
+}
+
+func NewSessionController() *SessionController {
+	repo := repository.NewSessionRepository()
+	svc := service.NewSessionService(repo)
+	return &SessionController{sessionService: svc}
+}
+
+// ListSessions 获取当前用户的登录设备
+// @Summary      登录设备列表
+// @Description  列出当前用户所有未过期的登录会话，current 标记当前设备
+// @Tags         会话
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  util.Response{data=[]dto.SessionResponse}
+// @Failure      401  {object}  util.Response  "未授权"
+// @Router       /me/sessions [get]
+func (ctrl *SessionController) ListSessions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	sessions, err := ctrl.sessionService.List(c.Request.Context(), userID.(uint), c.GetString("sessionID"))
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, sessions)
+}
+
+// RevokeSession 下线指定设备
+// @Summary      下线设备
+// @Description  吊销指定会话，该设备上的 token 立即失效
+// @Tags         会话
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "会话 ID"
+// @Success      200  {object}  util.Response  "下线成功"
+// @Failure      401  {object}  util.Response  "未授权"
+// @Failure      404  {object}  util.Response  "会话不存在"
+// @Router       /me/sessions/{id} [delete]
+func (ctrl *SessionController) RevokeSession(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的会话 ID"))
+		return
+	}
+
+	if err := ctrl.sessionService.Revoke(c.Request.Context(), userID.(uint), uint(id)); err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, nil)
+}
+
+// RevokeOtherSessions 下线除当前设备外的全部设备
+// @Summary      下线其他设备
+// @Description  吊销除当前会话外的全部会话
+// @Tags         会话
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  util.Response  "下线成功"
+// @Failure      401  {object}  util.Response  "未授权"
+// @Router       /me/sessions [delete]
+func (ctrl *SessionController) RevokeOtherSessions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	if err := ctrl.sessionService.RevokeOthers(c.Request.Context(), userID.(uint), c.GetString("sessionID")); err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, nil)
+}
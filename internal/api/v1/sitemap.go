@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-blog-api/internal/repository"
+	"go-blog-api/internal/service"
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SitemapController 负责输出站点地图
+type SitemapController struct {
+	sitemapService *service.SitemapService
+}
+
+func NewSitemapController() *SitemapController {
+	svc := service.NewSitemapService(repository.NewCachedArticleRepository(), repository.NewTagRepository())
+	return &SitemapController{sitemapService: svc}
+}
+
+// Sitemap 站点地图入口
+// @Summary      站点地图
+// @Description  包含首页、文章页、作者页和标签页；超过 50000 条时返回 sitemap 索引，分片地址为 /sitemaps/{n}.xml
+// @Tags         订阅
+// @Produce      xml
+// @Success      200
+// @Success      304  "未修改"
+// @Router       /sitemap.xml [get]
+func (ctrl *SitemapController) Sitemap(c *gin.Context) {
+	page, err := ctrl.sitemapService.Root(c.Request.Context(), func(n int) string {
+		return siteURL("/sitemaps/" + strconv.Itoa(n) + ".xml")
+	})
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+	writeSitemap(c, page)
+}
+
+// SitemapPage 站点地图分片
+// @Summary      站点地图分片
+// @Tags         订阅
+// @Produce      xml
+// @Param        page  path  string  true  "分片文件名，如 1.xml"
+// @Success      200
+// @Success      304  "未修改"
+// @Failure      404  {object}  util.Response  "分片不存在"
+// @Router       /sitemaps/{page} [get]
+func (ctrl *SitemapController) SitemapPage(c *gin.Context) {
+	n, err := strconv.Atoi(strings.TrimSuffix(c.Param("page"), ".xml"))
+	if err != nil {
+		util.HandleError(c, util.ErrNotFound)
+		return
+	}
+
+	page, err := ctrl.sitemapService.Page(c.Request.Context(), n)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+	writeSitemap(c, page)
+}
+
+func writeSitemap(c *gin.Context, page *service.SitemapPage) {
+	sum := sha256.Sum256(page.Body)
+	c.Header("Cache-Control", "public, max-age=3600")
+	if util.CheckNotModified(c, `"`+hex.EncodeToString(sum[:16])+`"`, page.LastModified) {
+		return
+	}
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", page.Body)
+}
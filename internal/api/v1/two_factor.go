@@ -0,0 +1,202 @@
+package v1
+
+import (
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/repository"
+	"go-blog-api/internal/service"
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorController 负责处理两步验证（TOTP）相关的 HTTP 请求
+type TwoFactorController struct {
+	twoFactorService *service.TwoFactorService
+}
+
+func NewTwoFactorController() *TwoFactorController {
+	userRepo := repository.NewUserRepository()
+	codeRepo := repository.NewRecoveryCodeRepository()
+	sessionService := service.NewSessionService(repository.NewSessionRepository())
+	svc := service.NewTwoFactorService(userRepo, codeRepo, sessionService, service.NewAuditService(repository.NewAuditRepository()), repository.NewTransactor())
+	return &TwoFactorController{twoFactorService: svc}
+}
+
+// Status 查询两步验证状态
+// @Summary      两步验证状态
+// @Description  查询当前用户是否开启两步验证及剩余恢复码数量
+// @Tags         两步验证
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  util.Response{data=dto.TwoFactorStatusResponse}
+// @Failure      401  {object}  util.Response  "未授权"
+// @Router       /auth/2fa [get]
+func (ctrl *TwoFactorController) Status(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	resp, err := ctrl.twoFactorService.Status(c.Request.Context(), userID.(uint))
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
+
+// Enroll 开始绑定验证器
+// @Summary      绑定验证器
+// @Description  生成 TOTP 密钥和 otpauth URI，需调用确认接口后才会生效
+// @Tags         两步验证
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  util.Response{data=dto.TwoFactorEnrollResponse}
+// @Failure      401  {object}  util.Response  "未授权"
+// @Failure      409  {object}  util.Response  "已开启两步验证"
+// @Router       /auth/2fa/enroll [post]
+func (ctrl *TwoFactorController) Enroll(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	resp, err := ctrl.twoFactorService.Enroll(c.Request.Context(), userID.(uint))
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
+
+// Confirm 确认开启两步验证
+// @Summary      确认开启两步验证
+// @Description  提交验证器上的第一个验证码，成功后返回一次性恢复码（仅展示一次）
+// @Tags         两步验证
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      dto.TwoFactorConfirmRequest  true  "验证码"
+// @Success      200      {object}  util.Response{data=dto.RecoveryCodesResponse}
+// @Failure      400      {object}  util.Response  "验证码错误"
+// @Failure      401      {object}  util.Response  "未授权"
+// @Router       /auth/2fa/confirm [post]
+func (ctrl *TwoFactorController) Confirm(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	var req dto.TwoFactorConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	resp, err := ctrl.twoFactorService.Confirm(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
+
+// Disable 关闭两步验证
+// @Summary      关闭两步验证
+// @Description  需要提供密码和验证码（或恢复码）
+// @Tags         两步验证
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      dto.TwoFactorDisableRequest  true  "密码和验证码"
+// @Success      200      {object}  util.Response  "关闭成功"
+// @Failure      400      {object}  util.Response  "验证码或密码错误"
+// @Failure      401      {object}  util.Response  "未授权"
+// @Router       /auth/2fa/disable [post]
+func (ctrl *TwoFactorController) Disable(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	var req dto.TwoFactorDisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	if err := ctrl.twoFactorService.Disable(c.Request.Context(), userID.(uint), &req); err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, nil)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+// @Summary      重新生成恢复码
+// @Description  提交当前验证码，旧恢复码全部作废
+// @Tags         两步验证
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      dto.TwoFactorConfirmRequest  true  "验证码"
+// @Success      200      {object}  util.Response{data=dto.RecoveryCodesResponse}
+// @Failure      400      {object}  util.Response  "验证码错误"
+// @Failure      401      {object}  util.Response  "未授权"
+// @Router       /auth/2fa/recovery-codes [post]
+func (ctrl *TwoFactorController) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	var req dto.TwoFactorConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	resp, err := ctrl.twoFactorService.RegenerateRecoveryCodes(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
+
+// VerifyLogin 两步登录第二步
+// @Summary      两步登录验证
+// @Description  使用登录接口返回的 challenge_token 和验证码（或恢复码）换取 JWT Token
+// @Tags         认证
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.TwoFactorLoginRequest  true  "挑战 token 和验证码"
+// @Success      200      {object}  util.Response{data=dto.LoginResponse}
+// @Failure      400      {object}  util.Response  "验证码错误"
+// @Failure      401      {object}  util.Response  "挑战已失效"
+// @Router       /auth/2fa/verify [post]
+func (ctrl *TwoFactorController) VerifyLogin(c *gin.Context) {
+	var req dto.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	resp, err := ctrl.twoFactorService.VerifyLogin(c.Request.Context(), &req, clientInfo(c))
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
@@ -20,7 +20,9 @@ type UserController struct {
 // 后续可以通过依赖注入将 userService 作为参数传入
 func NewUserController() *UserController {
 	repo := repository.NewUserRepository()
-	service := service.NewUserService(repo)
+	sessionService := service.NewSessionService(repository.NewSessionRepository())
+	auditService := service.NewAuditService(repository.NewAuditRepository())
+	service := service.NewUserService(repo, repository.NewCachedArticleRepository(), repository.NewOutboxRepository(), sessionService, auditService, repository.NewTransactor())
 	return &UserController{
 		userService: service,
 	}
@@ -36,6 +38,7 @@ func NewUserController() *UserController {
 // @Success      200      {object}  util.Response{data=dto.LoginResponse}
 // @Failure      400      {object}  util.Response  "参数错误"
 // @Failure      401      {object}  util.Response  "用户名或密码错误"
+// @Failure      403      {object}  util.Response  "账号已被暂停或封禁"
 // @Router       /auth/login [post]
 func (ctrl *UserController) Login(c *gin.Context) {
 	var req dto.LoginRequest
@@ -46,7 +49,34 @@ func (ctrl *UserController) Login(c *gin.Context) {
 	}
 
 	// 调用业务逻辑
-	resp, err := ctrl.userService.Login(&req)
+	resp, err := ctrl.userService.Login(c.Request.Context(), &req, clientInfo(c))
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
+
+// ResetPassword 管理员要求重置密码后设置新密码
+// @Summary      重置密码
+// @Description  被管理员要求重置密码的用户登录后只拿到 challenge_token，用它设置新密码后继续登录（开启两步验证的用户仍需完成第二步）
+// @Tags         认证
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.ResetPasswordRequest  true  "重置密码 token 和新密码"
+// @Success      200      {object}  util.Response{data=dto.LoginResponse}
+// @Failure      400      {object}  util.Response  "参数错误"
+// @Failure      401      {object}  util.Response  "token 已失效"
+// @Router       /auth/password/reset [post]
+func (ctrl *UserController) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	resp, err := ctrl.userService.ResetPassword(c.Request.Context(), &req, clientInfo(c))
 	if err != nil {
 		util.HandleError(c, err)
 		return
@@ -75,7 +105,7 @@ func (ctrl *UserController) Register(c *gin.Context) {
 	}
 
 	// 调用业务逻辑
-	if err := ctrl.userService.Register(req); err != nil {
+	if err := ctrl.userService.Register(c.Request.Context(), req); err != nil {
 		util.HandleError(c, err)
 		return
 	}
@@ -102,7 +132,7 @@ func (ctrl *UserController) GetMe(c *gin.Context) {
 		return
 	}
 
-	user, err := ctrl.userService.GetByID(userID.(uint))
+	user, err := ctrl.userService.GetByID(c.Request.Context(), userID.(uint))
 	if err != nil {
 		util.HandleError(c, err)
 		return
@@ -111,9 +141,9 @@ func (ctrl *UserController) GetMe(c *gin.Context) {
 	util.Success(c, user)
 }
 
-// Logout 注销当前登录用户（无服务器端会话，仅示意接口）
+// Logout 注销当前登录用户，吊销当前会话
 // @Summary      注销
-// @Description  注销当前登录用户（客户端需要清除本地 token）
+// @Description  注销当前登录用户，当前 token 立即失效
 // @Tags         认证
 // @Accept       json
 // @Produce      json
@@ -128,7 +158,11 @@ func (ctrl *UserController) Logout(c *gin.Context) {
 		return
 	}
 
-	// 这里没有服务器端会话需要清理；如果需要，可实现 token 黑名单
+	if err := ctrl.userService.Logout(c.Request.Context(), userID.(uint), c.GetString("sessionID")); err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
 	util.Success(c, nil)
 }
 
@@ -152,7 +186,7 @@ func (ctrl *UserController) GetUser(c *gin.Context) {
 		return
 	}
 
-	user, err := ctrl.userService.GetByID(uint(id))
+	user, err := ctrl.userService.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
 		util.HandleError(c, err)
 		return
@@ -180,7 +214,7 @@ func (ctrl *UserController) ListUsers(c *gin.Context) {
 		return
 	}
 
-	resp, err := ctrl.userService.List(&req)
+	resp, err := ctrl.userService.List(c.Request.Context(), &req)
 	if err != nil {
 		util.HandleError(c, err)
 		return
@@ -230,7 +264,7 @@ func (ctrl *UserController) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	user, err := ctrl.userService.Update(uint(id), &req)
+	user, err := ctrl.userService.Update(c.Request.Context(), uint(id), &req)
 	if err != nil {
 		util.HandleError(c, err)
 		return
@@ -273,7 +307,7 @@ func (ctrl *UserController) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	if err := ctrl.userService.Delete(uint(id)); err != nil {
+	if err := ctrl.userService.Delete(c.Request.Context(), uint(id)); err != nil {
 		util.HandleError(c, err)
 		return
 	}
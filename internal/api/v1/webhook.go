@@ -0,0 +1,263 @@
+package v1
+
+import (
+	"strconv"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/internal/service"
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookController 负责处理出站 webhook 相关的 HTTP 请求
+type WebhookController struct {
+	webhookService *service.WebhookService
+}
+
+func NewWebhookController() *WebhookController {
+	return &WebhookController{webhookService: service.NewWebhookService(repository.NewWebhookRepository(), service.NewAccountService(repository.NewUserRepository()))}
+}
+
+// ListWebhookEvents 可订阅的事件类型
+// @Summary      webhook 事件类型
+// @Description  文章事件只投递给文章作者的 webhook，用户事件只投递给用户本人的 webhook；全站 webhook 收到所有事件
+// @Tags         Webhook
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  util.Response{data=[]string}
+// @Router       /webhooks/events [get]
+func (ctrl *WebhookController) ListWebhookEvents(c *gin.Context) {
+	util.Success(c, model.WebhookEvents)
+}
+
+// CreateWebhook 创建 webhook
+// @Summary      创建 webhook
+// @Description  返回的签名密钥只展示一次。每次投递为 JSON POST，请求头 X-Webhook-Signature 为 sha256=HMAC-SHA256(secret, "{X-Webhook-Timestamp}.{body}")
+// @Tags         Webhook
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      dto.CreateWebhookRequest  true  "webhook 信息"
+// @Success      200      {object}  util.Response{data=dto.CreateWebhookResponse}
+// @Failure      400      {object}  util.Response  "参数错误"
+// @Failure      401      {object}  util.Response  "未授权"
+// @Failure      403      {object}  util.Response  "只有管理员可以创建全站 webhook"
+// @Router       /webhooks [post]
+func (ctrl *WebhookController) CreateWebhook(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	resp, err := ctrl.webhookService.Create(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
+
+// ListWebhooks 获取我的 webhook
+// @Summary      webhook 列表
+// @Description  获取当前用户创建的全部 webhook
+// @Tags         Webhook
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  util.Response{data=[]model.Webhook}
+// @Failure      401  {object}  util.Response  "未授权"
+// @Router       /webhooks [get]
+func (ctrl *WebhookController) ListWebhooks(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return
+	}
+
+	webhooks, err := ctrl.webhookService.List(c.Request.Context(), userID.(uint))
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, webhooks)
+}
+
+// GetWebhook 获取 webhook 详情
+// @Summary      webhook 详情
+// @Tags         Webhook
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "webhook ID"
+// @Success      200  {object}  util.Response{data=model.Webhook}
+// @Failure      401  {object}  util.Response  "未授权"
+// @Failure      404  {object}  util.Response  "webhook 不存在"
+// @Router       /webhooks/{id} [get]
+func (ctrl *WebhookController) GetWebhook(c *gin.Context) {
+	userID, id, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := ctrl.webhookService.Get(c.Request.Context(), userID, id)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, webhook)
+}
+
+// UpdateWebhook 更新 webhook
+// @Summary      更新 webhook
+// @Description  未传的字段保持不变；active=true 重新启用被自动停用的 webhook
+// @Tags         Webhook
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                        true  "webhook ID"
+// @Param        request  body      dto.UpdateWebhookRequest  true  "更新内容"
+// @Success      200      {object}  util.Response{data=model.Webhook}
+// @Failure      400      {object}  util.Response  "参数错误"
+// @Failure      401      {object}  util.Response  "未授权"
+// @Failure      404      {object}  util.Response  "webhook 不存在"
+// @Router       /webhooks/{id} [put]
+func (ctrl *WebhookController) UpdateWebhook(c *gin.Context) {
+	userID, id, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	webhook, err := ctrl.webhookService.Update(c.Request.Context(), userID, id, &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, webhook)
+}
+
+// DeleteWebhook 删除 webhook
+// @Summary      删除 webhook
+// @Description  同时删除投递记录
+// @Tags         Webhook
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "webhook ID"
+// @Success      200  {object}  util.Response  "删除成功"
+// @Failure      401  {object}  util.Response  "未授权"
+// @Failure      404  {object}  util.Response  "webhook 不存在"
+// @Router       /webhooks/{id} [delete]
+func (ctrl *WebhookController) DeleteWebhook(c *gin.Context) {
+	userID, id, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.webhookService.Delete(c.Request.Context(), userID, id); err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, nil)
+}
+
+// ListDeliveries 获取投递记录
+// @Summary      投递记录
+// @Description  分页获取 webhook 的投递记录（状态、尝试次数、响应码、响应内容），最新的在前
+// @Tags         Webhook
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                                true  "webhook ID"
+// @Param        request  body      dto.ListWebhookDeliveriesRequest  true  "分页参数"
+// @Success      200      {object}  util.Response{data=dto.WebhookDeliveryPageResponse}
+// @Failure      401      {object}  util.Response  "未授权"
+// @Failure      404      {object}  util.Response  "webhook 不存在"
+// @Router       /webhooks/{id}/deliveries/list [post]
+func (ctrl *WebhookController) ListDeliveries(c *gin.Context) {
+	userID, id, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ListWebhookDeliveriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg(err.Error()))
+		return
+	}
+
+	resp, err := ctrl.webhookService.ListDeliveries(c.Request.Context(), userID, id, &req)
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, resp)
+}
+
+// Redeliver 重新投递
+// @Summary      重新投递
+// @Description  以相同的事件 ID 和内容创建一次新的投递
+// @Tags         Webhook
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id          path      int  true  "webhook ID"
+// @Param        deliveryId  path      int  true  "投递记录 ID"
+// @Success      200         {object}  util.Response{data=model.WebhookDelivery}
+// @Failure      400         {object}  util.Response  "webhook 已停用"
+// @Failure      401         {object}  util.Response  "未授权"
+// @Failure      404         {object}  util.Response  "投递记录不存在"
+// @Router       /webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (ctrl *WebhookController) Redeliver(c *gin.Context) {
+	userID, id, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := strconv.ParseUint(c.Param("deliveryId"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的投递记录 ID"))
+		return
+	}
+
+	delivery, err := ctrl.webhookService.Redeliver(c.Request.Context(), userID, id, uint(deliveryID))
+	if err != nil {
+		util.HandleError(c, err)
+		return
+	}
+
+	util.Success(c, delivery)
+}
+
+// webhookParams 解析当前用户和路径中的 webhook ID，失败时已写入错误响应
+func webhookParams(c *gin.Context) (uint, uint, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		util.HandleError(c, util.ErrUnauthorized)
+		return 0, 0, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.HandleError(c, util.ErrInvalidParam.WithMsg("无效的 webhook ID"))
+		return 0, 0, false
+	}
+	return userID.(uint), uint(id), true
+}
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"time"
+
+	"go-blog-api/internal/model"
+)
+
+// ========== 请求结构 ==========
+
+// AdminListUsersRequest 管理员查询用户请求
+type AdminListUsersRequest struct {
+	PageRequest
+	Keyword     string     `json:"keyword"`                                                  // 用户名/邮箱
+	Status      string     `json:"status" binding:"omitempty,oneof=active suspended banned"` // 按实际状态筛选，已到期的处置视为 active
+	Role        string     `json:"role" binding:"omitempty,oneof=user admin"`
+	CreatedFrom *time.Time `json:"created_from"` // 注册时间范围（RFC 3339），包含两端
+	CreatedTo   *time.Time `json:"created_to"`
+	Deleted     bool       `json:"deleted"` // 只查已注销的用户
+}
+
+// SuspendUserRequest 暂停/封禁用户请求
+type SuspendUserRequest struct {
+	Reason string     `json:"reason" binding:"required,max=500"`
+	Until  *time.Time `json:"until"` // 到期时间（RFC 3339），为空表示永久
+}
+
+// ModerationReasonRequest 只需填写原因的处置请求（解除处置、强制重置密码、恢复账号、代登录）
+type ModerationReasonRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// SetRoleRequest 设置用户角色请求
+type SetRoleRequest struct {
+	Role   string `json:"role" binding:"required,oneof=user admin"`
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// ========== 响应结构 ==========
+
+// AdminUserResponse 管理接口中的用户信息，包含状态和处置原因
+type AdminUserResponse struct {
+	model.User
+	IsAdmin               bool       `json:"is_admin"` // 角色为 admin 或在 security.admin_user_ids 中
+	Status                string     `json:"status"`   // 实际状态，已到期的处置为 active
+	StatusReason          string     `json:"status_reason,omitempty"`
+	StatusUntil           *time.Time `json:"status_until,omitempty"`
+	PasswordResetRequired bool       `json:"password_reset_required"`
+	DeletedAt             *time.Time `json:"deleted_at,omitempty"` // 已注销用户的注销时间
+}
+
+// ImpersonationResponse 代登录响应；token 只能访问普通业务接口，每个请求都会标记代登录的管理员
+type ImpersonationResponse struct {
+	Token          string      `json:"token"`
+	ExpiresAt      time.Time   `json:"expires_at"`
+	User           *model.User `json:"user"`
+	ImpersonatorID uint        `json:"impersonator_id"`
+}
+
+// ========== Swagger 文档用的具体类型 ==========
+
+// AdminUserPageResponse 管理员用户分页响应（Swagger 用）
+type AdminUserPageResponse = PageResponse[AdminUserResponse]
+
+// ModerationActionPageResponse 处置记录分页响应（Swagger 用）
+type ModerationActionPageResponse = PageResponse[model.ModerationAction]
@@ -0,0 +1,20 @@
+package dto
+
+import "go-blog-api/internal/model"
+
+// ========== 请求结构 ==========
+
+// CreateAPIKeyRequest 创建 API Key 请求
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required,min=1,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,dive,oneof=articles:read articles:write users:read users:write"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=365"` // 为空表示永不过期
+}
+
+// ========== 响应结构 ==========
+
+// CreateAPIKeyResponse 创建 API Key 响应，完整的 key 只返回这一次
+type CreateAPIKeyResponse struct {
+	Key    string       `json:"key"`
+	APIKey model.APIKey `json:"api_key"`
+}
@@ -4,14 +4,20 @@ package dto
 
 // CreateArticleRequest 创建文章请求
 type CreateArticleRequest struct {
-	Title   string `json:"title" binding:"required,min=1,max=255"`
-	Content string `json:"content" binding:"required"`
+	Title         string   `json:"title" binding:"required,min=1,max=255"`
+	Slug          string   `json:"slug" binding:"omitempty,max=80"` // 自定义 slug，为空时由标题生成
+	Content       string   `json:"content" binding:"required"`
+	ContentFormat string   `json:"content_format" binding:"omitempty,oneof=markdown html plain"` // 默认 markdown
+	Tags          []string `json:"tags" binding:"omitempty,max=10,dive,max=50"`                  // 标签名，不存在的标签自动创建
 }
 
 // UpdateArticleRequest 更新文章请求
 type UpdateArticleRequest struct {
-	Title   string `json:"title" binding:"omitempty,min=1,max=255"`
-	Content string `json:"content" binding:"omitempty"`
+	Title         string    `json:"title" binding:"omitempty,min=1,max=255"`
+	Slug          string    `json:"slug" binding:"omitempty,max=80"` // 为空且标题变化时重新生成，旧 slug 继续可用
+	Content       string    `json:"content" binding:"omitempty"`
+	ContentFormat string    `json:"content_format" binding:"omitempty,oneof=markdown html plain"`
+	Tags          *[]string `json:"tags" binding:"omitempty,max=10,dive,max=50"` // 不传表示不修改，空数组表示清空标签
 }
 
 // ListArticlesRequest 文章列表请求（嵌入通用分页）
@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"time"
+
+	"go-blog-api/internal/model"
+)
+
+// ========== 请求结构 ==========
+
+// AuditLogQuery 管理员查询/导出审计日志请求，未填写的条件表示不限
+type AuditLogQuery struct {
+	PageRequest
+	ActorID    uint       `json:"actor_id"`
+	Action     string     `json:"action" binding:"max=50"`                            // 如 article.delete、auth.login_failed
+	TargetType string     `json:"target_type" binding:"omitempty,oneof=user article"` // 对象类型
+	TargetID   uint       `json:"target_id"`                                          // 需同时指定 target_type
+	From       *time.Time `json:"from"`                                               // 时间范围（RFC 3339），包含两端
+	To         *time.Time `json:"to"`
+}
+
+// ========== Swagger 文档用的具体类型 ==========
+
+// AuditLogPageResponse 审计日志分页响应（Swagger 用）
+type AuditLogPageResponse = PageResponse[model.AuditLog]
@@ -0,0 +1,25 @@
+package dto
+
+// ListBookmarksRequest 收藏列表请求（嵌入通用分页）
+type ListBookmarksRequest struct {
+	PageRequest
+}
+
+// CreateReadingListRequest 创建阅读清单请求
+type CreateReadingListRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=100"`
+	Description string `json:"description" binding:"omitempty,max=500"`
+	Public      bool   `json:"public"` // 公开后可通过分享链接访问
+}
+
+// UpdateReadingListRequest 更新阅读清单请求，未传的字段保持不变
+type UpdateReadingListRequest struct {
+	Name        string  `json:"name" binding:"omitempty,min=1,max=100"`
+	Description *string `json:"description" binding:"omitempty,max=500"`
+	Public      *bool   `json:"public"`
+}
+
+// ReorderReadingListRequest 调整阅读清单顺序请求
+type ReorderReadingListRequest struct {
+	ArticleIDs []uint `json:"article_ids" binding:"required,min=1,max=500"` // 按期望顺序排列的文章 ID
+}
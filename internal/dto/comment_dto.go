@@ -0,0 +1,12 @@
+package dto
+
+// CreateCommentRequest 发表评论请求
+type CreateCommentRequest struct {
+	Content  string `json:"content" binding:"required,min=1,max=5000"` // 支持 @用户名 提及
+	ParentID *uint  `json:"parent_id"`                                 // 回复某条评论时传入
+}
+
+// ListCommentsRequest 评论列表请求（嵌入通用分页）
+type ListCommentsRequest struct {
+	PageRequest
+}
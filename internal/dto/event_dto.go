@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+// EventTicketResponse 实时事件连接凭证
+type EventTicketResponse struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ArticleEventData 文章创建、更新、删除事件的数据
+type ArticleEventData struct {
+	ID        uint      `json:"id"`
+	Slug      string    `json:"slug"`
+	Title     string    `json:"title"`
+	UserID    uint      `json:"user_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// EventStreamMessage WebSocket 客户端发送的订阅变更消息
+type EventStreamMessage struct {
+	Action string   `json:"action"` // subscribe / unsubscribe
+	Topics []string `json:"topics"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+// FollowStatsResponse 关注统计
+type FollowStatsResponse struct {
+	Followers    int64 `json:"followers"`      // 粉丝数
+	Following    int64 `json:"following"`      // 关注数
+	FollowedByMe bool  `json:"followed_by_me"` // 当前用户是否已关注
+}
+
+// ListFollowsRequest 粉丝/关注列表请求（嵌入通用分页）
+type ListFollowsRequest struct {
+	PageRequest
+}
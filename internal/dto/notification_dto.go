@@ -0,0 +1,29 @@
+package dto
+
+import "go-blog-api/internal/model"
+
+// ListNotificationsRequest 通知列表请求
+type ListNotificationsRequest struct {
+	PageRequest
+	UnreadOnly bool `json:"unread_only"` // 只看未读
+}
+
+// NotificationListResponse 通知列表响应，附带未读总数
+type NotificationListResponse struct {
+	PageResponse[model.Notification]
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// UnreadCountResponse 未读通知数
+type UnreadCountResponse struct {
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// UpdateNotificationPreferenceRequest 更新通知偏好请求，未传的字段保持不变
+type UpdateNotificationPreferenceRequest struct {
+	Comment  *bool `json:"comment"`
+	Reply    *bool `json:"reply"`
+	Mention  *bool `json:"mention"`
+	Follow   *bool `json:"follow"`
+	Reaction *bool `json:"reaction"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+// ========== 响应结构 ==========
+
+// OIDCProvidersResponse 可用的第三方登录方式
+type OIDCProvidersResponse struct {
+	Providers []string `json:"providers"`
+}
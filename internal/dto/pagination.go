@@ -4,8 +4,8 @@ import "go-blog-api/internal/model"
 
 // PageRequest 通用分页请求
 type PageRequest struct {
-	Page     int `json:"page" binding:"omitempty,min=1"`
-	PageSize int `json:"page_size" binding:"omitempty,min=1,max=100"`
+	Page     int `json:"page" form:"page" binding:"omitempty,min=1"`
+	PageSize int `json:"page_size" form:"page_size" binding:"omitempty,min=1,max=100"`
 }
 
 // SetDefaults 设置默认分页参数
@@ -41,6 +41,25 @@ func NewPageResponse[T any](list []T, total int64, page, pageSize int) *PageResp
 	}
 }
 
+// CursorRequest 游标分页请求，适合持续增长的时间线
+type CursorRequest struct {
+	Cursor string `form:"cursor"` // 上一页返回的 next_cursor，第一页为空
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// SetDefaults 设置默认条数
+func (r *CursorRequest) SetDefaults() {
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+}
+
+// CursorResponse 游标分页响应（泛型）
+type CursorResponse[T any] struct {
+	List       []T    `json:"list"`
+	NextCursor string `json:"next_cursor"` // 为空表示没有更多数据
+}
+
 // ========== Swagger 文档用的具体类型别名 ==========
 // 由于 swaggo 不完全支持泛型，这里定义具体类型用于文档生成
 
@@ -52,3 +71,15 @@ type UserPageResponse = PageResponse[model.User]
 
 // CommentPageResponse 评论分页响应（Swagger 用）
 type CommentPageResponse = PageResponse[model.Comment]
+
+// MediaPageResponse 文件分页响应（Swagger 用）
+type MediaPageResponse = PageResponse[model.Media]
+
+// BookmarkPageResponse 收藏分页响应（Swagger 用）
+type BookmarkPageResponse = PageResponse[model.Bookmark]
+
+// ArticleCursorResponse 文章游标分页响应（Swagger 用）
+type ArticleCursorResponse = CursorResponse[model.Article]
+
+// WebhookDeliveryPageResponse 投递记录分页响应（Swagger 用）
+type WebhookDeliveryPageResponse = PageResponse[model.WebhookDelivery]
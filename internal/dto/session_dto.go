@@ -0,0 +1,17 @@
+package dto
+
+import "go-blog-api/internal/model"
+
+// ClientInfo 发起请求的客户端信息，由 API 层从 HTTP 请求中提取
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+// ========== 响应结构 ==========
+
+// SessionResponse 登录会话（设备）信息
+type SessionResponse struct {
+	model.Session
+	Current bool `json:"current"` // 是否为发起本次请求的会话
+}
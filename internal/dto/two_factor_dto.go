@@ -0,0 +1,39 @@
+package dto
+
+// ========== 请求结构 ==========
+
+// TwoFactorConfirmRequest 确认开启两步验证 / 重新生成恢复码请求
+type TwoFactorConfirmRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// TwoFactorDisableRequest 关闭两步验证请求
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"` // TOTP 验证码或恢复码
+}
+
+// TwoFactorLoginRequest 两步登录第二步请求
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"` // TOTP 验证码或恢复码
+}
+
+// ========== 响应结构 ==========
+
+// TwoFactorEnrollResponse 开始绑定验证器的响应
+type TwoFactorEnrollResponse struct {
+	Secret     string `json:"secret"`      // 手动输入用的 base32 密钥
+	OtpauthURI string `json:"otpauth_uri"` // 可生成二维码的 otpauth:// URI
+}
+
+// RecoveryCodesResponse 恢复码响应（明文只在生成时返回一次）
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorStatusResponse 两步验证状态
+type TwoFactorStatusResponse struct {
+	Enabled                bool  `json:"enabled"`
+	RemainingRecoveryCodes int64 `json:"remaining_recovery_codes"`
+}
@@ -23,6 +23,12 @@ type UpdateUserRequest struct {
 	Avatar string `json:"avatar" binding:"omitempty,url"`
 }
 
+// ResetPasswordRequest 管理员要求重置密码后设置新密码的请求
+type ResetPasswordRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"` // 登录时返回的重置密码 token
+	NewPassword    string `json:"new_password" binding:"required,min=6"`
+}
+
 // ListUsersRequest 用户列表请求（嵌入通用分页）
 type ListUsersRequest struct {
 	PageRequest
@@ -32,7 +38,12 @@ type ListUsersRequest struct {
 // ========== 响应结构 ==========
 
 // LoginResponse 登录响应
+// 开启两步验证的用户第一步只返回 challenge_token，需再调用 /auth/2fa/verify 换取正式 token；
+// 被管理员要求重置密码的用户同样只返回 challenge_token，需调用 /auth/password/reset 设置新密码
 type LoginResponse struct {
-	Token string     `json:"token"`
-	User  model.User `json:"user"`
+	Token                 string      `json:"token,omitempty"`
+	User                  *model.User `json:"user,omitempty"`
+	TwoFactorRequired     bool        `json:"two_factor_required"`
+	PasswordResetRequired bool        `json:"password_reset_required"`
+	ChallengeToken        string      `json:"challenge_token,omitempty"`
 }
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+
+	"go-blog-api/internal/model"
+)
+
+// ========== 请求结构 ==========
+
+// CreateWebhookRequest 创建 webhook 请求
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required,url,max=500"`
+	Description string   `json:"description" binding:"max=255"`
+	Events      []string `json:"events" binding:"required,min=1,dive,oneof=article.created article.updated article.deleted user.registered user.updated user.deleted"`
+	Global      bool     `json:"global"` // 全站 webhook，仅管理员可创建
+}
+
+// UpdateWebhookRequest 更新 webhook 请求，未传的字段保持不变；重新启用时清零连续失败次数
+type UpdateWebhookRequest struct {
+	URL         string   `json:"url" binding:"omitempty,url,max=500"`
+	Description *string  `json:"description" binding:"omitempty,max=255"`
+	Events      []string `json:"events" binding:"omitempty,min=1,dive,oneof=article.created article.updated article.deleted user.registered user.updated user.deleted"`
+	Active      *bool    `json:"active"`
+}
+
+// ListWebhookDeliveriesRequest 投递记录列表请求（嵌入通用分页）
+type ListWebhookDeliveriesRequest struct {
+	PageRequest
+}
+
+// ========== 响应结构 ==========
+
+// CreateWebhookResponse 创建 webhook 响应，签名密钥只返回这一次
+type CreateWebhookResponse struct {
+	Secret  string        `json:"secret"`
+	Webhook model.Webhook `json:"webhook"`
+}
+
+// WebhookPayload 投递给接收方的请求体
+type WebhookPayload struct {
+	ID        string    `json:"id"` // 事件 ID，重试和重新投递时不变
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// UserEventData 用户事件的数据（不含邮箱等隐私字段）
+type UserEventData struct {
+	ID        uint      `json:"id"`
+	Username  string    `json:"username"`
+	Avatar    string    `json:"avatar"`
+	CreatedAt time.Time `json:"created_at"`
+}
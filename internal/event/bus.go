@@ -0,0 +1,86 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Meta 事件的投递信息
+type Meta struct {
+	ID         uint      // outbox 中的事件 ID，重试时不变，订阅者可用于去重
+	OccurredAt time.Time // 事件写入 outbox 的时间
+	Attempt    int       // 第几次投递，从 1 开始
+}
+
+// Subscriber 一个已注册的订阅者
+type Subscriber struct {
+	Name   string // 订阅者名称，用于记录哪些订阅者已处理成功，注册后不要修改
+	handle func(ctx context.Context, meta Meta, e Event) error
+}
+
+// Handle 调用订阅者
+func (s Subscriber) Handle(ctx context.Context, meta Meta, e Event) error {
+	return s.handle(ctx, meta, e)
+}
+
+// Bus 事件总线：登记事件类型（用于从 outbox 反序列化）和订阅者，并发安全
+type Bus struct {
+	mu          sync.RWMutex
+	decoders    map[string]func([]byte) (Event, error)
+	subscribers map[string][]Subscriber
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		decoders:    make(map[string]func([]byte) (Event, error)),
+		subscribers: make(map[string][]Subscriber),
+	}
+}
+
+// Subscribe 以类型安全的方式订阅事件 T；同一订阅者名称对同一事件只能注册一次
+// 投递语义为至少一次：处理失败会重试，订阅者需要能容忍重复
+func Subscribe[T Event](b *Bus, name string, fn func(ctx context.Context, meta Meta, e T) error) {
+	var zero T
+	eventName := zero.EventName()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, s := range b.subscribers[eventName] {
+		if s.Name == name {
+			panic(fmt.Sprintf("event: subscriber %q already registered for %s", name, eventName))
+		}
+	}
+	b.decoders[eventName] = func(payload []byte) (Event, error) {
+		var e T
+		err := json.Unmarshal(payload, &e)
+		return e, err
+	}
+	b.subscribers[eventName] = append(b.subscribers[eventName], Subscriber{
+		Name: name,
+		handle: func(ctx context.Context, meta Meta, e Event) error {
+			return fn(ctx, meta, e.(T))
+		},
+	})
+}
+
+// Decode 根据事件名称反序列化 outbox 中的事件
+func (b *Bus) Decode(name string, payload []byte) (Event, error) {
+	b.mu.RLock()
+	decode, ok := b.decoders[name]
+	b.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("event: no subscriber registered for %s", name)
+	}
+	return decode(payload)
+}
+
+// Subscribers 某个事件的全部订阅者，按注册顺序
+func (b *Bus) Subscribers(name string) []Subscriber {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]Subscriber(nil), b.subscribers[name]...)
+}
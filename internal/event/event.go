@@ -0,0 +1,96 @@
+// Package event 定义领域事件和进程内的事件总线
+// 业务操作在同一个数据库事务中把事件写入 outbox 表，由后台 relay 投递给订阅者（至少一次）
+package event
+
+import "time"
+
+// Event 领域事件，Name 用于序列化后的类型识别，发布后不应再修改
+type Event interface {
+	EventName() string
+}
+
+// 事件名称
+const (
+	ArticleCreatedName = "article.created"
+	ArticleUpdatedName = "article.updated"
+	ArticleDeletedName = "article.deleted"
+	UserRegisteredName = "user.registered"
+	UserUpdatedName    = "user.updated"
+	UserDeletedName    = "user.deleted"
+	CommentCreatedName = "comment.created"
+	UserFollowedName   = "user.followed"
+	ReactionAddedName  = "reaction.added"
+)
+
+// ArticleInfo 文章事件携带的摘要信息
+type ArticleInfo struct {
+	ArticleID uint      `json:"article_id"`
+	UserID    uint      `json:"user_id"`
+	Slug      string    `json:"slug"`
+	Title     string    `json:"title"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UserInfo 用户事件携带的公开信息（不含邮箱等隐私字段）
+type UserInfo struct {
+	UserID    uint      `json:"user_id"`
+	Username  string    `json:"username"`
+	Avatar    string    `json:"avatar"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ArticleCreated 文章已发布
+type ArticleCreated struct{ ArticleInfo }
+
+// ArticleUpdated 文章已更新
+type ArticleUpdated struct{ ArticleInfo }
+
+// ArticleDeleted 文章已删除
+type ArticleDeleted struct{ ArticleInfo }
+
+// UserRegistered 新用户注册
+type UserRegistered struct{ UserInfo }
+
+// UserUpdated 用户资料已更新
+type UserUpdated struct{ UserInfo }
+
+// UserDeleted 用户已删除
+type UserDeleted struct{ UserInfo }
+
+// CommentCreated 新评论；评论内容由订阅者按 ID 查询
+type CommentCreated struct {
+	CommentID uint  `json:"comment_id"`
+	ArticleID uint  `json:"article_id"`
+	UserID    uint  `json:"user_id"`
+	ParentID  *uint `json:"parent_id,omitempty"`
+}
+
+// UserFollowed 新增关注
+type UserFollowed struct {
+	FollowerID uint `json:"follower_id"`
+	FolloweeID uint `json:"followee_id"`
+}
+
+// ReactionAdded 新增回应（文章或评论）
+type ReactionAdded struct {
+	UserID     uint   `json:"user_id"`
+	TargetType string `json:"target_type"`
+	TargetID   uint   `json:"target_id"`
+	Reaction   string `json:"reaction"`
+}
+
+func (ArticleCreated) EventName() string { return ArticleCreatedName }
+func (ArticleUpdated) EventName() string { return ArticleUpdatedName }
+func (ArticleDeleted) EventName() string { return ArticleDeletedName }
+func (UserRegistered) EventName() string { return UserRegisteredName }
+func (UserUpdated) EventName() string    { return UserUpdatedName }
+func (UserDeleted) EventName() string    { return UserDeletedName }
+func (CommentCreated) EventName() string { return CommentCreatedName }
+func (UserFollowed) EventName() string   { return UserFollowedName }
+func (ReactionAdded) EventName() string  { return ReactionAddedName }
+
+// Article 返回文章摘要，便于统一处理三种文章事件
+func (a ArticleInfo) Article() ArticleInfo { return a }
+
+// User 返回用户信息，便于统一处理三种用户事件
+func (u UserInfo) User() UserInfo { return u }
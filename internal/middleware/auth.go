@@ -1,15 +1,31 @@
 package middleware
 
 import (
+	"slices"
+	"strconv"
 	"strings"
 
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/internal/service"
 	"go-blog-api/pkg/util"
 
 	"github.com/gin-gonic/gin"
 )
 
-// JWT 认证中间件，验证 JWT Token
+// 认证方式，写入 Context 的 "authType"
+const (
+	AuthTypeToken  = "token"   // 用户登录得到的 JWT
+	AuthTypeAPIKey = "api_key" // 个人 API Key，只拥有创建时授予的权限范围
+)
+
+// JWT 认证中间件，验证 JWT Token，同时支持 "ApiKey <key>" 形式的个人 API Key
+// 被暂停、封禁或需要重置密码的账号一律拒绝；管理员代登录的请求会在响应头 X-Impersonated-By 中标记管理员 ID
 func JWT() gin.HandlerFunc {
+	apiKeyService := service.NewAPIKeyService(repository.NewAPIKeyRepository())
+	sessionService := service.NewSessionService(repository.NewSessionRepository())
+	accountService := service.NewAccountService(repository.NewUserRepository())
+
 	return func(c *gin.Context) {
 		// 1. 获取 Authorization Header
 		token := c.GetHeader("Authorization")
@@ -19,8 +35,30 @@ func JWT() gin.HandlerFunc {
 			return
 		}
 
-		// 2. 校验 Token 格式 "Bearer <token>"
+		// 2. 校验 Token 格式 "Bearer <token>" 或 "ApiKey <key>"
 		parts := strings.SplitN(token, " ", 2)
+		if len(parts) == 2 && parts[0] == "ApiKey" {
+			key, err := apiKeyService.Authenticate(c.Request.Context(), parts[1])
+			if err != nil {
+				util.HandleError(c, err)
+				c.Abort()
+				return
+			}
+			role, err := accountService.Check(c.Request.Context(), key.UserID)
+			if err != nil {
+				util.HandleError(c, err)
+				c.Abort()
+				return
+			}
+
+			c.Set("userID", key.UserID)
+			c.Set("role", role)
+			c.Set("username", key.User.Username)
+			c.Set("authType", AuthTypeAPIKey)
+			c.Set("scopes", key.ScopeList())
+			c.Next()
+			return
+		}
 		if len(parts) != 2 || parts[0] != "Bearer" {
 			util.HandleError(c, util.ErrUnauthorized.WithMsg("Token 格式错误"))
 			c.Abort()
@@ -35,9 +73,139 @@ func JWT() gin.HandlerFunc {
 			return
 		}
 
-		// 4. 将用户信息存入 Context
+		// 4. 校验会话是否已被吊销（结果有缓存）
+		if !sessionService.Validate(c.Request.Context(), claims.SessionID, claims.UserID) {
+			util.HandleError(c, util.ErrTokenExpired.WithMsg("会话已失效，请重新登录"))
+			c.Abort()
+			return
+		}
+
+		// 5. 校验账号状态（结果有缓存）；代登录时管理员须仍是管理员
+		role, err := accountService.Check(c.Request.Context(), claims.UserID)
+		if err != nil {
+			util.HandleError(c, err)
+			c.Abort()
+			return
+		}
+		if claims.ImpersonatorID != 0 {
+			if !accountService.IsAdmin(c.Request.Context(), claims.ImpersonatorID) {
+				util.HandleError(c, util.ErrTokenExpired.WithMsg("代登录已失效"))
+				c.Abort()
+				return
+			}
+			c.Set("impersonatorID", claims.ImpersonatorID)
+			c.Header("X-Impersonated-By", strconv.FormatUint(uint64(claims.ImpersonatorID), 10))
+			// 审计日志记录实际操作的管理员
+			meta := service.RequestMetaFrom(c.Request.Context())
+			meta.ImpersonatorID = claims.ImpersonatorID
+			c.Request = c.Request.WithContext(service.WithRequestMeta(c.Request.Context(), meta))
+		}
+
+		// 6. 将用户信息存入 Context
 		c.Set("userID", claims.UserID)
 		c.Set("username", claims.Username)
+		c.Set("sessionID", claims.SessionID)
+		c.Set("authType", AuthTypeToken)
+		c.Set("role", role)
+		c.Next()
+	}
+}
+
+// RequireScope 限制 API Key 访问的权限范围，登录 token 不受限制；需挂在 JWT() 之后
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("authType") != AuthTypeAPIKey {
+			c.Next()
+			return
+		}
+
+		if !slices.Contains(c.GetStringSlice("scopes"), scope) {
+			util.HandleError(c, util.ErrInsufficientScope.WithMsg("API Key 缺少权限: "+scope))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireUserToken 只允许登录 token 访问（如管理 API Key、两步验证等敏感操作）；需挂在 JWT() 之后
+func RequireUserToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("authType") != AuthTypeToken {
+			util.HandleError(c, util.ErrForbidden.WithMsg("该操作不支持 API Key 访问"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAdmin 只允许管理员通过登录 token 访问，代登录的 token 不可用；需挂在 JWT() 之后
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("authType") != AuthTypeToken || c.GetUint("impersonatorID") != 0 {
+			util.HandleError(c, util.ErrForbidden.WithMsg("管理接口只允许管理员本人登录访问"))
+			c.Abort()
+			return
+		}
+		if c.GetString("role") != model.RoleAdmin {
+			util.HandleError(c, util.ErrForbidden.WithMsg("需要管理员权限"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// DenyImpersonation 禁止代登录的 token 执行账号安全相关的操作（API Key、会话、两步验证、注销账号等）；需挂在 JWT() 之后
+func DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetUint("impersonatorID") != 0 {
+			util.HandleError(c, util.ErrForbidden.WithMsg("代登录时不能执行该操作"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// StreamAuth 实时事件连接的认证：浏览器的 EventSource 和 WebSocket 无法设置请求头，
+// 支持通过查询参数 ticket 携带连接凭证，未携带时按 JWT() 校验
+func StreamAuth() gin.HandlerFunc {
+	jwt := JWT()
+	eventService := service.NewEventService()
+	sessionService := service.NewSessionService(repository.NewSessionRepository())
+	accountService := service.NewAccountService(repository.NewUserRepository())
+
+	return func(c *gin.Context) {
+		ticket := c.Query("ticket")
+		if ticket == "" {
+			jwt(c)
+			return
+		}
+
+		userID, sessionID, err := eventService.VerifyTicket(ticket)
+		if err != nil {
+			util.HandleError(c, err)
+			c.Abort()
+			return
+		}
+		if !sessionService.Validate(c.Request.Context(), sessionID, userID) {
+			util.HandleError(c, util.ErrTokenExpired.WithMsg("会话已失效，请重新登录"))
+			c.Abort()
+			return
+		}
+		role, err := accountService.Check(c.Request.Context(), userID)
+		if err != nil {
+			util.HandleError(c, err)
+			c.Abort()
+			return
+		}
+
+		c.Set("userID", userID)
+		c.Set("role", role)
+		c.Set("sessionID", sessionID)
+		c.Set("authType", AuthTypeToken)
 		c.Next()
 	}
 }
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CachePolicy GET 接口的 HTTP 缓存策略
+type CachePolicy struct {
+	// CacheControl Cache-Control 响应头，如 "public, max-age=60"、"private, no-cache"
+	CacheControl string
+	// Weak 使用弱 ETag，适用于列表等聚合响应：内容变化时 ETag 仍会改变，但只表示语义等价，不保证逐字节一致
+	Weak bool
+	// VaryAuth 响应随登录用户变化（如当前用户的回应状态），共享缓存需按 Authorization 区分
+	VaryAuth bool
+	// NoLastModified 不输出 Last-Modified，只使用 ETag：响应中包含回应数、评论数等
+	// 不会更新 UpdatedAt 的数据时，按 If-Modified-Since 返回 304 会让客户端拿到过期的计数
+	NoLastModified bool
+}
+
+// lastModifier 可提供最后修改时间的响应数据（model 均通过 BaseModel 实现）
+type lastModifier interface {
+	LastModified() time.Time
+}
+
+// Conditional 为 util.Success 写出的 GET 响应设置 ETag、Last-Modified 和 Cache-Control，
+// 并按 If-None-Match / If-Modified-Since 返回 304；错误响应原样输出，不设置缓存头
+// ETag 由响应体计算；Last-Modified 仅在响应数据是单个 model 且未设置 NoLastModified 时取其 UpdatedAt，列表响应只使用 ETag
+func Conditional(policy CachePolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		data, ok := util.ResponseData(c)
+		if !ok || w.status != http.StatusOK {
+			w.flush()
+			return
+		}
+
+		sum := sha256.Sum256(w.body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		if policy.Weak {
+			etag = "W/" + etag
+		}
+		var lastModified time.Time
+		if m, ok := data.(lastModifier); ok && !policy.NoLastModified {
+			lastModified = m.LastModified()
+		}
+
+		if policy.CacheControl != "" {
+			c.Header("Cache-Control", policy.CacheControl)
+		}
+		if policy.VaryAuth {
+			c.Writer.Header().Add("Vary", "Authorization")
+		}
+		if util.CheckNotModified(c, etag, lastModified) {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.WriteHeaderNow()
+			return
+		}
+		w.flush()
+	}
+}
+
+// bufferedWriter 缓存 handler 写出的状态码和响应体，由中间件处理后再写到底层 writer（条件请求、幂等请求）
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.body.Len() > 0
+}
+
+// flush 把缓存的状态码和响应体写到底层 writer
+func (w *bufferedWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(w.body.Bytes())
+}
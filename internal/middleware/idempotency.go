@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+
+	"go-blog-api/internal/repository"
+	"go-blog-api/internal/service"
+	"go-blog-api/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	idempotencyHeader       = "Idempotency-Key"
+	maxIdempotencyKeyLength = 255
+)
+
+// Idempotency 处理 Idempotency-Key 请求头，需挂载在 JWT 之后：同一用户使用相同 key 的重试直接返回首次响应，
+// 首次请求仍在处理中时返回 409，key 被用于不同的请求（方法、路径或请求体不同）时返回 422；未携带该请求头时不做处理
+// 只保存 5xx 以外的响应，服务端错误后的重试会重新执行
+func Idempotency() gin.HandlerFunc {
+	svc := service.NewIdempotencyService(repository.NewIdempotencyRepository())
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			util.HandleError(c, util.ErrInvalidParam.WithMsg("Idempotency-Key 过长"))
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			util.HandleError(c, util.ErrBadRequest)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		h := sha256.New()
+		io.WriteString(h, c.Request.Method+" "+c.Request.URL.RequestURI()+"\n")
+		h.Write(body)
+
+		ctx := c.Request.Context()
+		record, err := svc.Begin(ctx, c.GetUint("userID"), key, hex.EncodeToString(h.Sum(nil)))
+		if err != nil {
+			util.HandleError(c, err)
+			c.Abort()
+			return
+		}
+		if record.Completed() {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(record.StatusCode, record.ContentType, record.Body)
+			c.Abort()
+			return
+		}
+
+		// 结果的保存不随请求取消而跳过：客户端断开后正是需要重试的场景
+		saveCtx := context.WithoutCancel(ctx)
+		w := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = w
+		done := false
+		defer func() {
+			// handler panic 时恢复原 writer，让 Recovery 能写出 500，并释放 key
+			c.Writer = w.ResponseWriter
+			if !done {
+				if err := svc.Release(saveCtx, record); err != nil {
+					log.Printf("release idempotency key %d failed: %v", record.ID, err)
+				}
+			}
+		}()
+
+		c.Next()
+
+		if w.status >= http.StatusInternalServerError {
+			if err := svc.Release(saveCtx, record); err != nil {
+				log.Printf("release idempotency key %d failed: %v", record.ID, err)
+			}
+		} else if err := svc.Complete(saveCtx, record, w.status, w.Header().Get("Content-Type"), w.body.Bytes()); err != nil {
+			log.Printf("save idempotency key %d failed: %v", record.ID, err)
+		}
+		done = true
+		w.flush()
+	}
+}
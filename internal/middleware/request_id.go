@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"go-blog-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader 请求 ID 的请求头和响应头
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID 沿用上游（网关、反向代理）传入的请求 ID 时只接受这些字符，避免写入日志的内容被伪造
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// RequestID 为每个请求分配请求 ID（上游已传入合法的 X-Request-ID 时沿用），写入响应头，
+// 并把请求 ID、客户端 IP 和 User-Agent 放入请求的 context，供审计日志使用
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			b := make([]byte, 16)
+			_, _ = rand.Read(b)
+			requestID = hex.EncodeToString(b)
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		ctx := service.WithRequestMeta(c.Request.Context(), service.RequestMeta{
+			RequestID: requestID,
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
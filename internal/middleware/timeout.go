@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"go-blog-api/pkg/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueryTimeout 为请求的 context 设置超时，service 和 repository 使用该 context 查询数据库，超时后查询随之取消
+// 时限取 database.query_timeout_seconds，可通过 database.route_timeouts 按路由覆盖；长连接接口不要挂载
+func QueryTimeout() gin.HandlerFunc {
+	cfg := config.AppConfig.Database
+	routes := make(map[string]time.Duration, len(cfg.RouteTimeouts))
+	for _, rt := range cfg.RouteTimeouts {
+		routes[rt.Route] = time.Duration(rt.Seconds) * time.Second
+	}
+	defaultTimeout := time.Duration(cfg.QueryTimeoutSeconds) * time.Second
+
+	return func(c *gin.Context) {
+		timeout, ok := routes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			timeout = defaultTimeout
+		}
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// API Key 可授予的权限范围
+const (
+	ScopeArticlesRead  = "articles:read"
+	ScopeArticlesWrite = "articles:write"
+	ScopeUsersRead     = "users:read"
+	ScopeUsersWrite    = "users:write"
+)
+
+// APIKeyScopes 全部合法的权限范围
+var APIKeyScopes = []string{ScopeArticlesRead, ScopeArticlesWrite, ScopeUsersRead, ScopeUsersWrite}
+
+// APIKey 用户个人 API Key，供脚本和第三方集成使用
+// 完整的 key 只在创建时返回一次，数据库中只保存前缀（用于查找）和密钥哈希
+type APIKey struct {
+	BaseModel
+	UserID     uint       `gorm:"index;not null" json:"user_id"`
+	User       *User      `gorm:"foreignKey:UserID" json:"-"`
+	Name       string     `gorm:"type:varchar(100);not null" json:"name"`
+	Prefix     string     `gorm:"type:varchar(16);uniqueIndex;not null" json:"prefix"`
+	SecretHash string     `gorm:"type:char(64);not null" json:"-"`
+	Scopes     string     `gorm:"type:varchar(255);not null" json:"scopes"` // 逗号分隔
+	ExpiresAt  *time.Time `json:"expires_at"`                               // 为空表示永不过期
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// ScopeList 将存储的逗号分隔字符串转为切片
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return nil
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// Expired 判断 key 是否已过期
+func (k *APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && now.After(*k.ExpiresAt)
+}
@@ -1,9 +1,18 @@
 package model
 
+import "go-blog-api/pkg/markup"
+
 type Article struct {
 	BaseModel
-	Title   string `gorm:"type:varchar(255);not null;index" json:"title"` // 标题加索引，方便搜索
-	Content string `gorm:"type:longtext" json:"content"`
-	UserID  uint   `gorm:"index;not null" json:"user_id"`           // 逻辑外键
-	User    *User  `gorm:"foreignKey:UserID" json:"user,omitempty"` // 关联关系
+	Title         string `gorm:"type:varchar(255);not null;index" json:"title"` // 标题加索引，方便搜索
+	Slug          string `gorm:"type:varchar(100);uniqueIndex" json:"slug"`     // URL 中使用的唯一标识，历史数据为 NULL，读取时补齐
+	Content       string `gorm:"type:longtext" json:"content"`
+	ContentFormat string `gorm:"type:varchar(16);not null;default:markdown" json:"content_format"` // markdown / html / plain
+	ContentHTML   string `gorm:"type:longtext" json:"content_html"`                                // 渲染并过滤后的 HTML，正文更新时重新生成
+	UserID        uint   `gorm:"index;not null" json:"user_id"`                                    // 逻辑外键
+	User          *User  `gorm:"foreignKey:UserID" json:"user,omitempty"`                          // 关联关系
+	Tags          []Tag  `gorm:"many2many:article_tags" json:"tags,omitempty"`                     // 标签，关联表为 article_tags
+
+	TOC       []markup.TOCItem `gorm:"-" json:"toc,omitempty"` // 目录，仅详情接口返回
+	Reactions *ReactionSummary `gorm:"-" json:"reactions,omitempty"`
 }
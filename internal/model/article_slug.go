@@ -0,0 +1,8 @@
+package model
+
+// ArticleSlugRedirect 文章的历史 slug，标题或 slug 修改后旧地址通过它跳转到当前 slug
+type ArticleSlugRedirect struct {
+	BaseModel
+	Slug      string `gorm:"type:varchar(100);uniqueIndex;not null" json:"slug"`
+	ArticleID uint   `gorm:"index;not null" json:"article_id"`
+}
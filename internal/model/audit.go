@@ -0,0 +1,41 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 审计动作
+const (
+	AuditLogin         = "auth.login"
+	AuditLoginFailed   = "auth.login_failed"
+	AuditPasswordReset = "user.password_reset"
+	AuditUserUpdate    = "user.update"
+	AuditUserDelete    = "user.delete"
+	AuditArticleCreate = "article.create"
+	AuditArticleUpdate = "article.update"
+	AuditArticleDelete = "article.delete"
+	AuditAdminPrefix   = "admin." // 管理员处置，后接处置类型，如 admin.ban
+)
+
+// 审计对象类型
+const (
+	AuditTargetUser    = "user"
+	AuditTargetArticle = "article"
+)
+
+// AuditLog 安全和内容相关操作的审计日志，只追加不修改
+type AuditLog struct {
+	ID             uint            `gorm:"primaryKey" json:"id"`
+	CreatedAt      time.Time       `gorm:"index" json:"created_at"`
+	ActorID        uint            `gorm:"index;not null" json:"actor_id"`                      // 操作者，0 表示未知（如用户名不存在的登录失败）
+	ImpersonatorID uint            `gorm:"not null;default:0" json:"impersonator_id,omitempty"` // 代登录时实际操作的管理员
+	Action         string          `gorm:"type:varchar(50);not null;index" json:"action"`
+	TargetType     string          `gorm:"type:varchar(30);not null;index:idx_audit_target" json:"target_type"`
+	TargetID       uint            `gorm:"not null;index:idx_audit_target" json:"target_id"`
+	Changes        json.RawMessage `gorm:"type:json" json:"changes,omitempty" swaggertype:"object"` // 变更前后的字段：{"字段": {"from": 旧值, "to": 新值}}
+	Detail         string          `gorm:"type:varchar(500)" json:"detail,omitempty"`               // 附加信息，如登录方式和失败原因
+	IP             string          `gorm:"type:varchar(64)" json:"ip"`
+	UserAgent      string          `gorm:"type:varchar(255)" json:"user_agent"`
+	RequestID      string          `gorm:"type:varchar(64);index" json:"request_id"`
+}
@@ -14,3 +14,8 @@ type BaseModel struct {
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // 软删除字段,前端不可见
 }
+
+// LastModified 资源的最后修改时间，用于 Last-Modified 响应头
+func (m BaseModel) LastModified() time.Time {
+	return m.UpdatedAt
+}
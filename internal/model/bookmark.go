@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// Bookmark 用户收藏的文章；文章被删除时收藏随之移除
+type Bookmark struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_bookmark_user_article" json:"user_id"`
+	ArticleID uint      `gorm:"not null;uniqueIndex:idx_bookmark_user_article;index" json:"article_id"`
+	Article   *Article  `gorm:"foreignKey:ArticleID" json:"article,omitempty"`
+}
+
+// ReadingList 阅读清单（文章集合），公开后可通过分享链接访问
+type ReadingList struct {
+	BaseModel
+	UserID      uint              `gorm:"index;not null" json:"user_id"`
+	Name        string            `gorm:"type:varchar(100);not null" json:"name"`
+	Description string            `gorm:"type:varchar(500)" json:"description"`
+	Public      bool              `gorm:"not null;default:false" json:"public"`
+	ShareToken  string            `gorm:"type:char(32);uniqueIndex;not null" json:"share_token,omitempty"`
+	ItemCount   int64             `gorm:"-" json:"item_count"`
+	Items       []ReadingListItem `gorm:"foreignKey:ListID" json:"items,omitempty"`
+}
+
+// ReadingListItem 阅读清单中的文章，按 Position 升序排列
+type ReadingListItem struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	ListID    uint      `gorm:"not null;uniqueIndex:idx_list_article" json:"list_id"`
+	ArticleID uint      `gorm:"not null;uniqueIndex:idx_list_article;index" json:"article_id"`
+	Position  int       `gorm:"not null;default:0" json:"position"`
+	Article   *Article  `gorm:"foreignKey:ArticleID" json:"article,omitempty"`
+}
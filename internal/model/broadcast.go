@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// 广播消息类型
+const (
+	BroadcastRealtime       = "realtime"           // 实时推送（SSE / WebSocket）
+	BroadcastSitemapExpired = "sitemap.invalidate" // 站点地图缓存失效
+)
+
+// BroadcastMessage 需要每个实例各自处理一次的消息（进程内的实时推送和缓存失效），
+// 各实例按 ID 轮询新消息，处理完不做标记，保留一段时间后删除
+type BroadcastMessage struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	Kind      string    `gorm:"type:varchar(50);not null" json:"kind"`
+	Payload   string    `gorm:"type:mediumtext;not null" json:"payload"`
+}
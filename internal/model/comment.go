@@ -5,4 +5,8 @@ type Comment struct {
 	Content   string `gorm:"type:text;not null" json:"content"`
 	ArticleID uint   `gorm:"index;not null" json:"article_id"`
 	UserID    uint   `gorm:"index;not null" json:"user_id"`
+	User      *User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	ParentID  *uint  `gorm:"index" json:"parent_id"` // 回复的评论，为空表示直接评论文章
+
+	Reactions *ReactionSummary `gorm:"-" json:"reactions,omitempty"`
 }
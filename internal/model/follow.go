@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// Follow 关注关系：FollowerID 关注了 FolloweeID
+type Follow struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	FollowerID uint      `gorm:"not null;uniqueIndex:idx_follow_pair,priority:1" json:"follower_id"`
+	FolloweeID uint      `gorm:"not null;uniqueIndex:idx_follow_pair,priority:2;index" json:"followee_id"`
+}
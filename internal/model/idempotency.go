@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// IdempotencyKey 幂等请求记录：同一用户的同一 Idempotency-Key 只执行一次，保存首次响应供重试时直接返回
+// 记录本身也是处理中的锁：StatusCode 为 0 时表示首个请求仍在处理，LockToken 标识当前持有执行权的请求
+type IdempotencyKey struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_idempotency_user_key,priority:1" json:"user_id"`
+	Key         string    `gorm:"column:idempotency_key;type:varchar(255);not null;uniqueIndex:idx_idempotency_user_key,priority:2" json:"key"`
+	RequestHash string    `gorm:"type:char(64);not null" json:"-"`                // 方法、路径和请求体的 SHA-256，key 被用于不同请求时拒绝
+	StatusCode  int       `gorm:"not null;default:0" json:"status_code"`          // 首次响应的状态码，0 表示处理中
+	ContentType string    `gorm:"type:varchar(100);not null;default:''" json:"-"` // 首次响应的 Content-Type
+	Body        []byte    `gorm:"type:mediumblob" json:"-"`                       // 首次响应的响应体
+	LockedUntil time.Time `gorm:"not null" json:"locked_until"`                   // 处理中的锁到期时间，到期后视为处理中断
+	LockToken   string    `gorm:"type:char(32);not null;default:''" json:"-"`     // 获得执行权时生成，被接管后保存或释放不再生效
+	ExpiresAt   time.Time `gorm:"not null;index" json:"expires_at"`
+}
+
+// Completed 首个请求是否已处理完成
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
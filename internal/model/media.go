@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// 上传文件的用途
+const (
+	MediaKindAvatar     = "avatar"     // 用户头像，公开访问
+	MediaKindAttachment = "attachment" // 文章附件 / 插图，通过签名地址访问
+)
+
+// 图片处理状态，非图片文件直接为 ready
+const (
+	MediaStatusPending = "pending"
+	MediaStatusReady   = "ready"
+	MediaStatusFailed  = "failed"
+)
+
+// Media 用户上传的文件，实际内容存放在 BlobStore 中
+// 相同内容（按 SHA-256）只存储一份，多条记录可以引用同一个 StorageKey
+type Media struct {
+	BaseModel
+	UserID       uint           `gorm:"index;not null" json:"user_id"`
+	Kind         string         `gorm:"type:varchar(20);not null" json:"kind"`
+	StorageKey   string         `gorm:"type:varchar(255);not null" json:"-"`
+	ContentHash  string         `gorm:"type:char(64);index;not null" json:"content_hash"`
+	MimeType     string         `gorm:"type:varchar(100);not null" json:"mime_type"`
+	Size         int64          `gorm:"not null" json:"size"`
+	OriginalName string         `gorm:"type:varchar(255)" json:"original_name"`
+	Status       string         `gorm:"type:varchar(20);index;not null;default:ready" json:"status"`
+	Width        int            `json:"width,omitempty"`             // 图片宽度（已按 EXIF 方向校正）
+	Height       int            `json:"height,omitempty"`            // 图片高度
+	Orientation  int            `gorm:"not null;default:1" json:"-"` // 早期上传记录的 EXIF 方向；现在上传时已把旋转写入原图，固定为 1
+	Variants     []MediaVariant `gorm:"foreignKey:MediaID" json:"variants,omitempty"`
+	URL          string         `gorm:"-" json:"url"` // 下载地址（附件为带有效期的签名地址），不入库
+}
+
+// MediaBlob 存储对象的锁行：上传引用某个存储对象、删除最后一个引用时都先锁住这一行，
+// 避免删除存储对象的同时另一次上传因对象已存在而跳过写入
+type MediaBlob struct {
+	StorageKey string    `gorm:"primaryKey;type:varchar(255)"`
+	LockedAt   time.Time `gorm:"not null"`
+}
+
+// MediaVariant 图片的派生尺寸（缩略图、中图、大图、正方形头像），由后台异步生成
+type MediaVariant struct {
+	BaseModel
+	MediaID    uint   `gorm:"index;not null" json:"-"`
+	Name       string `gorm:"type:varchar(30);not null" json:"name"`
+	StorageKey string `gorm:"type:varchar(255);not null" json:"-"`
+	MimeType   string `gorm:"type:varchar(100);not null" json:"mime_type"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	Size       int64  `json:"size"`
+	URL        string `gorm:"-" json:"url"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// 管理员处置类型
+const (
+	ModerationSuspend       = "suspend"
+	ModerationBan           = "ban"
+	ModerationUnsuspend     = "unsuspend"
+	ModerationPasswordReset = "force_password_reset"
+	ModerationRestore       = "restore"
+	ModerationImpersonate   = "impersonate"
+	ModerationSetRole       = "set_role"
+)
+
+// ModerationAction 管理员对用户的处置记录，只追加不修改
+type ModerationAction struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time  `gorm:"index" json:"created_at"`
+	UserID    uint       `gorm:"index;not null" json:"user_id"`  // 被处置的用户
+	AdminID   uint       `gorm:"index;not null" json:"admin_id"` // 执行处置的管理员
+	Action    string     `gorm:"type:varchar(30);not null" json:"action"`
+	Reason    string     `gorm:"type:varchar(500);not null" json:"reason"`
+	Detail    string     `gorm:"type:varchar(255)" json:"detail,omitempty"` // 附加信息，如新角色、代登录会话
+	Until     *time.Time `json:"until,omitempty"`                           // 暂停/封禁的到期时间
+}
@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// 通知类型
+const (
+	NotificationComment  = "comment"  // 有人评论了你的文章
+	NotificationReply    = "reply"    // 有人回复了你的评论
+	NotificationMention  = "mention"  // 有人在评论中 @ 了你
+	NotificationFollow   = "follow"   // 有人关注了你
+	NotificationReaction = "reaction" // 有人回应了你的文章或评论
+)
+
+// Notification 站内通知，ReadAt 为空表示未读
+type Notification struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time  `gorm:"index" json:"created_at"`
+	UserID    uint       `gorm:"not null;index:idx_notification_user_read,priority:1;uniqueIndex:idx_notification_event_user,priority:2" json:"user_id"` // 接收者
+	EventID   *uint      `gorm:"uniqueIndex:idx_notification_event_user,priority:1" json:"-"`                                                            // 来源 outbox 事件，与接收者唯一，重复投递时不会重复通知
+	ActorID   uint       `gorm:"not null" json:"actor_id"`                                                                                               // 触发者
+	Actor     *User      `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+	Type      string     `gorm:"type:varchar(32);not null" json:"type"`
+	ArticleID uint       `json:"article_id,omitempty"`
+	CommentID uint       `json:"comment_id,omitempty"`
+	Reaction  string     `gorm:"type:varchar(32)" json:"reaction,omitempty"` // 回应类型，仅 reaction 通知
+	ReadAt    *time.Time `gorm:"index:idx_notification_user_read,priority:2" json:"read_at"`
+}
+
+// NotificationPreference 用户的通知偏好，没有记录时全部开启
+// 布尔列不设数据库默认值：GORM 插入时会跳过零值字段，false 会被默认值覆盖
+type NotificationPreference struct {
+	UserID    uint      `gorm:"primaryKey" json:"-"`
+	UpdatedAt time.Time `json:"-"`
+	Comment   bool      `gorm:"not null" json:"comment"`
+	Reply     bool      `gorm:"not null" json:"reply"`
+	Mention   bool      `gorm:"not null" json:"mention"`
+	Follow    bool      `gorm:"not null" json:"follow"`
+	Reaction  bool      `gorm:"not null" json:"reaction"`
+}
+
+// DefaultNotificationPreference 默认偏好：全部开启
+func DefaultNotificationPreference(userID uint) *NotificationPreference {
+	return &NotificationPreference{UserID: userID, Comment: true, Reply: true, Mention: true, Follow: true, Reaction: true}
+}
+
+// Enabled 判断某类通知是否开启
+func (p *NotificationPreference) Enabled(notificationType string) bool {
+	switch notificationType {
+	case NotificationComment:
+		return p.Comment
+	case NotificationReply:
+		return p.Reply
+	case NotificationMention:
+		return p.Mention
+	case NotificationFollow:
+		return p.Follow
+	case NotificationReaction:
+		return p.Reaction
+	}
+	return false
+}
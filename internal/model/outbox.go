@@ -0,0 +1,43 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// outbox 事件状态
+const (
+	OutboxStatusPending = "pending" // 等待投递或等待重试
+	OutboxStatusDone    = "done"    // 所有订阅者都已处理成功
+	OutboxStatusFailed  = "failed"  // 重试次数用尽，需要人工处理
+)
+
+// OutboxEvent 与业务数据在同一事务中写入的领域事件，由后台 relay 投递给订阅者
+type OutboxEvent struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	Name          string     `gorm:"type:varchar(100);not null" json:"name"`
+	Payload       string     `gorm:"type:text;not null" json:"payload"`
+	Status        string     `gorm:"type:varchar(20);not null;index:idx_outbox_due,priority:1" json:"status"`
+	NextAttemptAt time.Time  `gorm:"not null;index:idx_outbox_due,priority:2" json:"next_attempt_at"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	Completed     string     `gorm:"type:varchar(500);not null;default:''" json:"completed"` // 已处理成功的订阅者，逗号分隔，重试时跳过
+	LastError     string     `gorm:"type:varchar(500)" json:"last_error"`
+	ProcessedAt   *time.Time `gorm:"index" json:"processed_at"`
+}
+
+// TableName 使用单数表名 outbox
+func (OutboxEvent) TableName() string {
+	return "outbox"
+}
+
+// CompletedSet 已处理成功的订阅者
+func (e *OutboxEvent) CompletedSet() map[string]bool {
+	done := make(map[string]bool)
+	for _, name := range strings.Split(e.Completed, ",") {
+		if name != "" {
+			done[name] = true
+		}
+	}
+	return done
+}
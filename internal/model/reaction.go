@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// 可以被回应的对象类型
+const (
+	ReactionTargetArticle = "article"
+	ReactionTargetComment = "comment"
+)
+
+// ReactionLike 点赞，始终可用；其他表情回应由配置决定
+const ReactionLike = "like"
+
+// Reaction 用户对文章或评论的一次回应，同一用户对同一对象的同一种回应只能有一条
+type Reaction struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UserID     uint      `gorm:"not null;uniqueIndex:idx_reaction_user_target,priority:4" json:"user_id"`
+	TargetType string    `gorm:"type:varchar(16);not null;uniqueIndex:idx_reaction_user_target,priority:1" json:"target_type"`
+	TargetID   uint      `gorm:"not null;uniqueIndex:idx_reaction_user_target,priority:2" json:"target_id"`
+	Type       string    `gorm:"type:varchar(32);not null;uniqueIndex:idx_reaction_user_target,priority:3" json:"type"`
+}
+
+// ReactionCount 回应计数，与 Reaction 在同一事务中增减，列表接口无需聚合查询
+type ReactionCount struct {
+	TargetType string `gorm:"type:varchar(16);primaryKey"`
+	TargetID   uint   `gorm:"primaryKey"`
+	Type       string `gorm:"type:varchar(32);primaryKey"`
+	Count      int64  `gorm:"not null;default:0"`
+}
+
+// ReactionSummary 嵌入文章/评论响应中的回应汇总
+type ReactionSummary struct {
+	Counts    map[string]int64 `json:"counts"`
+	Mine      []string         `json:"mine"`        // 当前用户做过的回应
+	LikedByMe bool             `json:"liked_by_me"` // 当前用户是否点过赞
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// RecoveryCode 两步验证恢复码，只保存哈希，每个恢复码只能使用一次
+type RecoveryCode struct {
+	BaseModel
+	UserID   uint       `gorm:"index;not null" json:"user_id"`
+	CodeHash string     `gorm:"type:char(64);uniqueIndex;not null" json:"-"`
+	UsedAt   *time.Time `json:"used_at"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// Session 登录会话，每次登录创建一条，JWT 中的 sid 声明指向 SessionID
+// 吊销会话即软删除该记录，携带对应 sid 的 token 随即失效
+type Session struct {
+	BaseModel
+	SessionID  string    `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	UserID     uint      `gorm:"index;not null" json:"user_id"`
+	UserAgent  string    `gorm:"type:varchar(255)" json:"user_agent"`
+	IP         string    `gorm:"type:varchar(64)" json:"ip"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `gorm:"index" json:"expires_at"`
+
+	ImpersonatorID uint `gorm:"index;not null;default:0" json:"impersonator_id,omitempty"` // 管理员代登录时为管理员 ID，用户可在会话列表中看到
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// Tag 文章标签，slug 由名称生成，用于标签订阅源和前端标签页地址
+type Tag struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"-"`
+	Name      string    `gorm:"type:varchar(50);not null" json:"name"`
+	Slug      string    `gorm:"type:varchar(80);uniqueIndex;not null" json:"slug"`
+
+	ArticlesChangedAt *time.Time `json:"-"` // 最近一次有文章加入或移出该标签的时间
+}
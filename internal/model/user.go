@@ -1,9 +1,48 @@
 package model
 
+import "time"
+
+// 用户角色
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin" // 可访问 /admin 接口，security.admin_user_ids 中的用户同样视为管理员
+)
+
+// 账号状态
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended" // 暂停使用：到期后自动恢复，已登录的会话保留
+	UserStatusBanned    = "banned"    // 封禁：同时吊销全部会话
+)
+
 type User struct {
 	BaseModel
 	Username string `gorm:"type:varchar(100);uniqueIndex;not null" json:"username"`
 	Password string `gorm:"type:varchar(255);not null" json:"-"` // 密码不返回给前端
 	Email    string `gorm:"type:varchar(100);uniqueIndex" json:"email"`
 	Avatar   string `gorm:"type:varchar(255)" json:"avatar"`
+
+	// 两步验证（TOTP）
+	TOTPSecret      string `gorm:"column:totp_secret;type:varchar(255)" json:"-"`                  // 加密存储的 TOTP 密钥
+	TOTPEnabled     bool   `gorm:"column:totp_enabled;not null;default:false" json:"totp_enabled"` // 是否已开启
+	TOTPLastCounter int64  `gorm:"column:totp_last_counter;not null;default:0" json:"-"`           // 最近一次使用的时间步，防止验证码重放
+	// 验证码错误计数和锁定，防止在线暴力破解；签发时间不晚于 TOTPChallengeAfter 的挑战 token 已失效
+	TOTPFailures       int        `gorm:"column:totp_failures;not null;default:0" json:"-"`
+	TOTPLockedUntil    *time.Time `gorm:"column:totp_locked_until" json:"-"`
+	TOTPChallengeAfter *time.Time `gorm:"column:totp_challenge_after" json:"-"`
+
+	// 角色与管理员处置，状态和处置原因只在管理接口中返回
+	Role                  string     `gorm:"type:varchar(20);not null;default:user;index" json:"role"`
+	Status                string     `gorm:"type:varchar(20);not null;default:active;index" json:"-"`
+	StatusReason          string     `gorm:"type:varchar(500)" json:"-"`
+	StatusUntil           *time.Time `json:"-"`                               // 暂停/封禁的到期时间，为空表示永久
+	PasswordResetRequired bool       `gorm:"not null;default:false" json:"-"` // 管理员要求重置密码，重置前不能登录
+}
+
+// EffectiveStatus 考虑到期时间后的实际状态：暂停或封禁已到期时视为正常
+func (u *User) EffectiveStatus(now time.Time) string {
+	if u.Status == "" || (u.StatusUntil != nil && !u.StatusUntil.After(now)) {
+		return UserStatusActive
+	}
+	return u.Status
 }
@@ -0,0 +1,10 @@
+package model
+
+// UserIdentity 用户绑定的第三方身份（OIDC / OAuth2），同一提供方的 subject 唯一
+type UserIdentity struct {
+	BaseModel
+	UserID   uint   `gorm:"index;not null" json:"user_id"`
+	Provider string `gorm:"type:varchar(50);not null;uniqueIndex:idx_provider_subject" json:"provider"`
+	Subject  string `gorm:"type:varchar(255);not null;uniqueIndex:idx_provider_subject" json:"subject"`
+	Email    string `gorm:"type:varchar(100)" json:"email"`
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Webhook 可订阅的事件类型
+const (
+	WebhookArticleCreated = "article.created"
+	WebhookArticleUpdated = "article.updated"
+	WebhookArticleDeleted = "article.deleted"
+	WebhookUserRegistered = "user.registered"
+	WebhookUserUpdated    = "user.updated"
+	WebhookUserDeleted    = "user.deleted"
+)
+
+// WebhookEvents 全部可订阅的事件类型
+var WebhookEvents = []string{
+	WebhookArticleCreated, WebhookArticleUpdated, WebhookArticleDeleted,
+	WebhookUserRegistered, WebhookUserUpdated, WebhookUserDeleted,
+}
+
+// 投递状态
+const (
+	DeliveryStatusPending = "pending" // 等待投递或等待重试
+	DeliveryStatusSuccess = "success"
+	DeliveryStatusFailed  = "failed" // 重试次数用尽或 webhook 已停用
+)
+
+// Webhook 用户注册的回调地址：普通用户只收到与自己相关的事件（自己的文章、自己的账号），
+// 管理员创建的全站 webhook（Global）收到所有事件
+// Active、Global 没有设置数据库默认值：GORM 插入时会跳过 false 零值而使用默认值
+type Webhook struct {
+	BaseModel
+	UserID       uint       `gorm:"index;not null" json:"user_id"`
+	URL          string     `gorm:"type:varchar(500);not null" json:"url"`
+	Description  string     `gorm:"type:varchar(255)" json:"description"`
+	Secret       string     `gorm:"type:varchar(255);not null" json:"-"` // 加密存储的签名密钥
+	Events       string     `gorm:"type:varchar(500);not null" json:"events"`
+	Global       bool       `gorm:"not null" json:"global"`
+	Active       bool       `gorm:"not null" json:"active"`
+	FailureCount int        `gorm:"not null;default:0" json:"failure_count"` // 连续失败次数，成功后清零
+	DisabledAt   *time.Time `json:"disabled_at"`                             // 因连续失败被自动停用的时间
+}
+
+// EventList 将存储的逗号分隔字符串转为切片
+func (w *Webhook) EventList() []string {
+	if w.Events == "" {
+		return nil
+	}
+	return strings.Split(w.Events, ",")
+}
+
+// Subscribes 是否订阅了某个事件
+func (w *Webhook) Subscribes(event string) bool {
+	for _, e := range w.EventList() {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery 一次事件投递（含重试）；payload 在事件发生时生成，重试和手动重新投递都发送相同内容
+type WebhookDelivery struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	WebhookID     uint       `gorm:"index;not null" json:"webhook_id"`
+	EventID       string     `gorm:"type:varchar(64);index;not null" json:"event_id"` // 同一事件投递给不同 webhook、重新投递时保持不变，接收方可用于去重
+	Event         string     `gorm:"type:varchar(50);not null" json:"event"`
+	Payload       string     `gorm:"type:mediumtext;not null" json:"payload"`
+	Status        string     `gorm:"type:varchar(20);not null;index:idx_delivery_due,priority:1" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt *time.Time `gorm:"index:idx_delivery_due,priority:2" json:"next_attempt_at"`
+	ResponseCode  int        `json:"response_code"` // 最近一次尝试的 HTTP 状态码，0 表示未收到响应
+	ResponseBody  string     `gorm:"type:text" json:"response_body"`
+	Error         string     `gorm:"type:varchar(500)" json:"error"`
+	DurationMs    int64      `json:"duration_ms"`
+	DeliveredAt   *time.Time `json:"delivered_at"`
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+type IAPIKeyRepository interface {
+	Create(ctx context.Context, key *model.APIKey) error
+	GetByPrefix(ctx context.Context, prefix string) (*model.APIKey, error)
+	ListByUserID(ctx context.Context, userID uint) ([]model.APIKey, error)
+	CountByUserID(ctx context.Context, userID uint) (int64, error)
+	Delete(ctx context.Context, id, userID uint) (bool, error)
+	TouchLastUsed(ctx context.Context, id uint, now time.Time, minInterval time.Duration) error
+}
+
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+// 确保 APIKeyRepository 实现了接口
+var _ IAPIKeyRepository = (*APIKeyRepository)(nil)
+
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{db: db.DB}
+}
+
+// Create 创建 API Key
+func (r *APIKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	return conn(ctx, r.db).Create(key).Error
+}
+
+// GetByPrefix 根据前缀查找 API Key（同时加载所属用户）
+func (r *APIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*model.APIKey, error) {
+	var key model.APIKey
+	if err := conn(ctx, r.db).Preload("User").Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListByUserID 获取用户的全部 API Key
+func (r *APIKeyRepository) ListByUserID(ctx context.Context, userID uint) ([]model.APIKey, error) {
+	var keys []model.APIKey
+	if err := conn(ctx, r.db).Where("user_id = ?", userID).Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// CountByUserID 统计用户的 API Key 数量
+func (r *APIKeyRepository) CountByUserID(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.APIKey{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// Delete 吊销 API Key，只能删除自己的 key
+func (r *APIKeyRepository) Delete(ctx context.Context, id, userID uint) (bool, error) {
+	result := conn(ctx, r.db).Where("id = ? AND user_id = ?", id, userID).Delete(&model.APIKey{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// TouchLastUsed 更新最近使用时间，minInterval 内只写一次，避免每个请求都写库
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uint, now time.Time, minInterval time.Duration) error {
+	return conn(ctx, r.db).Model(&model.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, now.Add(-minInterval)).
+		Update("last_used_at", now).Error
+}
@@ -1,19 +1,38 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
+	"time"
+
 	"go-blog-api/internal/model"
 	"go-blog-api/pkg/db"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type IArticleRepository interface {
-	Create(article *model.Article) error
-	GetByID(id uint) (*model.Article, error)
-	Update(article *model.Article) error
-	Delete(id uint) error
-	List(offset, limit int) ([]model.Article, int64, error)
-	ListByUserID(userID uint, offset, limit int) ([]model.Article, int64, error)
+	Create(ctx context.Context, article *model.Article) error
+	GetByID(ctx context.Context, id uint) (*model.Article, error)
+	GetBySlug(ctx context.Context, slug string) (*model.Article, error)
+	GetSlugRedirect(ctx context.Context, slug string) (*model.ArticleSlugRedirect, error)
+	SlugTaken(ctx context.Context, slug string, excludeID uint) (bool, error)
+	Update(ctx context.Context, article *model.Article) error
+	GetByIDForUpdate(ctx context.Context, id uint) (*model.Article, error)
+	UpdateWithSlug(ctx context.Context, article *model.Article, oldSlug string, columns []string) error
+	UpdateSlug(ctx context.Context, id uint, slug string) error
+	UpdateContentHTML(ctx context.Context, id uint, contentHTML string) error
+	Delete(ctx context.Context, id uint) error
+	DeleteByUserID(ctx context.Context, userID uint) ([]model.Article, error)
+	RestoreByUserID(ctx context.Context, userID uint, deletedSince time.Time) (int64, error)
+	ReplaceTags(ctx context.Context, article *model.Article, tags []model.Tag) error
+	List(ctx context.Context, offset, limit int) ([]model.Article, int64, error)
+	ListByUserID(ctx context.Context, userID uint, offset, limit int) ([]model.Article, int64, error)
+	ListByTag(ctx context.Context, tagID uint, offset, limit int) ([]model.Article, int64, error)
+	ListForSitemap(ctx context.Context, afterID uint, limit int) ([]model.Article, error)
+	LastModified(ctx context.Context, userID, tagID uint) (time.Time, error)
+	ListFeed(ctx context.Context, followerID, beforeID uint, limit int) ([]model.Article, error)
 }
 
 type ArticleRepository struct {
@@ -27,41 +46,170 @@ func NewArticleRepository() *ArticleRepository {
 	return &ArticleRepository{db: db.DB}
 }
 
-// Create 创建文章
-func (r *ArticleRepository) Create(article *model.Article) error {
-	return r.db.Create(article).Error
+// Create 创建文章，article.Tags 中的标签（需已存在）一并写入关联表
+func (r *ArticleRepository) Create(ctx context.Context, article *model.Article) error {
+	return conn(ctx, r.db).Create(article).Error
 }
 
 // GetByID 根据 ID 获取文章
-func (r *ArticleRepository) GetByID(id uint) (*model.Article, error) {
+func (r *ArticleRepository) GetByID(ctx context.Context, id uint) (*model.Article, error) {
+	var article model.Article
+	if err := conn(ctx, r.db).Preload("User").Preload("Tags").First(&article, id).Error; err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+// GetBySlug 根据当前 slug 获取文章
+func (r *ArticleRepository) GetBySlug(ctx context.Context, slug string) (*model.Article, error) {
 	var article model.Article
-	if err := r.db.Preload("User").First(&article, id).Error; err != nil {
+	if err := conn(ctx, r.db).Preload("User").Preload("Tags").Where("slug = ?", slug).First(&article).Error; err != nil {
 		return nil, err
 	}
 	return &article, nil
 }
 
+// GetSlugRedirect 查找历史 slug
+func (r *ArticleRepository) GetSlugRedirect(ctx context.Context, slug string) (*model.ArticleSlugRedirect, error) {
+	var redirect model.ArticleSlugRedirect
+	if err := conn(ctx, r.db).Where("slug = ?", slug).First(&redirect).Error; err != nil {
+		return nil, err
+	}
+	return &redirect, nil
+}
+
+// SlugTaken 判断 slug 是否已被其他文章占用（包括已删除文章和其他文章的历史 slug）
+func (r *ArticleRepository) SlugTaken(ctx context.Context, slug string, excludeID uint) (bool, error) {
+	var count int64
+	if err := conn(ctx, r.db).Unscoped().Model(&model.Article{}).
+		Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	err := conn(ctx, r.db).Model(&model.ArticleSlugRedirect{}).
+		Where("slug = ? AND article_id <> ?", slug, excludeID).Count(&count).Error
+	return count > 0, err
+}
+
 // Update 更新文章
-func (r *ArticleRepository) Update(article *model.Article) error {
-	return r.db.Save(article).Error
+func (r *ArticleRepository) Update(ctx context.Context, article *model.Article) error {
+	return conn(ctx, r.db).Save(article).Error
+}
+
+// GetByIDForUpdate 在事务中获取文章并加行锁，同一文章的并发修改依次执行
+func (r *ArticleRepository) GetByIDForUpdate(ctx context.Context, id uint) (*model.Article, error) {
+	var article model.Article
+	if err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Tags").First(&article, id).Error; err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+// UpdateWithSlug 只更新 columns 中的列（updated_at 总会更新），slug 变化时把旧 slug 记入跳转表
+func (r *ArticleRepository) UpdateWithSlug(ctx context.Context, article *model.Article, oldSlug string, columns []string) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if oldSlug != "" && oldSlug != article.Slug {
+			if err := tx.Create(&model.ArticleSlugRedirect{Slug: oldSlug, ArticleID: article.ID}).Error; err != nil {
+				return err
+			}
+			// 改回曾经用过的 slug 时，对应的跳转记录不再需要
+			if err := tx.Unscoped().Where("slug = ? AND article_id = ?", article.Slug, article.ID).
+				Delete(&model.ArticleSlugRedirect{}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(article).Select(append(columns, "updated_at")).Updates(article).Error
+	})
+}
+
+// UpdateSlug 仅更新 slug，不改动 updated_at（用于补齐历史数据）
+func (r *ArticleRepository) UpdateSlug(ctx context.Context, id uint, slug string) error {
+	return conn(ctx, r.db).Model(&model.Article{}).Where("id = ?", id).UpdateColumn("slug", slug).Error
+}
+
+// UpdateContentHTML 仅更新渲染缓存，不改动 updated_at
+func (r *ArticleRepository) UpdateContentHTML(ctx context.Context, id uint, contentHTML string) error {
+	return conn(ctx, r.db).Model(&model.Article{}).Where("id = ?", id).UpdateColumn("content_html", contentHTML).Error
+}
+
+// Delete 删除文章（软删除），同时移除所有用户对它的收藏和阅读清单条目
+func (r *ArticleRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("article_id = ?", id).Delete(&model.Bookmark{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("article_id = ?", id).Delete(&model.ReadingListItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Article{}, id).Error
+	})
+}
+
+// RestoreByUserID 恢复用户在 deletedSince 之后被删除的文章（注销账号时级联删除的文章），收藏和阅读清单条目无法恢复
+func (r *ArticleRepository) RestoreByUserID(ctx context.Context, userID uint, deletedSince time.Time) (int64, error) {
+	result := conn(ctx, r.db).Unscoped().Model(&model.Article{}).
+		Where("user_id = ? AND deleted_at >= ?", userID, deletedSince).Update("deleted_at", nil)
+	return result.RowsAffected, result.Error
+}
+
+// ReplaceTags 替换文章的标签（只改关联表），tags 为空表示清空
+// 新旧标签都记录文章变动时间：文章移出标签后不再出现在标签订阅源中，订阅源的修改时间不能因此回退
+func (r *ArticleRepository) ReplaceTags(ctx context.Context, article *model.Article, tags []model.Tag) error {
+	var ids []uint
+	if err := conn(ctx, r.db).Table("article_tags").Where("article_id = ?", article.ID).Pluck("tag_id", &ids).Error; err != nil {
+		return err
+	}
+	if err := conn(ctx, r.db).Model(article).Omit("Tags.*").Association("Tags").Replace(tags); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		ids = append(ids, tag.ID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Model(&model.Tag{}).Where("id IN ?", ids).Update("articles_changed_at", time.Now()).Error
 }
 
-// Delete 删除文章（软删除）
-func (r *ArticleRepository) Delete(id uint) error {
-	return r.db.Delete(&model.Article{}, id).Error
+// DeleteByUserID 删除用户的全部文章（软删除）及其收藏和阅读清单条目，返回被删除的文章（仅含事件需要的字段）
+func (r *ArticleRepository) DeleteByUserID(ctx context.Context, userID uint) ([]model.Article, error) {
+	var articles []model.Article
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id", "user_id", "slug", "title", "updated_at").
+			Where("user_id = ?", userID).Find(&articles).Error; err != nil {
+			return err
+		}
+		if len(articles) == 0 {
+			return nil
+		}
+		ids := make([]uint, len(articles))
+		for i, a := range articles {
+			ids[i] = a.ID
+		}
+		if err := tx.Where("article_id IN ?", ids).Delete(&model.Bookmark{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("article_id IN ?", ids).Delete(&model.ReadingListItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Article{}, ids).Error
+	})
+	return articles, err
 }
 
 // List 获取文章列表
-func (r *ArticleRepository) List(offset, limit int) ([]model.Article, int64, error) {
+func (r *ArticleRepository) List(ctx context.Context, offset, limit int) ([]model.Article, int64, error) {
 	var articles []model.Article
 	var total int64
 
-	if err := r.db.Model(&model.Article{}).Count(&total).Error; err != nil {
+	if err := conn(ctx, r.db).Model(&model.Article{}).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	// Preload User 信息
-	if err := r.db.Preload("User").Offset(offset).Limit(limit).Order("created_at DESC").Find(&articles).Error; err != nil {
+	// Preload User 和标签信息
+	if err := conn(ctx, r.db).Preload("User").Preload("Tags").Offset(offset).Limit(limit).Order("created_at DESC").Find(&articles).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -69,20 +217,76 @@ func (r *ArticleRepository) List(offset, limit int) ([]model.Article, int64, err
 }
 
 // ListByUserID 根据用户 ID 获取文章列表
-func (r *ArticleRepository) ListByUserID(userID uint, offset, limit int) ([]model.Article, int64, error) {
+func (r *ArticleRepository) ListByUserID(ctx context.Context, userID uint, offset, limit int) ([]model.Article, int64, error) {
 	var articles []model.Article
 	var total int64
 
-	query := r.db.Model(&model.Article{}).Where("user_id = ?", userID)
+	query := conn(ctx, r.db).Model(&model.Article{}).Where("user_id = ?", userID)
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	// Preload User 信息
-	if err := query.Preload("User").Offset(offset).Limit(limit).Order("created_at DESC").Find(&articles).Error; err != nil {
+	// Preload User 和标签信息
+	if err := query.Preload("User").Preload("Tags").Offset(offset).Limit(limit).Order("created_at DESC").Find(&articles).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return articles, total, nil
+}
+
+// ListByTag 获取带有指定标签的文章列表
+func (r *ArticleRepository) ListByTag(ctx context.Context, tagID uint, offset, limit int) ([]model.Article, int64, error) {
+	var articles []model.Article
+	var total int64
+
+	tagged := conn(ctx, r.db).Table("article_tags").Select("article_id").Where("tag_id = ?", tagID)
+	query := conn(ctx, r.db).Model(&model.Article{}).Where("id IN (?)", tagged)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Preload("User").Preload("Tags").Offset(offset).Limit(limit).Order("created_at DESC").Find(&articles).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return articles, total, nil
 }
+
+// LastModified 范围内文章最近一次新增、修改或删除的时间（包括已删除的文章），userID、tagID 为 0 表示不限
+func (r *ArticleRepository) LastModified(ctx context.Context, userID, tagID uint) (time.Time, error) {
+	query := conn(ctx, r.db).Unscoped().Model(&model.Article{})
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if tagID != 0 {
+		tagged := conn(ctx, r.db).Table("article_tags").Select("article_id").Where("tag_id = ?", tagID)
+		query = query.Where("id IN (?)", tagged)
+	}
+	var last sql.NullTime
+	err := query.Select("MAX(GREATEST(updated_at, COALESCE(deleted_at, updated_at)))").Row().Scan(&last)
+	return last.Time, err
+}
+
+// ListForSitemap 按 ID 游标分批获取文章，只查询生成站点地图需要的字段
+func (r *ArticleRepository) ListForSitemap(ctx context.Context, afterID uint, limit int) ([]model.Article, error) {
+	var articles []model.Article
+	err := conn(ctx, r.db).Select("id", "slug", "user_id", "updated_at").
+		Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&articles).Error
+	return articles, err
+}
+
+// ListFeed 获取关注作者的文章，按 ID 倒序做游标分页（beforeID 为 0 表示第一页）
+// 关注列表用子查询过滤，作者的公开资料通过一次 Preload 批量加载（不含邮箱）
+func (r *ArticleRepository) ListFeed(ctx context.Context, followerID, beforeID uint, limit int) ([]model.Article, error) {
+	followees := conn(ctx, r.db).Model(&model.Follow{}).Select("followee_id").Where("follower_id = ?", followerID)
+
+	query := conn(ctx, r.db).Scopes(articlePreview).Where("user_id IN (?)", followees)
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+
+	var articles []model.Article
+	err := query.Order("id DESC").Limit(limit).Find(&articles).Error
+	return articles, err
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+type IAuditRepository interface {
+	Create(ctx context.Context, log *model.AuditLog) error
+	List(ctx context.Context, filter AuditFilter, offset, limit int) ([]model.AuditLog, int64, error)
+	ListAfter(ctx context.Context, filter AuditFilter, afterID uint, limit int) ([]model.AuditLog, error)
+}
+
+// AuditFilter 查询审计日志的筛选条件，零值表示不限
+type AuditFilter struct {
+	ActorID    uint
+	Action     string
+	TargetType string
+	TargetID   uint
+	From       *time.Time // 时间范围，包含两端
+	To         *time.Time
+}
+
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// 确保 AuditRepository 实现了接口
+var _ IAuditRepository = (*AuditRepository)(nil)
+
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{db: db.DB}
+}
+
+// Create 追加审计日志，在事务中调用时随事务提交
+func (r *AuditRepository) Create(ctx context.Context, log *model.AuditLog) error {
+	return conn(ctx, r.db).Create(log).Error
+}
+
+// List 按条件分页查询审计日志，最新的在前
+func (r *AuditRepository) List(ctx context.Context, filter AuditFilter, offset, limit int) ([]model.AuditLog, int64, error) {
+	var logs []model.AuditLog
+	var total int64
+
+	query := r.filtered(ctx, filter)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Offset(offset).Limit(limit).Order("id DESC").Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+// ListAfter 按 ID 游标分批查询审计日志（用于导出），按时间先后排列
+func (r *AuditRepository) ListAfter(ctx context.Context, filter AuditFilter, afterID uint, limit int) ([]model.AuditLog, error) {
+	var logs []model.AuditLog
+	err := r.filtered(ctx, filter).Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&logs).Error
+	return logs, err
+}
+
+func (r *AuditRepository) filtered(ctx context.Context, filter AuditFilter) *gorm.DB {
+	query := conn(ctx, r.db).Model(&model.AuditLog{})
+	if filter.ActorID > 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID > 0 {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", *filter.To)
+	}
+	return query
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IBookmarkRepository interface {
+	Add(ctx context.Context, userID, articleID uint) (bool, error)
+	Remove(ctx context.Context, userID, articleID uint) (bool, error)
+	ListByUserID(ctx context.Context, userID uint, offset, limit int) ([]model.Bookmark, int64, error)
+}
+
+type BookmarkRepository struct {
+	db *gorm.DB
+}
+
+// 确保 BookmarkRepository 实现了接口
+var _ IBookmarkRepository = (*BookmarkRepository)(nil)
+
+func NewBookmarkRepository() *BookmarkRepository {
+	return &BookmarkRepository{db: db.DB}
+}
+
+// Add 收藏文章，已收藏时返回 false
+func (r *BookmarkRepository) Add(ctx context.Context, userID, articleID uint) (bool, error) {
+	result := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.Bookmark{UserID: userID, ArticleID: articleID})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Remove 取消收藏，未收藏时返回 false
+func (r *BookmarkRepository) Remove(ctx context.Context, userID, articleID uint) (bool, error) {
+	result := conn(ctx, r.db).Where("user_id = ? AND article_id = ?", userID, articleID).Delete(&model.Bookmark{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListByUserID 获取用户的收藏，最近收藏的在前
+func (r *BookmarkRepository) ListByUserID(ctx context.Context, userID uint, offset, limit int) ([]model.Bookmark, int64, error) {
+	var bookmarks []model.Bookmark
+	var total int64
+
+	query := conn(ctx, r.db).Model(&model.Bookmark{}).Where("user_id = ?", userID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Article", articlePreview).Offset(offset).Limit(limit).Order("id DESC").Find(&bookmarks).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return bookmarks, total, nil
+}
+
+// articlePreview 关联加载文章时只取列表展示需要的字段，不加载正文，作者只取公开资料（分享的阅读清单无需登录）
+func articlePreview(db *gorm.DB) *gorm.DB {
+	return db.Omit("content", "content_html").Preload("User", publicUser)
+}
+
+// publicUser 关联加载用户时只取公开资料
+func publicUser(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "username", "avatar", "created_at")
+}
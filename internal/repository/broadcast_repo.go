@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+type IBroadcastRepository interface {
+	Create(ctx context.Context, msg *model.BroadcastMessage) error
+	LastID(ctx context.Context) (uint, error)
+	ListAfter(ctx context.Context, afterID uint, gaps []uint, limit int) ([]model.BroadcastMessage, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type BroadcastRepository struct {
+	db *gorm.DB
+}
+
+// 确保 BroadcastRepository 实现了接口
+var _ IBroadcastRepository = (*BroadcastRepository)(nil)
+
+func NewBroadcastRepository() *BroadcastRepository {
+	return &BroadcastRepository{db: db.DB}
+}
+
+// Create 写入广播消息
+func (r *BroadcastRepository) Create(ctx context.Context, msg *model.BroadcastMessage) error {
+	return conn(ctx, r.db).Create(msg).Error
+}
+
+// LastID 当前最大的消息 ID，实例启动时从这里开始接收，没有消息时返回 0
+func (r *BroadcastRepository) LastID(ctx context.Context) (uint, error) {
+	var id *uint
+	if err := conn(ctx, r.db).Model(&model.BroadcastMessage{}).Select("MAX(id)").Scan(&id).Error; err != nil {
+		return 0, err
+	}
+	if id == nil {
+		return 0, nil
+	}
+	return *id, nil
+}
+
+// ListAfter 获取 ID 大于 afterID 的消息，以及 gaps 中之前跳过的（写入时尚未提交的）消息，按 ID 排序
+func (r *BroadcastRepository) ListAfter(ctx context.Context, afterID uint, gaps []uint, limit int) ([]model.BroadcastMessage, error) {
+	var msgs []model.BroadcastMessage
+	query := conn(ctx, r.db).Where("id > ?", afterID)
+	if len(gaps) > 0 {
+		query = conn(ctx, r.db).Where("id > ? OR id IN ?", afterID, gaps)
+	}
+	err := query.Order("id").Limit(limit).Find(&msgs).Error
+	return msgs, err
+}
+
+// DeleteBefore 清理旧消息
+func (r *BroadcastRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := conn(ctx, r.db).Where("created_at < ?", before).Delete(&model.BroadcastMessage{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/cache"
+	"go-blog-api/pkg/config"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	defaultArticleCacheTTL = time.Minute
+	articleListGenKey      = "articles:list:gen"
+)
+
+// articleLoads 合并同一 key 的并发回源查询，防止缓存失效瞬间的请求同时打到数据库
+var articleLoads singleflight.Group
+
+// articlePage 文章列表的缓存内容
+type articlePage struct {
+	Articles []model.Article `json:"articles"`
+	Total    int64           `json:"total"`
+}
+
+// CachedArticleRepository IArticleRepository 的缓存装饰器：缓存 GetByID 和 List，写操作后使相关缓存失效，其余方法直接委托
+// 缓存 key 带有保存在共享缓存中的版本号（每篇文章一个，列表共用一个），失效即切换版本：
+// 任一实例回源期间发生的写入都会让回源结果写到旧版本下，不会被再次读到
+// 事务中的读取绕过缓存；事务中的写入在提交后再失效一次，避免提交前被并发读取回填旧数据
+// 缓存的文章包含作者信息，作者资料变更最多在 TTL 后生效
+type CachedArticleRepository struct {
+	IArticleRepository
+	store cache.Store
+	ttl   time.Duration
+}
+
+// 确保 CachedArticleRepository 实现了接口
+var _ IArticleRepository = (*CachedArticleRepository)(nil)
+
+// NewCachedArticleRepository 使用全局缓存后端包装 ArticleRepository，未启用缓存时直接返回 ArticleRepository
+// 所有实例共享同一个缓存后端，任一实例的写入都会使缓存失效
+func NewCachedArticleRepository() IArticleRepository {
+	if cache.Default == nil {
+		return NewArticleRepository()
+	}
+	ttl := time.Duration(config.AppConfig.Cache.TTLSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = defaultArticleCacheTTL
+	}
+	return &CachedArticleRepository{IArticleRepository: NewArticleRepository(), store: cache.Default, ttl: ttl}
+}
+
+// GetByID 根据 ID 获取文章，优先读缓存
+func (r *CachedArticleRepository) GetByID(ctx context.Context, id uint) (*model.Article, error) {
+	if inTx(ctx) {
+		return r.IArticleRepository.GetByID(ctx, id)
+	}
+	var article model.Article
+	key := articleKey(id) + ":" + r.generation(ctx, articleGenKey(id), 2*r.ttl)
+	err := r.load(ctx, key, &article, func(ctx context.Context) (any, error) {
+		return r.IArticleRepository.GetByID(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+// List 获取文章列表，优先读缓存
+func (r *CachedArticleRepository) List(ctx context.Context, offset, limit int) ([]model.Article, int64, error) {
+	if inTx(ctx) {
+		return r.IArticleRepository.List(ctx, offset, limit)
+	}
+	key := "articles:list:" + r.generation(ctx, articleListGenKey, 0) + ":" + strconv.Itoa(offset) + ":" + strconv.Itoa(limit)
+	var page articlePage
+	err := r.load(ctx, key, &page, func(ctx context.Context) (any, error) {
+		articles, total, err := r.IArticleRepository.List(ctx, offset, limit)
+		return articlePage{Articles: articles, Total: total}, err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return page.Articles, page.Total, nil
+}
+
+// LastModified 范围内文章的最近修改时间，优先读缓存（随列表缓存一起失效）
+func (r *CachedArticleRepository) LastModified(ctx context.Context, userID, tagID uint) (time.Time, error) {
+	if inTx(ctx) {
+		return r.IArticleRepository.LastModified(ctx, userID, tagID)
+	}
+	key := "articles:lastmod:" + r.generation(ctx, articleListGenKey, 0) + ":" + strconv.FormatUint(uint64(userID), 10) + ":" + strconv.FormatUint(uint64(tagID), 10)
+	var last time.Time
+	err := r.load(ctx, key, &last, func(ctx context.Context) (any, error) {
+		return r.IArticleRepository.LastModified(ctx, userID, tagID)
+	})
+	return last, err
+}
+
+// Create 创建文章
+func (r *CachedArticleRepository) Create(ctx context.Context, article *model.Article) error {
+	if err := r.IArticleRepository.Create(ctx, article); err != nil {
+		return err
+	}
+	r.invalidate(ctx)
+	return nil
+}
+
+// Update 更新文章
+func (r *CachedArticleRepository) Update(ctx context.Context, article *model.Article) error {
+	if err := r.IArticleRepository.Update(ctx, article); err != nil {
+		return err
+	}
+	r.invalidate(ctx, article.ID)
+	return nil
+}
+
+// UpdateWithSlug 更新文章和 slug
+func (r *CachedArticleRepository) UpdateWithSlug(ctx context.Context, article *model.Article, oldSlug string, columns []string) error {
+	if err := r.IArticleRepository.UpdateWithSlug(ctx, article, oldSlug, columns); err != nil {
+		return err
+	}
+	r.invalidate(ctx, article.ID)
+	return nil
+}
+
+// UpdateSlug 仅更新 slug
+func (r *CachedArticleRepository) UpdateSlug(ctx context.Context, id uint, slug string) error {
+	if err := r.IArticleRepository.UpdateSlug(ctx, id, slug); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id)
+	return nil
+}
+
+// UpdateContentHTML 仅更新渲染缓存
+func (r *CachedArticleRepository) UpdateContentHTML(ctx context.Context, id uint, contentHTML string) error {
+	if err := r.IArticleRepository.UpdateContentHTML(ctx, id, contentHTML); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id)
+	return nil
+}
+
+// Delete 删除文章
+func (r *CachedArticleRepository) Delete(ctx context.Context, id uint) error {
+	if err := r.IArticleRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id)
+	return nil
+}
+
+// ReplaceTags 替换文章的标签
+func (r *CachedArticleRepository) ReplaceTags(ctx context.Context, article *model.Article, tags []model.Tag) error {
+	if err := r.IArticleRepository.ReplaceTags(ctx, article, tags); err != nil {
+		return err
+	}
+	r.invalidate(ctx, article.ID)
+	return nil
+}
+
+// DeleteByUserID 删除用户的全部文章
+func (r *CachedArticleRepository) DeleteByUserID(ctx context.Context, userID uint) ([]model.Article, error) {
+	articles, err := r.IArticleRepository.DeleteByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(articles))
+	for i := range articles {
+		ids[i] = articles[i].ID
+	}
+	r.invalidate(ctx, ids...)
+	return articles, nil
+}
+
+// RestoreByUserID 恢复用户被级联删除的文章
+func (r *CachedArticleRepository) RestoreByUserID(ctx context.Context, userID uint, deletedSince time.Time) (int64, error) {
+	n, err := r.IArticleRepository.RestoreByUserID(ctx, userID, deletedSince)
+	if err != nil {
+		return 0, err
+	}
+	r.invalidate(ctx)
+	return n, nil
+}
+
+// load 先读缓存，未命中时回源查询并写入缓存；同一 key 的并发未命中只回源一次
+func (r *CachedArticleRepository) load(ctx context.Context, key string, dst any, fetch func(ctx context.Context) (any, error)) error {
+	data, ok, err := r.store.Get(ctx, key)
+	if err != nil {
+		log.Printf("article cache get %s failed: %v", key, err)
+	}
+	if ok && json.Unmarshal(data, dst) == nil {
+		return nil
+	}
+
+	ch := articleLoads.DoChan(key, func() (any, error) {
+		// 回源查询不随发起请求的取消而中断（其他请求在等待结果），但仍受请求超时限制
+		loadCtx := context.WithoutCancel(ctx)
+		if deadline, ok := ctx.Deadline(); ok {
+			var cancel context.CancelFunc
+			loadCtx, cancel = context.WithDeadline(loadCtx, deadline)
+			defer cancel()
+		}
+
+		value, err := fetch(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.store.Set(loadCtx, key, data, r.ttl); err != nil {
+			log.Printf("article cache set %s failed: %v", key, err)
+		}
+		return data, nil
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return res.Err
+		}
+		return json.Unmarshal(res.Val.([]byte), dst)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// generation 读取缓存版本号；不存在（首次使用、过期或被淘汰）时生成新的版本，不复用可能残留的旧条目
+func (r *CachedArticleRepository) generation(ctx context.Context, genKey string, ttl time.Duration) string {
+	if gen, ok, err := r.store.Get(ctx, genKey); err == nil && ok {
+		return string(gen)
+	}
+	return r.bumpGeneration(ctx, genKey, ttl)
+}
+
+func (r *CachedArticleRepository) bumpGeneration(ctx context.Context, genKey string, ttl time.Duration) string {
+	gen := strconv.FormatInt(time.Now().UnixNano(), 36)
+	if err := r.store.Set(ctx, genKey, []byte(gen), ttl); err != nil {
+		log.Printf("article cache set %s failed: %v", genKey, err)
+	}
+	return gen
+}
+
+// invalidate 切换文章详情和列表缓存的版本；事务中的写入在提交后再执行一次
+func (r *CachedArticleRepository) invalidate(ctx context.Context, ids ...uint) {
+	// 失效不应因请求取消而跳过
+	ctx = context.WithoutCancel(ctx)
+	r.evict(ctx, ids)
+	if inTx(ctx) {
+		afterCommit(ctx, func() { r.evict(ctx, ids) })
+	}
+}
+
+// evict 旧版本的条目不再被访问，等待过期
+func (r *CachedArticleRepository) evict(ctx context.Context, ids []uint) {
+	for _, id := range ids {
+		// 详情版本号只需比详情条目活得久；过期后重新生成的版本同样不会命中旧条目
+		r.bumpGeneration(ctx, articleGenKey(id), 2*r.ttl)
+	}
+	r.bumpGeneration(ctx, articleListGenKey, 0)
+}
+
+func articleKey(id uint) string {
+	return "article:" + strconv.FormatUint(uint64(id), 10)
+}
+
+func articleGenKey(id uint) string {
+	return "article:gen:" + strconv.FormatUint(uint64(id), 10)
+}
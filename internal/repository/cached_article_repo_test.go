@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/cache"
+)
+
+// fakeArticleRepo 可以在回源时阻塞的文章仓库
+type fakeArticleRepo struct {
+	IArticleRepository
+	mu      sync.Mutex
+	title   string
+	reads   int
+	blockCh chan struct{} // 非 nil 时 GetByID 读取数据后等待关闭再返回
+}
+
+func (r *fakeArticleRepo) GetByID(ctx context.Context, id uint) (*model.Article, error) {
+	r.mu.Lock()
+	r.reads++
+	title, block := r.title, r.blockCh
+	r.mu.Unlock()
+	if block != nil {
+		<-block
+	}
+	return &model.Article{BaseModel: model.BaseModel{ID: id}, Title: title}, nil
+}
+
+func (r *fakeArticleRepo) Update(ctx context.Context, article *model.Article) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.title = article.Title
+	return nil
+}
+
+func newCachedPair(store cache.Store, repo *fakeArticleRepo) (*CachedArticleRepository, *CachedArticleRepository) {
+	a := &CachedArticleRepository{IArticleRepository: repo, store: store, ttl: time.Minute}
+	b := &CachedArticleRepository{IArticleRepository: repo, store: store, ttl: time.Minute}
+	return a, b
+}
+
+func TestCachedArticleInvalidationIsSharedAcrossInstances(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeArticleRepo{title: "v1"}
+	a, b := newCachedPair(cache.NewMemoryStore(0), repo)
+
+	if got, _ := a.GetByID(ctx, 1); got.Title != "v1" {
+		t.Fatalf("title = %q", got.Title)
+	}
+	if got, _ := a.GetByID(ctx, 1); got.Title != "v1" || repo.reads != 1 {
+		t.Fatalf("second read not served from cache: title=%q reads=%d", got.Title, repo.reads)
+	}
+
+	// 另一个实例写入后，本实例不再读到旧缓存
+	if err := b.Update(ctx, &model.Article{BaseModel: model.BaseModel{ID: 1}, Title: "v2"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := a.GetByID(ctx, 1); got.Title != "v2" {
+		t.Fatalf("title after update on other instance = %q, want v2", got.Title)
+	}
+}
+
+func TestCachedArticleStaleLoadIsNotServed(t *testing.T) {
+	ctx := context.Background()
+	block := make(chan struct{})
+	repo := &fakeArticleRepo{title: "v1", blockCh: block}
+	a, b := newCachedPair(cache.NewMemoryStore(0), repo)
+
+	// 实例 A 回源读到 v1 后停住
+	done := make(chan string)
+	go func() {
+		got, _ := a.GetByID(ctx, 1)
+		done <- got.Title
+	}()
+	for {
+		repo.mu.Lock()
+		reads := repo.reads
+		repo.mu.Unlock()
+		if reads == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// 实例 B 在此期间更新并失效，然后 A 的回源结果写入缓存
+	repo.mu.Lock()
+	repo.blockCh = nil
+	repo.mu.Unlock()
+	if err := b.Update(ctx, &model.Article{BaseModel: model.BaseModel{ID: 1}, Title: "v2"}); err != nil {
+		t.Fatal(err)
+	}
+	close(block)
+	if title := <-done; title != "v1" {
+		t.Fatalf("in-flight read = %q, want v1", title)
+	}
+
+	// 旧结果写在旧版本下，之后的读取（任一实例）都拿到新数据
+	for name, r := range map[string]*CachedArticleRepository{"a": a, "b": b} {
+		if got, _ := r.GetByID(ctx, 1); got.Title != "v2" {
+			t.Fatalf("instance %s served stale title %q", name, got.Title)
+		}
+	}
+}
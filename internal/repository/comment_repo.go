@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+type ICommentRepository interface {
+	Create(ctx context.Context, comment *model.Comment) error
+	GetByID(ctx context.Context, id uint) (*model.Comment, error)
+	Delete(ctx context.Context, id uint) error
+	ListByArticleID(ctx context.Context, articleID uint, offset, limit int) ([]model.Comment, int64, error)
+}
+
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+// 确保 CommentRepository 实现了接口
+var _ ICommentRepository = (*CommentRepository)(nil)
+
+func NewCommentRepository() *CommentRepository {
+	return &CommentRepository{db: db.DB}
+}
+
+// Create 创建评论
+func (r *CommentRepository) Create(ctx context.Context, comment *model.Comment) error {
+	return conn(ctx, r.db).Create(comment).Error
+}
+
+// GetByID 根据 ID 获取评论，评论者只取公开资料（评论会推送给订阅文章的任意用户）
+func (r *CommentRepository) GetByID(ctx context.Context, id uint) (*model.Comment, error) {
+	var comment model.Comment
+	if err := conn(ctx, r.db).Preload("User", publicUser).First(&comment, id).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// Delete 删除评论（软删除）
+func (r *CommentRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&model.Comment{}, id).Error
+}
+
+// ListByArticleID 获取文章的评论列表，按时间正序，评论者只取公开资料
+func (r *CommentRepository) ListByArticleID(ctx context.Context, articleID uint, offset, limit int) ([]model.Comment, int64, error) {
+	var comments []model.Comment
+	var total int64
+
+	query := conn(ctx, r.db).Model(&model.Comment{}).Where("article_id = ?", articleID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("User", publicUser).Offset(offset).Limit(limit).Order("created_at ASC").Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
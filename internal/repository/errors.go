@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// 唯一约束冲突，由 repository 从数据库错误转换而来，service 层据此返回对应的业务错误
+var (
+	ErrDuplicateUsername = errors.New("duplicate username")
+	ErrDuplicateEmail    = errors.New("duplicate email")
+	// ErrDuplicateIdempotencyKey 同一用户的 Idempotency-Key 已存在
+	ErrDuplicateIdempotencyKey = errors.New("duplicate idempotency key")
+)
+
+// mysqlDuplicateEntry MySQL 唯一键冲突错误码
+const mysqlDuplicateEntry = 1062
+
+// duplicateKey 判断 err 是否为唯一键冲突，是则返回冲突的索引名
+// MySQL 错误信息形如 "Duplicate entry 'x' for key 'users.idx_users_username'"（5.7 不带表名前缀）
+func duplicateKey(err error) (string, bool) {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != mysqlDuplicateEntry {
+		return "", false
+	}
+	key := mysqlErr.Message
+	if i := strings.LastIndex(key, " key '"); i >= 0 {
+		key = strings.TrimSuffix(key[i+len(" key '"):], "'")
+	}
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		key = key[i+1:]
+	}
+	return key, true
+}
+
+// translateUserError 把用户表的唯一键冲突转换为 ErrDuplicateUsername/ErrDuplicateEmail
+func translateUserError(err error) error {
+	switch key, _ := duplicateKey(err); key {
+	case "idx_users_username":
+		return ErrDuplicateUsername
+	case "idx_users_email":
+		return ErrDuplicateEmail
+	}
+	return err
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IFollowRepository interface {
+	Add(ctx context.Context, followerID, followeeID uint) (bool, error)
+	Remove(ctx context.Context, followerID, followeeID uint) (bool, error)
+	Exists(ctx context.Context, followerID, followeeID uint) (bool, error)
+	CountFollowers(ctx context.Context, userID uint) (int64, error)
+	CountFollowing(ctx context.Context, userID uint) (int64, error)
+	ListFollowers(ctx context.Context, userID uint, offset, limit int) ([]model.User, int64, error)
+	ListFollowing(ctx context.Context, userID uint, offset, limit int) ([]model.User, int64, error)
+}
+
+type FollowRepository struct {
+	db *gorm.DB
+}
+
+// 确保 FollowRepository 实现了接口
+var _ IFollowRepository = (*FollowRepository)(nil)
+
+func NewFollowRepository() *FollowRepository {
+	return &FollowRepository{db: db.DB}
+}
+
+// Add 关注用户，已关注时返回 false
+func (r *FollowRepository) Add(ctx context.Context, followerID, followeeID uint) (bool, error) {
+	result := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.Follow{FollowerID: followerID, FolloweeID: followeeID})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Remove 取消关注，未关注时返回 false
+func (r *FollowRepository) Remove(ctx context.Context, followerID, followeeID uint) (bool, error) {
+	result := conn(ctx, r.db).Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&model.Follow{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Exists 判断是否已关注
+func (r *FollowRepository) Exists(ctx context.Context, followerID, followeeID uint) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.Follow{}).Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Count(&count).Error
+	return count > 0, err
+}
+
+// CountFollowers 统计粉丝数
+func (r *FollowRepository) CountFollowers(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.Follow{}).Where("followee_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// CountFollowing 统计关注数
+func (r *FollowRepository) CountFollowing(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.Follow{}).Where("follower_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// ListFollowers 获取粉丝列表，最近关注的在前
+func (r *FollowRepository) ListFollowers(ctx context.Context, userID uint, offset, limit int) ([]model.User, int64, error) {
+	return r.listUsers(ctx, "follows.follower_id", "follows.followee_id", userID, offset, limit)
+}
+
+// ListFollowing 获取关注列表，最近关注的在前
+func (r *FollowRepository) ListFollowing(ctx context.Context, userID uint, offset, limit int) ([]model.User, int64, error) {
+	return r.listUsers(ctx, "follows.followee_id", "follows.follower_id", userID, offset, limit)
+}
+
+// listUsers 通过一次 JOIN 查询关系另一端的用户，避免逐个加载
+func (r *FollowRepository) listUsers(ctx context.Context, joinColumn, filterColumn string, userID uint, offset, limit int) ([]model.User, int64, error) {
+	var users []model.User
+	var total int64
+
+	query := conn(ctx, r.db).Model(&model.User{}).
+		Joins("JOIN follows ON users.id = "+joinColumn).
+		Where(filterColumn+" = ?", userID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Offset(offset).Limit(limit).Order("follows.id DESC").Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+type IIdempotencyRepository interface {
+	Create(ctx context.Context, record *model.IdempotencyKey) error
+	Get(ctx context.Context, userID uint, key string) (*model.IdempotencyKey, error)
+	TakeOver(ctx context.Context, record *model.IdempotencyKey, now time.Time) (bool, error)
+	Complete(ctx context.Context, id uint, lockToken string, statusCode int, contentType string, body []byte) (bool, error)
+	Delete(ctx context.Context, id uint, lockToken string) (bool, error)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type IdempotencyRepository struct {
+	db *gorm.DB
+}
+
+// 确保 IdempotencyRepository 实现了接口
+var _ IIdempotencyRepository = (*IdempotencyRepository)(nil)
+
+func NewIdempotencyRepository() *IdempotencyRepository {
+	return &IdempotencyRepository{db: db.DB}
+}
+
+// Create 写入处理中的记录；同一用户的 key 已存在时返回 ErrDuplicateIdempotencyKey
+func (r *IdempotencyRepository) Create(ctx context.Context, record *model.IdempotencyKey) error {
+	err := conn(ctx, r.db).Create(record).Error
+	if _, ok := duplicateKey(err); ok {
+		return ErrDuplicateIdempotencyKey
+	}
+	return err
+}
+
+// Get 获取用户的 key 对应的记录
+func (r *IdempotencyRepository) Get(ctx context.Context, userID uint, key string) (*model.IdempotencyKey, error) {
+	var record model.IdempotencyKey
+	err := conn(ctx, r.db).Where("user_id = ? AND idempotency_key = ?", userID, key).First(&record).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// TakeOver 接管已过期或锁已到期的记录，重置为 record 中的请求；多个请求同时接管时只有一个成功
+func (r *IdempotencyRepository) TakeOver(ctx context.Context, record *model.IdempotencyKey, now time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&model.IdempotencyKey{}).
+		Where("id = ? AND (expires_at <= ? OR (status_code = 0 AND locked_until <= ?))", record.ID, now, now).
+		Updates(map[string]any{
+			"request_hash": record.RequestHash,
+			"status_code":  0,
+			"content_type": "",
+			"body":         nil,
+			"locked_until": record.LockedUntil,
+			"lock_token":   record.LockToken,
+			"expires_at":   record.ExpiresAt,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// Complete 保存首次响应；记录已被其他请求接管（lock_token 不一致）时不修改并返回 false
+func (r *IdempotencyRepository) Complete(ctx context.Context, id uint, lockToken string, statusCode int, contentType string, body []byte) (bool, error) {
+	result := conn(ctx, r.db).Model(&model.IdempotencyKey{}).
+		Where("id = ? AND lock_token = ? AND status_code = 0", id, lockToken).
+		Updates(map[string]any{
+			"status_code":  statusCode,
+			"content_type": contentType,
+			"body":         body,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// Delete 删除记录，释放 key；记录已被其他请求接管时不删除并返回 false
+func (r *IdempotencyRepository) Delete(ctx context.Context, id uint, lockToken string) (bool, error) {
+	result := conn(ctx, r.db).Where("id = ? AND lock_token = ? AND status_code = 0", id, lockToken).Delete(&model.IdempotencyKey{})
+	return result.RowsAffected == 1, result.Error
+}
+
+// DeleteExpired 清理已过期的记录
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := conn(ctx, r.db).Where("expires_at <= ?", now).Delete(&model.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IMediaRepository interface {
+	Create(ctx context.Context, media *model.Media) error
+	GetByID(ctx context.Context, id uint) (*model.Media, error)
+	GetByUserAndHash(ctx context.Context, userID uint, kind, contentHash string) (*model.Media, error)
+	CountByStorageKey(ctx context.Context, storageKey string) (int64, error)
+	LockBlob(ctx context.Context, storageKey string) error
+	DeleteBlob(ctx context.Context, storageKey string) error
+	Delete(ctx context.Context, id uint) error
+	ListByUserID(ctx context.Context, userID uint, offset, limit int) ([]model.Media, int64, error)
+	ListPendingIDs(ctx context.Context, before time.Time, limit int) ([]uint, error)
+	UpdateStatus(ctx context.Context, id uint, status string) error
+	SaveProcessed(ctx context.Context, media *model.Media, variants []model.MediaVariant) error
+}
+
+type MediaRepository struct {
+	db *gorm.DB
+}
+
+// 确保 MediaRepository 实现了接口
+var _ IMediaRepository = (*MediaRepository)(nil)
+
+func NewMediaRepository() *MediaRepository {
+	return &MediaRepository{db: db.DB}
+}
+
+// Create 创建文件记录
+func (r *MediaRepository) Create(ctx context.Context, media *model.Media) error {
+	return conn(ctx, r.db).Create(media).Error
+}
+
+// GetByID 根据 ID 获取文件记录（含图片派生尺寸）
+func (r *MediaRepository) GetByID(ctx context.Context, id uint) (*model.Media, error) {
+	var media model.Media
+	if err := conn(ctx, r.db).Preload("Variants").First(&media, id).Error; err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// GetByUserAndHash 查找用户已上传过的相同内容（用于去重）
+func (r *MediaRepository) GetByUserAndHash(ctx context.Context, userID uint, kind, contentHash string) (*model.Media, error) {
+	var media model.Media
+	if err := conn(ctx, r.db).Where("user_id = ? AND kind = ? AND content_hash = ?", userID, kind, contentHash).First(&media).Error; err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// CountByStorageKey 统计仍在引用某个存储对象的记录数
+func (r *MediaRepository) CountByStorageKey(ctx context.Context, storageKey string) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.Media{}).Where("storage_key = ?", storageKey).Count(&count).Error
+	return count, err
+}
+
+// LockBlob 锁住存储对象对应的锁行（不存在时创建），直到事务结束；必须在事务中调用
+func (r *MediaRepository) LockBlob(ctx context.Context, storageKey string) error {
+	// 主键冲突时执行更新，MySQL 会对已存在的行加排他锁
+	return conn(ctx, r.db).Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"locked_at"}),
+	}).Create(&model.MediaBlob{StorageKey: storageKey, LockedAt: time.Now()}).Error
+}
+
+// DeleteBlob 存储对象删除后移除其锁行
+func (r *MediaRepository) DeleteBlob(ctx context.Context, storageKey string) error {
+	return conn(ctx, r.db).Where("storage_key = ?", storageKey).Delete(&model.MediaBlob{}).Error
+}
+
+// Delete 删除文件记录（软删除）
+func (r *MediaRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&model.Media{}, id).Error
+}
+
+// ListByUserID 获取用户上传的文件列表
+func (r *MediaRepository) ListByUserID(ctx context.Context, userID uint, offset, limit int) ([]model.Media, int64, error) {
+	var list []model.Media
+	var total int64
+
+	query := conn(ctx, r.db).Model(&model.Media{}).Where("user_id = ?", userID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Variants").Offset(offset).Limit(limit).Order("created_at DESC").Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return list, total, nil
+}
+
+// ListPendingIDs 获取等待处理的图片（用于重启后或队列溢出后补偿处理）
+func (r *MediaRepository) ListPendingIDs(ctx context.Context, before time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := conn(ctx, r.db).Model(&model.Media{}).
+		Where("status = ? AND created_at < ?", model.MediaStatusPending, before).
+		Order("id ASC").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// UpdateStatus 更新处理状态
+func (r *MediaRepository) UpdateStatus(ctx context.Context, id uint, status string) error {
+	return conn(ctx, r.db).Model(&model.Media{}).Where("id = ?", id).Update("status", status).Error
+}
+
+// SaveProcessed 保存处理结果：替换派生尺寸并更新宽高和状态
+func (r *MediaRepository) SaveProcessed(ctx context.Context, media *model.Media, variants []model.MediaVariant) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("media_id = ?", media.ID).Delete(&model.MediaVariant{}).Error; err != nil {
+			return err
+		}
+		if len(variants) > 0 {
+			if err := tx.Create(&variants).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&model.Media{}).Where("id = ?", media.ID).Updates(map[string]any{
+			"width":  media.Width,
+			"height": media.Height,
+			"status": model.MediaStatusReady,
+		}).Error
+	})
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+type IModerationRepository interface {
+	Create(ctx context.Context, action *model.ModerationAction) error
+	ListByUserID(ctx context.Context, userID uint, offset, limit int) ([]model.ModerationAction, int64, error)
+}
+
+type ModerationRepository struct {
+	db *gorm.DB
+}
+
+// 确保 ModerationRepository 实现了接口
+var _ IModerationRepository = (*ModerationRepository)(nil)
+
+func NewModerationRepository() *ModerationRepository {
+	return &ModerationRepository{db: db.DB}
+}
+
+// Create 追加处置记录
+func (r *ModerationRepository) Create(ctx context.Context, action *model.ModerationAction) error {
+	return conn(ctx, r.db).Create(action).Error
+}
+
+// ListByUserID 获取用户的处置记录，最新的在前
+func (r *ModerationRepository) ListByUserID(ctx context.Context, userID uint, offset, limit int) ([]model.ModerationAction, int64, error) {
+	var actions []model.ModerationAction
+	var total int64
+
+	query := conn(ctx, r.db).Model(&model.ModerationAction{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Offset(offset).Limit(limit).Order("id DESC").Find(&actions).Error; err != nil {
+		return nil, 0, err
+	}
+	return actions, total, nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type INotificationRepository interface {
+	CreateBatch(ctx context.Context, notifications []model.Notification) ([]model.Notification, error)
+	ExistsSince(ctx context.Context, n *model.Notification, since time.Time) (bool, error)
+	ListByUserID(ctx context.Context, userID uint, unreadOnly bool, offset, limit int) ([]model.Notification, int64, error)
+	CountUnread(ctx context.Context, userID uint) (int64, error)
+	MarkRead(ctx context.Context, id, userID uint, now time.Time) (bool, error)
+	MarkAllRead(ctx context.Context, userID uint, now time.Time) (int64, error)
+	GetPreference(ctx context.Context, userID uint) (*model.NotificationPreference, error)
+	ListPreferences(ctx context.Context, userIDs []uint) ([]model.NotificationPreference, error)
+	SavePreference(ctx context.Context, pref *model.NotificationPreference) error
+}
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+// 确保 NotificationRepository 实现了接口
+var _ INotificationRepository = (*NotificationRepository)(nil)
+
+func NewNotificationRepository() *NotificationRepository {
+	return &NotificationRepository{db: db.DB}
+}
+
+// CreateBatch 批量写入通知，同一事件和接收者已有通知时跳过，返回实际写入的通知
+func (r *NotificationRepository) CreateBatch(ctx context.Context, notifications []model.Notification) ([]model.Notification, error) {
+	created := make([]model.Notification, 0, len(notifications))
+	for i := range notifications {
+		n := notifications[i]
+		result := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&n)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected > 0 {
+			created = append(created, n)
+		}
+	}
+	return created, nil
+}
+
+// ExistsSince 接收者在 since 之后是否已收到同一触发者对同一对象的同类通知
+func (r *NotificationRepository) ExistsSince(ctx context.Context, n *model.Notification, since time.Time) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.Notification{}).
+		Where("user_id = ? AND type = ? AND actor_id = ? AND article_id = ? AND comment_id = ? AND created_at >= ?",
+			n.UserID, n.Type, n.ActorID, n.ArticleID, n.CommentID, since).
+		Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+// ListByUserID 获取用户的通知，最新的在前
+func (r *NotificationRepository) ListByUserID(ctx context.Context, userID uint, unreadOnly bool, offset, limit int) ([]model.Notification, int64, error) {
+	var notifications []model.Notification
+	var total int64
+
+	query := conn(ctx, r.db).Model(&model.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Actor", publicUser).Offset(offset).Limit(limit).Order("id DESC").Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, total, nil
+}
+
+// CountUnread 统计未读通知数
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+// MarkRead 标记单条未读通知为已读，通知不存在、不属于该用户或已读时返回 false
+func (r *NotificationRepository) MarkRead(ctx context.Context, id, userID uint, now time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&model.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", id, userID).
+		Update("read_at", now)
+	return result.RowsAffected == 1, result.Error
+}
+
+// MarkAllRead 标记用户的所有未读通知为已读，返回更新条数
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID uint, now time.Time) (int64, error) {
+	result := conn(ctx, r.db).Model(&model.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Update("read_at", now)
+	return result.RowsAffected, result.Error
+}
+
+// GetPreference 获取用户的通知偏好
+func (r *NotificationRepository) GetPreference(ctx context.Context, userID uint) (*model.NotificationPreference, error) {
+	var pref model.NotificationPreference
+	if err := conn(ctx, r.db).First(&pref, userID).Error; err != nil {
+		return nil, err
+	}
+	return &pref, nil
+}
+
+// ListPreferences 批量获取通知偏好，没有设置过的用户不在结果中
+func (r *NotificationRepository) ListPreferences(ctx context.Context, userIDs []uint) ([]model.NotificationPreference, error) {
+	var prefs []model.NotificationPreference
+	if len(userIDs) == 0 {
+		return prefs, nil
+	}
+	err := conn(ctx, r.db).Where("user_id IN ?", userIDs).Find(&prefs).Error
+	return prefs, err
+}
+
+// SavePreference 保存通知偏好
+func (r *NotificationRepository) SavePreference(ctx context.Context, pref *model.NotificationPreference) error {
+	return conn(ctx, r.db).Save(pref).Error
+}
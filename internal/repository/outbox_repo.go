@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go-blog-api/internal/event"
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+type IOutboxRepository interface {
+	Add(ctx context.Context, events ...event.Event) error
+	ListDue(ctx context.Context, now time.Time, limit int) ([]model.OutboxEvent, error)
+	Claim(ctx context.Context, id uint, now, leaseUntil time.Time) (bool, error)
+	Save(ctx context.Context, e *model.OutboxEvent) error
+	DeleteProcessedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// 确保 OutboxRepository 实现了接口
+var _ IOutboxRepository = (*OutboxRepository)(nil)
+
+func NewOutboxRepository() *OutboxRepository {
+	return &OutboxRepository{db: db.DB}
+}
+
+// Add 写入事件；需要与业务数据保持一致时应使用 Transactor.Transaction 传入的 ctx 调用
+func (r *OutboxRepository) Add(ctx context.Context, events ...event.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	now := time.Now()
+	rows := make([]model.OutboxEvent, len(events))
+	for i, e := range events {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		rows[i] = model.OutboxEvent{
+			Name:          e.EventName(),
+			Payload:       string(payload),
+			Status:        model.OutboxStatusPending,
+			NextAttemptAt: now,
+		}
+	}
+	return conn(ctx, r.db).Create(&rows).Error
+}
+
+// ListDue 获取到期待投递的事件，按写入顺序
+func (r *OutboxRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+	err := conn(ctx, r.db).Where("status = ? AND next_attempt_at <= ?", model.OutboxStatusPending, now).
+		Order("id").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// Claim 领取事件：把下次尝试时间推迟到 leaseUntil，多个实例同时扫描时只有一个能领取成功
+func (r *OutboxRepository) Claim(ctx context.Context, id uint, now, leaseUntil time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&model.OutboxEvent{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", id, model.OutboxStatusPending, now).
+		Update("next_attempt_at", leaseUntil)
+	return result.RowsAffected == 1, result.Error
+}
+
+// Save 保存投递结果
+func (r *OutboxRepository) Save(ctx context.Context, e *model.OutboxEvent) error {
+	return conn(ctx, r.db).Save(e).Error
+}
+
+// DeleteProcessedBefore 清理已处理完成的旧事件
+func (r *OutboxRepository) DeleteProcessedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := conn(ctx, r.db).Where("status = ? AND processed_at < ?", model.OutboxStatusDone, before).
+		Delete(&model.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
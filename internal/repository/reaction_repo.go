@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IReactionRepository interface {
+	Add(ctx context.Context, reaction *model.Reaction) (bool, error)
+	Remove(ctx context.Context, userID uint, targetType string, targetID uint, reactionType string) (bool, error)
+	CountsByTargets(ctx context.Context, targetType string, targetIDs []uint) ([]model.ReactionCount, error)
+	ListByUserAndTargets(ctx context.Context, userID uint, targetType string, targetIDs []uint) ([]model.Reaction, error)
+}
+
+type ReactionRepository struct {
+	db *gorm.DB
+}
+
+// 确保 ReactionRepository 实现了接口
+var _ IReactionRepository = (*ReactionRepository)(nil)
+
+func NewReactionRepository() *ReactionRepository {
+	return &ReactionRepository{db: db.DB}
+}
+
+// Add 添加回应并累加计数；已存在时不做任何修改，返回 false
+// 唯一索引保证并发重复添加只有一个成功，计数只由成功插入的事务累加
+func (r *ReactionRepository) Add(ctx context.Context, reaction *model.Reaction) (bool, error) {
+	added := false
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(reaction)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		added = true
+
+		counter := model.ReactionCount{TargetType: reaction.TargetType, TargetID: reaction.TargetID, Type: reaction.Type, Count: 1}
+		return tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.Assignments(map[string]any{"count": gorm.Expr("count + 1")}),
+		}).Create(&counter).Error
+	})
+	return added, err
+}
+
+// Remove 删除回应并扣减计数；不存在时返回 false
+func (r *ReactionRepository) Remove(ctx context.Context, userID uint, targetType string, targetID uint, reactionType string) (bool, error) {
+	removed := false
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND target_type = ? AND target_id = ? AND type = ?", userID, targetType, targetID, reactionType).
+			Delete(&model.Reaction{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		removed = true
+
+		return tx.Model(&model.ReactionCount{}).
+			Where("target_type = ? AND target_id = ? AND type = ? AND count > 0", targetType, targetID, reactionType).
+			UpdateColumn("count", gorm.Expr("count - 1")).Error
+	})
+	return removed, err
+}
+
+// CountsByTargets 批量获取多个对象的回应计数
+func (r *ReactionRepository) CountsByTargets(ctx context.Context, targetType string, targetIDs []uint) ([]model.ReactionCount, error) {
+	var counts []model.ReactionCount
+	if len(targetIDs) == 0 {
+		return counts, nil
+	}
+	err := conn(ctx, r.db).Where("target_type = ? AND target_id IN ? AND count > 0", targetType, targetIDs).Find(&counts).Error
+	return counts, err
+}
+
+// ListByUserAndTargets 批量获取用户对多个对象做过的回应
+func (r *ReactionRepository) ListByUserAndTargets(ctx context.Context, userID uint, targetType string, targetIDs []uint) ([]model.Reaction, error) {
+	var reactions []model.Reaction
+	if len(targetIDs) == 0 {
+		return reactions, nil
+	}
+	err := conn(ctx, r.db).Where("user_id = ? AND target_type = ? AND target_id IN ?", userID, targetType, targetIDs).
+		Order("id ASC").Find(&reactions).Error
+	return reactions, err
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IReadingListRepository interface {
+	Create(ctx context.Context, list *model.ReadingList) error
+	GetByID(ctx context.Context, id uint) (*model.ReadingList, error)
+	GetByShareToken(ctx context.Context, token string) (*model.ReadingList, error)
+	Update(ctx context.Context, list *model.ReadingList) error
+	Delete(ctx context.Context, id uint) error
+	ListByUserID(ctx context.Context, userID uint) ([]model.ReadingList, error)
+	CountByUserID(ctx context.Context, userID uint) (int64, error)
+	ListItems(ctx context.Context, listID uint) ([]model.ReadingListItem, error)
+	CountItems(ctx context.Context, listID uint) (int64, error)
+	AddItem(ctx context.Context, listID, articleID uint) (bool, error)
+	RemoveItem(ctx context.Context, listID, articleID uint) (bool, error)
+	Reorder(ctx context.Context, listID uint, articleIDs []uint) error
+}
+
+type ReadingListRepository struct {
+	db *gorm.DB
+}
+
+// 确保 ReadingListRepository 实现了接口
+var _ IReadingListRepository = (*ReadingListRepository)(nil)
+
+func NewReadingListRepository() *ReadingListRepository {
+	return &ReadingListRepository{db: db.DB}
+}
+
+// Create 创建阅读清单
+func (r *ReadingListRepository) Create(ctx context.Context, list *model.ReadingList) error {
+	return conn(ctx, r.db).Create(list).Error
+}
+
+// GetByID 根据 ID 获取阅读清单（不含条目）
+func (r *ReadingListRepository) GetByID(ctx context.Context, id uint) (*model.ReadingList, error) {
+	var list model.ReadingList
+	if err := conn(ctx, r.db).First(&list, id).Error; err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetByShareToken 根据分享 token 获取阅读清单
+func (r *ReadingListRepository) GetByShareToken(ctx context.Context, token string) (*model.ReadingList, error) {
+	var list model.ReadingList
+	if err := conn(ctx, r.db).Where("share_token = ?", token).First(&list).Error; err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// Update 更新阅读清单
+func (r *ReadingListRepository) Update(ctx context.Context, list *model.ReadingList) error {
+	return conn(ctx, r.db).Omit("Items").Save(list).Error
+}
+
+// Delete 删除阅读清单（软删除）及其条目
+func (r *ReadingListRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("list_id = ?", id).Delete(&model.ReadingListItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.ReadingList{}, id).Error
+	})
+}
+
+// ListByUserID 获取用户的所有阅读清单，并统计每个清单的文章数
+func (r *ReadingListRepository) ListByUserID(ctx context.Context, userID uint) ([]model.ReadingList, error) {
+	var lists []model.ReadingList
+	if err := conn(ctx, r.db).Where("user_id = ?", userID).Order("id ASC").Find(&lists).Error; err != nil {
+		return nil, err
+	}
+	if len(lists) == 0 {
+		return lists, nil
+	}
+
+	ids := make([]uint, len(lists))
+	for i := range lists {
+		ids[i] = lists[i].ID
+	}
+	var counts []struct {
+		ListID uint
+		Count  int64
+	}
+	if err := conn(ctx, r.db).Model(&model.ReadingListItem{}).Select("list_id, COUNT(*) AS count").
+		Where("list_id IN ?", ids).Group("list_id").Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]int64, len(counts))
+	for _, c := range counts {
+		byID[c.ListID] = c.Count
+	}
+	for i := range lists {
+		lists[i].ItemCount = byID[lists[i].ID]
+	}
+	return lists, nil
+}
+
+// CountByUserID 统计用户的阅读清单数量
+func (r *ReadingListRepository) CountByUserID(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.ReadingList{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// ListItems 获取清单中的文章，按排列顺序
+func (r *ReadingListRepository) ListItems(ctx context.Context, listID uint) ([]model.ReadingListItem, error) {
+	var items []model.ReadingListItem
+	err := conn(ctx, r.db).Where("list_id = ?", listID).Preload("Article", articlePreview).
+		Order("position ASC, id ASC").Find(&items).Error
+	return items, err
+}
+
+// CountItems 统计清单中的文章数
+func (r *ReadingListRepository) CountItems(ctx context.Context, listID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.ReadingListItem{}).Where("list_id = ?", listID).Count(&count).Error
+	return count, err
+}
+
+// AddItem 把文章追加到清单末尾，已在清单中时返回 false
+func (r *ReadingListRepository) AddItem(ctx context.Context, listID, articleID uint) (bool, error) {
+	added := false
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// 锁住清单行，串行化同一清单的并发追加，保证位置连续
+		var list model.ReadingList
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&list, listID).Error; err != nil {
+			return err
+		}
+
+		var maxPos int
+		if err := tx.Model(&model.ReadingListItem{}).Where("list_id = ?", listID).
+			Select("COALESCE(MAX(position), 0)").Scan(&maxPos).Error; err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.ReadingListItem{ListID: listID, ArticleID: articleID, Position: maxPos + 1})
+		added = result.RowsAffected > 0
+		return result.Error
+	})
+	return added, err
+}
+
+// RemoveItem 从清单中移除文章，不在清单中时返回 false
+func (r *ReadingListRepository) RemoveItem(ctx context.Context, listID, articleID uint) (bool, error) {
+	result := conn(ctx, r.db).Where("list_id = ? AND article_id = ?", listID, articleID).Delete(&model.ReadingListItem{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Reorder 按给定的文章顺序重排清单，未列出的文章排在最后并保持原有相对顺序
+func (r *ReadingListRepository) Reorder(ctx context.Context, listID uint, articleIDs []uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var items []model.ReadingListItem
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("list_id = ?", listID).
+			Order("position ASC, id ASC").Find(&items).Error; err != nil {
+			return err
+		}
+
+		order := make(map[uint]int, len(articleIDs))
+		for i, id := range articleIDs {
+			if _, ok := order[id]; !ok {
+				order[id] = i + 1
+			}
+		}
+		next := len(articleIDs)
+		for _, item := range items {
+			pos, ok := order[item.ArticleID]
+			if !ok {
+				next++
+				pos = next
+			}
+			if pos == item.Position {
+				continue
+			}
+			if err := tx.Model(&model.ReadingListItem{}).Where("id = ?", item.ID).
+				UpdateColumn("position", pos).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
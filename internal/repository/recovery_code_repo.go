@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+type IRecoveryCodeRepository interface {
+	ReplaceForUser(ctx context.Context, userID uint, codeHashes []string) error
+	DeleteByUserID(ctx context.Context, userID uint) error
+	Consume(ctx context.Context, userID uint, codeHash string) (bool, error)
+	CountUnused(ctx context.Context, userID uint) (int64, error)
+}
+
+type RecoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+// 确保 RecoveryCodeRepository 实现了接口
+var _ IRecoveryCodeRepository = (*RecoveryCodeRepository)(nil)
+
+func NewRecoveryCodeRepository() *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{db: db.DB}
+}
+
+// ReplaceForUser 删除旧恢复码并写入新的一组
+func (r *RecoveryCodeRepository) ReplaceForUser(ctx context.Context, userID uint, codeHashes []string) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]model.RecoveryCode, 0, len(codeHashes))
+		for _, h := range codeHashes {
+			codes = append(codes, model.RecoveryCode{UserID: userID, CodeHash: h})
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// DeleteByUserID 删除用户的全部恢复码（关闭两步验证时使用）
+func (r *RecoveryCodeRepository) DeleteByUserID(ctx context.Context, userID uint) error {
+	return conn(ctx, r.db).Unscoped().Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error
+}
+
+// Consume 使用一个恢复码，条件更新保证并发下同一恢复码只能成功一次
+func (r *RecoveryCodeRepository) Consume(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	result := conn(ctx, r.db).Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// CountUnused 统计剩余可用的恢复码
+func (r *RecoveryCodeRepository) CountUnused(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
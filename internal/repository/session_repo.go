@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+type ISessionRepository interface {
+	Create(ctx context.Context, session *model.Session) error
+	GetBySessionID(ctx context.Context, sessionID string) (*model.Session, error)
+	ListActiveByUserID(ctx context.Context, userID uint, now time.Time) ([]model.Session, error)
+	Revoke(ctx context.Context, id, userID uint) (*model.Session, error)
+	RevokeOthers(ctx context.Context, userID uint, keepSessionID string) ([]string, error)
+	RevokeAll(ctx context.Context, userID uint) ([]string, error)
+	TouchLastSeen(ctx context.Context, id uint, now time.Time) error
+}
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+// 确保 SessionRepository 实现了接口
+var _ ISessionRepository = (*SessionRepository)(nil)
+
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{db: db.DB}
+}
+
+// Create 创建会话
+func (r *SessionRepository) Create(ctx context.Context, session *model.Session) error {
+	return conn(ctx, r.db).Create(session).Error
+}
+
+// GetBySessionID 根据 sid 获取会话（已吊销的会话查不到）
+func (r *SessionRepository) GetBySessionID(ctx context.Context, sessionID string) (*model.Session, error) {
+	var session model.Session
+	if err := conn(ctx, r.db).Where("session_id = ?", sessionID).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActiveByUserID 获取用户未过期的会话，最近活跃的在前
+func (r *SessionRepository) ListActiveByUserID(ctx context.Context, userID uint, now time.Time) ([]model.Session, error) {
+	var sessions []model.Session
+	if err := conn(ctx, r.db).Where("user_id = ? AND expires_at > ?", userID, now).Order("last_seen_at DESC").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Revoke 吊销用户的某个会话，返回被吊销的会话
+func (r *SessionRepository) Revoke(ctx context.Context, id, userID uint) (*model.Session, error) {
+	var session model.Session
+	if err := conn(ctx, r.db).Where("id = ? AND user_id = ?", id, userID).First(&session).Error; err != nil {
+		return nil, err
+	}
+	if err := conn(ctx, r.db).Delete(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// RevokeOthers 吊销除 keepSessionID 外的全部会话，返回被吊销的 sid 列表
+func (r *SessionRepository) RevokeOthers(ctx context.Context, userID uint, keepSessionID string) ([]string, error) {
+	var sessionIDs []string
+	query := conn(ctx, r.db).Model(&model.Session{}).Where("user_id = ? AND session_id <> ?", userID, keepSessionID)
+	if err := query.Pluck("session_id", &sessionIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(sessionIDs) == 0 {
+		return nil, nil
+	}
+	if err := conn(ctx, r.db).Where("session_id IN ?", sessionIDs).Delete(&model.Session{}).Error; err != nil {
+		return nil, err
+	}
+	return sessionIDs, nil
+}
+
+// RevokeAll 吊销用户的全部会话，返回被吊销的 sid 列表
+func (r *SessionRepository) RevokeAll(ctx context.Context, userID uint) ([]string, error) {
+	return r.RevokeOthers(ctx, userID, "")
+}
+
+// TouchLastSeen 更新最近活跃时间
+func (r *SessionRepository) TouchLastSeen(ctx context.Context, id uint, now time.Time) error {
+	return conn(ctx, r.db).Model(&model.Session{}).Where("id = ?", id).Update("last_seen_at", now).Error
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ITagRepository interface {
+	GetBySlug(ctx context.Context, slug string) (*model.Tag, error)
+	FindOrCreate(ctx context.Context, tags []model.Tag) ([]model.Tag, error)
+	ListForSitemap(ctx context.Context) ([]TagLastMod, error)
+}
+
+// TagLastMod 标签及其文章中最新的更新时间
+type TagLastMod struct {
+	Slug    string
+	LastMod time.Time
+}
+
+type TagRepository struct {
+	db *gorm.DB
+}
+
+// 确保 TagRepository 实现了接口
+var _ ITagRepository = (*TagRepository)(nil)
+
+func NewTagRepository() *TagRepository {
+	return &TagRepository{db: db.DB}
+}
+
+// GetBySlug 根据 slug 获取标签
+func (r *TagRepository) GetBySlug(ctx context.Context, slug string) (*model.Tag, error) {
+	var tag model.Tag
+	if err := conn(ctx, r.db).Where("slug = ?", slug).First(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// FindOrCreate 按 slug 查找标签，不存在的创建（并发创建由唯一索引兜底），返回顺序与参数一致
+func (r *TagRepository) FindOrCreate(ctx context.Context, tags []model.Tag) ([]model.Tag, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	// 冲突时数据库不返回已有行的 ID，插入后按 slug 重新查询
+	insert := make([]model.Tag, len(tags))
+	copy(insert, tags)
+	if err := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&insert).Error; err != nil {
+		return nil, err
+	}
+
+	slugs := make([]string, len(tags))
+	for i, tag := range tags {
+		slugs[i] = tag.Slug
+	}
+	var found []model.Tag
+	if err := conn(ctx, r.db).Where("slug IN ?", slugs).Find(&found).Error; err != nil {
+		return nil, err
+	}
+	bySlug := make(map[string]model.Tag, len(found))
+	for _, tag := range found {
+		bySlug[tag.Slug] = tag
+	}
+
+	result := make([]model.Tag, 0, len(tags))
+	for _, slug := range slugs {
+		if tag, ok := bySlug[slug]; ok {
+			result = append(result, tag)
+		}
+	}
+	return result, nil
+}
+
+// ListForSitemap 获取有未删除文章的标签，最近修改时间取其文章中最新的 updated_at
+func (r *TagRepository) ListForSitemap(ctx context.Context) ([]TagLastMod, error) {
+	var tags []TagLastMod
+	err := conn(ctx, r.db).Table("tags").
+		Select("tags.slug, MAX(articles.updated_at) AS last_mod").
+		Joins("JOIN article_tags ON article_tags.tag_id = tags.id").
+		Joins("JOIN articles ON articles.id = article_tags.article_id AND articles.deleted_at IS NULL").
+		Group("tags.id, tags.slug").Order("tags.id ASC").
+		Scan(&tags).Error
+	return tags, err
+}
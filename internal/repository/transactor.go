@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+// txKey context 中存放事务状态的 key
+type txKey struct{}
+
+// txState 进行中的事务：事务连接和提交后要执行的回调
+type txState struct {
+	db          *gorm.DB
+	afterCommit []func()
+}
+
+// ITransactor 在一个数据库事务中执行多个 repository 操作
+// 事务连接通过 ctx 传递：fn 内用收到的 ctx 调用 repository，即自动加入该事务
+type ITransactor interface {
+	// Transaction fn 返回错误或 panic 时回滚，否则提交；ctx 已处于事务中时直接加入外层事务
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type Transactor struct {
+	db *gorm.DB
+}
+
+// 确保 Transactor 实现了接口
+var _ ITransactor = (*Transactor)(nil)
+
+func NewTransactor() *Transactor {
+	return &Transactor{db: db.DB}
+}
+
+// Transaction 在事务中执行 fn
+func (t *Transactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if inTx(ctx) {
+		return fn(ctx)
+	}
+	state := &txState{}
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		state.db = tx
+		return fn(context.WithValue(ctx, txKey{}, state))
+	})
+	if err != nil {
+		return err
+	}
+	for _, f := range state.afterCommit {
+		f()
+	}
+	return nil
+}
+
+// conn 返回 ctx 中的事务连接，不在事务中时返回绑定 ctx 的默认连接
+func conn(ctx context.Context, fallback *gorm.DB) *gorm.DB {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.db
+	}
+	return fallback.WithContext(ctx)
+}
+
+// inTx 判断 ctx 是否处于事务中
+func inTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*txState)
+	return ok
+}
+
+// afterCommit 事务提交后执行 f（回滚时不执行）；不在事务中时立即执行
+func afterCommit(ctx context.Context, f func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, f)
+		return
+	}
+	f()
+}
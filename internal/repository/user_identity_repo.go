@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+type IUserIdentityRepository interface {
+	Create(ctx context.Context, identity *model.UserIdentity) error
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*model.UserIdentity, error)
+	ListByUserID(ctx context.Context, userID uint) ([]model.UserIdentity, error)
+}
+
+type UserIdentityRepository struct {
+	db *gorm.DB
+}
+
+// 确保 UserIdentityRepository 实现了接口
+var _ IUserIdentityRepository = (*UserIdentityRepository)(nil)
+
+func NewUserIdentityRepository() *UserIdentityRepository {
+	return &UserIdentityRepository{db: db.DB}
+}
+
+// Create 绑定第三方身份
+func (r *UserIdentityRepository) Create(ctx context.Context, identity *model.UserIdentity) error {
+	return conn(ctx, r.db).Create(identity).Error
+}
+
+// GetByProviderSubject 根据提供方和外部用户 ID 查找绑定关系
+func (r *UserIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*model.UserIdentity, error) {
+	var identity model.UserIdentity
+	if err := conn(ctx, r.db).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// ListByUserID 获取用户绑定的全部第三方身份
+func (r *UserIdentityRepository) ListByUserID(ctx context.Context, userID uint) ([]model.UserIdentity, error) {
+	var identities []model.UserIdentity
+	if err := conn(ctx, r.db).Where("user_id = ?", userID).Order("created_at ASC").Find(&identities).Error; err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
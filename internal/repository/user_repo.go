@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"go-blog-api/internal/model"
 	"go-blog-api/pkg/db"
 
@@ -8,13 +11,38 @@ import (
 )
 
 type IUserRepository interface {
-	CreateUser(user *model.User) error
-	GetByID(id uint) (*model.User, error)
-	GetByUsername(username string) (*model.User, error)
-	GetByEmail(email string) (*model.User, error)
-	Update(user *model.User) error
-	Delete(id uint) error
-	List(offset, limit int, keyword string) ([]model.User, int64, error)
+	CreateUser(ctx context.Context, user *model.User) error
+	GetByID(ctx context.Context, id uint) (*model.User, error)
+	GetByUsername(ctx context.Context, username string) (*model.User, error)
+	GetByEmail(ctx context.Context, email string) (*model.User, error)
+	ListByUsernames(ctx context.Context, usernames []string) ([]model.User, error)
+	UpdateProfile(ctx context.Context, id uint, fields map[string]any) error
+	UpdateAvatar(ctx context.Context, id uint, avatar string) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, offset, limit int, keyword string) ([]model.User, int64, error)
+	AdvanceTOTPCounter(ctx context.Context, id uint, counter int64) (bool, error)
+	SetTOTPSecret(ctx context.Context, id uint, secret string) (bool, error)
+	EnableTOTP(ctx context.Context, id uint, secret string, counter int64) (bool, error)
+	DisableTOTP(ctx context.Context, id uint) error
+	RecordTOTPFailure(ctx context.Context, id uint, maxFailures int, now, lockUntil time.Time) (bool, error)
+	ConsumeTOTPChallenge(ctx context.Context, id uint, issuedAt, now time.Time) (bool, error)
+	Search(ctx context.Context, filter UserFilter, now time.Time, offset, limit int) ([]model.User, int64, error)
+	GetByIDWithDeleted(ctx context.Context, id uint) (*model.User, error)
+	Restore(ctx context.Context, id uint) error
+	UpdateStatus(ctx context.Context, id uint, status, reason string, until *time.Time) error
+	UpdateRole(ctx context.Context, id uint, role string) error
+	RequirePasswordReset(ctx context.Context, id uint) error
+	UpdatePassword(ctx context.Context, id uint, hashedPassword string) error
+}
+
+// UserFilter 管理员查询用户的筛选条件，零值表示不限
+type UserFilter struct {
+	Keyword     string     // 用户名或邮箱
+	Status      string     // 按实际状态筛选，已到期的暂停/封禁视为 active
+	Role        string     // 按数据库中的角色筛选，不包含 security.admin_user_ids
+	CreatedFrom *time.Time // 注册时间范围，包含两端
+	CreatedTo   *time.Time
+	Deleted     bool // 只查已注销（软删除）的用户
 }
 
 type UserRepository struct {
@@ -28,54 +56,72 @@ func NewUserRepository() *UserRepository {
 	return &UserRepository{db: db.DB}
 }
 
-// CreateUser 创建新用户
-func (r *UserRepository) CreateUser(user *model.User) error {
-	return r.db.Create(user).Error
+// CreateUser 创建新用户，用户名或邮箱冲突时返回 ErrDuplicateUsername/ErrDuplicateEmail
+func (r *UserRepository) CreateUser(ctx context.Context, user *model.User) error {
+	return translateUserError(conn(ctx, r.db).Create(user).Error)
 }
 
 // GetByID 根据 ID 获取用户
-func (r *UserRepository) GetByID(id uint) (*model.User, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id uint) (*model.User, error) {
 	var user model.User
-	if err := r.db.First(&user, id).Error; err != nil {
+	if err := conn(ctx, r.db).First(&user, id).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
 // GetByUsername 根据用户名获取用户
-func (r *UserRepository) GetByUsername(username string) (*model.User, error) {
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*model.User, error) {
 	var user model.User
-	if err := r.db.Where("username = ?", username).First(&user).Error; err != nil {
+	if err := conn(ctx, r.db).Where("username = ?", username).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
 // GetByEmail 根据邮箱获取用户
-func (r *UserRepository) GetByEmail(email string) (*model.User, error) {
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
-	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := conn(ctx, r.db).Where("email = ?", email).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-// Update 更新用户
-func (r *UserRepository) Update(user *model.User) error {
-	return r.db.Save(user).Error
+// ListByUsernames 批量根据用户名获取用户，不存在的用户名会被忽略
+func (r *UserRepository) ListByUsernames(ctx context.Context, usernames []string) ([]model.User, error) {
+	var users []model.User
+	if len(usernames) == 0 {
+		return users, nil
+	}
+	err := conn(ctx, r.db).Where("username IN ?", usernames).Find(&users).Error
+	return users, err
+}
+
+// UpdateProfile 只更新给定的资料字段（列名 → 值），不会覆盖状态、角色等并发修改的列；邮箱冲突时返回 ErrDuplicateEmail
+func (r *UserRepository) UpdateProfile(ctx context.Context, id uint, fields map[string]any) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return translateUserError(conn(ctx, r.db).Model(&model.User{}).Where("id = ?", id).Updates(fields).Error)
+}
+
+// UpdateAvatar 更新头像
+func (r *UserRepository) UpdateAvatar(ctx context.Context, id uint, avatar string) error {
+	return conn(ctx, r.db).Model(&model.User{}).Where("id = ?", id).Update("avatar", avatar).Error
 }
 
 // Delete 删除用户（软删除）
-func (r *UserRepository) Delete(id uint) error {
-	return r.db.Delete(&model.User{}, id).Error
+func (r *UserRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&model.User{}, id).Error
 }
 
 // List 获取用户列表（支持关键词搜索）
-func (r *UserRepository) List(offset, limit int, keyword string) ([]model.User, int64, error) {
+func (r *UserRepository) List(ctx context.Context, offset, limit int, keyword string) ([]model.User, int64, error) {
 	var users []model.User
 	var total int64
 
-	query := r.db.Model(&model.User{})
+	query := conn(ctx, r.db).Model(&model.User{})
 
 	// 关键词搜索（用户名或邮箱）
 	if keyword != "" {
@@ -92,3 +138,160 @@ func (r *UserRepository) List(offset, limit int, keyword string) ([]model.User,
 
 	return users, total, nil
 }
+
+// AdvanceTOTPCounter 记录最近使用的 TOTP 时间步，只允许递增，防止同一验证码被重复使用
+func (r *UserRepository) AdvanceTOTPCounter(ctx context.Context, id uint, counter int64) (bool, error) {
+	result := conn(ctx, r.db).Model(&model.User{}).
+		Where("id = ? AND totp_last_counter < ?", id, counter).
+		Update("totp_last_counter", counter)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// SetTOTPSecret 保存待确认的 TOTP 密钥，已开启两步验证时不修改并返回 false
+func (r *UserRepository) SetTOTPSecret(ctx context.Context, id uint, secret string) (bool, error) {
+	result := conn(ctx, r.db).Model(&model.User{}).
+		Where("id = ? AND totp_enabled = ?", id, false).
+		Update("totp_secret", secret)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// EnableTOTP 开启两步验证并记录确认时使用的时间步；密钥已被重新生成、已开启或时间步未递增时返回 false
+func (r *UserRepository) EnableTOTP(ctx context.Context, id uint, secret string, counter int64) (bool, error) {
+	result := conn(ctx, r.db).Model(&model.User{}).
+		Where("id = ? AND totp_enabled = ? AND totp_secret = ? AND totp_last_counter < ?", id, false, secret, counter).
+		Updates(map[string]any{
+			"totp_enabled":      true,
+			"totp_last_counter": counter,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// DisableTOTP 关闭两步验证并清除密钥、时间步和错误计数
+func (r *UserRepository) DisableTOTP(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Model(&model.User{}).Where("id = ?", id).Updates(map[string]any{
+		"totp_enabled":      false,
+		"totp_secret":       "",
+		"totp_last_counter": 0,
+		"totp_failures":     0,
+		"totp_locked_until": nil,
+	}).Error
+}
+
+// RecordTOTPFailure 记录一次验证码错误；累计达到 maxFailures 时锁定到 lockUntil，
+// 并作废此前签发的全部挑战 token，返回是否因此被锁定
+func (r *UserRepository) RecordTOTPFailure(ctx context.Context, id uint, maxFailures int, now, lockUntil time.Time) (bool, error) {
+	if err := conn(ctx, r.db).Model(&model.User{}).Where("id = ?", id).
+		UpdateColumn("totp_failures", gorm.Expr("totp_failures + 1")).Error; err != nil {
+		return false, err
+	}
+	result := conn(ctx, r.db).Model(&model.User{}).
+		Where("id = ? AND totp_failures >= ?", id, maxFailures).
+		UpdateColumns(map[string]any{
+			"totp_failures":        0,
+			"totp_locked_until":    lockUntil,
+			"totp_challenge_after": now,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// ConsumeTOTPChallenge 两步登录成功时使用挑战 token：签发时间 issuedAt 之前（含）的挑战全部作废并清零错误计数；
+// 挑战已被使用、已作废或账号处于锁定中时返回 false
+func (r *UserRepository) ConsumeTOTPChallenge(ctx context.Context, id uint, issuedAt, now time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&model.User{}).
+		Where("id = ? AND (totp_challenge_after IS NULL OR totp_challenge_after < ?)", id, issuedAt).
+		Where("totp_locked_until IS NULL OR totp_locked_until <= ?", now).
+		UpdateColumns(map[string]any{
+			"totp_failures":        0,
+			"totp_challenge_after": issuedAt,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// Search 按条件查询用户（管理员使用），最新注册的在前
+func (r *UserRepository) Search(ctx context.Context, filter UserFilter, now time.Time, offset, limit int) ([]model.User, int64, error) {
+	var users []model.User
+	var total int64
+
+	query := conn(ctx, r.db).Model(&model.User{})
+	if filter.Deleted {
+		query = query.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+	if filter.Keyword != "" {
+		query = query.Where("username LIKE ? OR email LIKE ?", "%"+filter.Keyword+"%", "%"+filter.Keyword+"%")
+	}
+	switch filter.Status {
+	case "":
+	case model.UserStatusActive:
+		query = query.Where("status = ? OR (status_until IS NOT NULL AND status_until <= ?)", model.UserStatusActive, now)
+	default:
+		query = query.Where("status = ? AND (status_until IS NULL OR status_until > ?)", filter.Status, now)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at <= ?", *filter.CreatedTo)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// GetByIDWithDeleted 根据 ID 获取用户，包括已注销的用户
+func (r *UserRepository) GetByIDWithDeleted(ctx context.Context, id uint) (*model.User, error) {
+	var user model.User
+	if err := conn(ctx, r.db).Unscoped().First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Restore 恢复已注销的用户
+func (r *UserRepository) Restore(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Unscoped().Model(&model.User{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil).Error
+}
+
+// UpdateStatus 更新账号状态及处置原因、到期时间
+func (r *UserRepository) UpdateStatus(ctx context.Context, id uint, status, reason string, until *time.Time) error {
+	return conn(ctx, r.db).Model(&model.User{}).Where("id = ?", id).Updates(map[string]any{
+		"status":        status,
+		"status_reason": reason,
+		"status_until":  until,
+	}).Error
+}
+
+// UpdateRole 更新角色
+func (r *UserRepository) UpdateRole(ctx context.Context, id uint, role string) error {
+	return conn(ctx, r.db).Model(&model.User{}).Where("id = ?", id).Update("role", role).Error
+}
+
+// RequirePasswordReset 要求用户下次登录时重置密码
+func (r *UserRepository) RequirePasswordReset(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Model(&model.User{}).Where("id = ?", id).Update("password_reset_required", true).Error
+}
+
+// UpdatePassword 更新密码并清除重置要求
+func (r *UserRepository) UpdatePassword(ctx context.Context, id uint, hashedPassword string) error {
+	return conn(ctx, r.db).Model(&model.User{}).Where("id = ?", id).Updates(map[string]any{
+		"password":                hashedPassword,
+		"password_reset_required": false,
+	}).Error
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+type IWebhookRepository interface {
+	Create(ctx context.Context, webhook *model.Webhook) error
+	GetByID(ctx context.Context, id uint) (*model.Webhook, error)
+	Update(ctx context.Context, webhook *model.Webhook) error
+	Delete(ctx context.Context, id uint) error
+	ListByUserID(ctx context.Context, userID uint) ([]model.Webhook, error)
+	CountByUserID(ctx context.Context, userID uint) (int64, error)
+	ListActiveForOwner(ctx context.Context, ownerID uint) ([]model.Webhook, error)
+	RecordSuccess(ctx context.Context, id uint) error
+	RecordFailure(ctx context.Context, id uint, disableAfter int, now time.Time) (bool, error)
+
+	CreateDeliveries(ctx context.Context, deliveries []model.WebhookDelivery) error
+	GetDelivery(ctx context.Context, id uint) (*model.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, webhookID uint, offset, limit int) ([]model.WebhookDelivery, int64, error)
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error)
+	ClaimDelivery(ctx context.Context, id uint, now, leaseUntil time.Time) (bool, error)
+	SaveDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+}
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+// 确保 WebhookRepository 实现了接口
+var _ IWebhookRepository = (*WebhookRepository)(nil)
+
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{db: db.DB}
+}
+
+// Create 创建 webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook *model.Webhook) error {
+	return conn(ctx, r.db).Create(webhook).Error
+}
+
+// GetByID 根据 ID 获取 webhook
+func (r *WebhookRepository) GetByID(ctx context.Context, id uint) (*model.Webhook, error) {
+	var webhook model.Webhook
+	if err := conn(ctx, r.db).First(&webhook, id).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// Update 更新 webhook
+func (r *WebhookRepository) Update(ctx context.Context, webhook *model.Webhook) error {
+	return conn(ctx, r.db).Save(webhook).Error
+}
+
+// Delete 删除 webhook 及其投递记录
+func (r *WebhookRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&model.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Webhook{}, id).Error
+	})
+}
+
+// ListByUserID 获取用户创建的 webhook
+func (r *WebhookRepository) ListByUserID(ctx context.Context, userID uint) ([]model.Webhook, error) {
+	var webhooks []model.Webhook
+	err := conn(ctx, r.db).Where("user_id = ?", userID).Order("created_at DESC").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// CountByUserID 统计用户创建的 webhook 数量
+func (r *WebhookRepository) CountByUserID(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.Webhook{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// ListActiveForOwner 获取应收到某个用户相关事件的 webhook：该用户自己的，以及全站 webhook
+func (r *WebhookRepository) ListActiveForOwner(ctx context.Context, ownerID uint) ([]model.Webhook, error) {
+	var webhooks []model.Webhook
+	err := conn(ctx, r.db).Where("active = ? AND (user_id = ? OR global = ?)", true, ownerID, true).Find(&webhooks).Error
+	return webhooks, err
+}
+
+// RecordSuccess 投递成功后清零连续失败次数
+func (r *WebhookRepository) RecordSuccess(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Model(&model.Webhook{}).
+		Where("id = ? AND failure_count <> 0", id).
+		Update("failure_count", 0).Error
+}
+
+// RecordFailure 累加连续失败次数，达到 disableAfter 时停用 webhook；返回本次是否触发了停用
+func (r *WebhookRepository) RecordFailure(ctx context.Context, id uint, disableAfter int, now time.Time) (bool, error) {
+	if err := conn(ctx, r.db).Model(&model.Webhook{}).Where("id = ?", id).
+		Update("failure_count", gorm.Expr("failure_count + 1")).Error; err != nil {
+		return false, err
+	}
+	if disableAfter <= 0 {
+		return false, nil
+	}
+	result := conn(ctx, r.db).Model(&model.Webhook{}).
+		Where("id = ? AND active = ? AND failure_count >= ?", id, true, disableAfter).
+		Updates(map[string]any{"active": false, "disabled_at": now})
+	return result.RowsAffected == 1, result.Error
+}
+
+// CreateDeliveries 批量创建投递任务
+func (r *WebhookRepository) CreateDeliveries(ctx context.Context, deliveries []model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Create(&deliveries).Error
+}
+
+// GetDelivery 根据 ID 获取投递记录
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id uint) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	if err := conn(ctx, r.db).First(&delivery, id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// ListDeliveries 获取 webhook 的投递记录，最新的在前
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, webhookID uint, offset, limit int) ([]model.WebhookDelivery, int64, error) {
+	var deliveries []model.WebhookDelivery
+	var total int64
+
+	query := conn(ctx, r.db).Model(&model.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+// ListDueDeliveries 获取到期待投递的任务
+func (r *WebhookRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := conn(ctx, r.db).Where("status = ? AND next_attempt_at <= ?", model.DeliveryStatusPending, now).
+		Order("next_attempt_at").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// ClaimDelivery 领取投递任务：把下次尝试时间推迟到 leaseUntil，多个实例同时扫描时只有一个能领取成功；
+// 进程在投递过程中退出时，任务在 leaseUntil 之后会被重新领取
+func (r *WebhookRepository) ClaimDelivery(ctx context.Context, id uint, now, leaseUntil time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&model.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", id, model.DeliveryStatusPending, now).
+		Update("next_attempt_at", leaseUntil)
+	return result.RowsAffected == 1, result.Error
+}
+
+// SaveDelivery 保存投递结果
+func (r *WebhookRepository) SaveDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	return conn(ctx, r.db).Save(delivery).Error
+}
@@ -3,6 +3,7 @@ package router
 import (
 	v1 "go-blog-api/internal/api/v1"
 	"go-blog-api/internal/middleware"
+	"go-blog-api/internal/model"
 	"go-blog-api/pkg/config"
 
 	"github.com/gin-gonic/gin"
@@ -17,50 +18,243 @@ func InitRouter() *gin.Engine {
 	r := gin.New()
 	r.Use(gin.Logger())   // 日志中间件
 	r.Use(gin.Recovery()) // 恢复中间件，防止崩溃
+	// 请求 ID，写入响应头和审计日志
+	r.Use(middleware.RequestID())
 	// CORS 中间件，允许本地前端开发访问
 	r.Use(middleware.CORS())
 
 	// Swagger 文档路由
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// JWKS 公钥集合，供其他服务验证本服务签发的 token（不加 /api/v1 前缀，遵循约定路径）
+	r.GET("/.well-known/jwks.json", v1.JWKS)
+
+	// 请求超时，超时后该请求的数据库查询随之取消；实时事件推送和文件下载是长连接，不挂载
+	timeout := middleware.QueryTimeout()
+
+	// GET 接口的 HTTP 缓存策略：公开文章允许共享缓存但每次需重新验证，个人数据只允许客户端缓存
+	articleCache := middleware.Conditional(middleware.CachePolicy{CacheControl: "public, no-cache", VaryAuth: true, NoLastModified: true})
+	articleListCache := middleware.Conditional(middleware.CachePolicy{CacheControl: "public, max-age=60", Weak: true})
+	privateCache := middleware.Conditional(middleware.CachePolicy{CacheControl: "private, no-cache"})
+
+	// 创建类 POST 接口支持 Idempotency-Key，需挂载在 JWT 之后
+	idempotent := middleware.Idempotency()
+
+	// 订阅源：全站、作者和标签维度，公开访问
+	feedCtrl := v1.NewFeedController()
+	pages := r.Group("", timeout)
+	pages.GET("/feed.xml", feedCtrl.RSS)
+	pages.GET("/atom.xml", feedCtrl.Atom)
+	pages.GET("/feed.json", feedCtrl.JSON)
+	pages.GET("/users/:id/feed.xml", feedCtrl.AuthorRSS)
+	pages.GET("/users/:id/atom.xml", feedCtrl.AuthorAtom)
+	pages.GET("/users/:id/feed.json", feedCtrl.AuthorJSON)
+	pages.GET("/tags/:slug/feed.xml", feedCtrl.TagRSS)
+	pages.GET("/tags/:slug/atom.xml", feedCtrl.TagAtom)
+	pages.GET("/tags/:slug/feed.json", feedCtrl.TagJSON)
+
+	// 站点地图，供搜索引擎抓取
+	sitemapCtrl := v1.NewSitemapController()
+	pages.GET("/sitemap.xml", sitemapCtrl.Sitemap)
+	pages.GET("/sitemaps/:page", sitemapCtrl.SitemapPage)
+
 	// 初始化 Controller
 	articleCtrl := v1.NewArticleController()
 	userCtrl := v1.NewUserController()
+	twoFactorCtrl := v1.NewTwoFactorController()
+	oidcCtrl := v1.NewOIDCController()
+	apiKeyCtrl := v1.NewAPIKeyController()
+	sessionCtrl := v1.NewSessionController()
+	mediaCtrl := v1.NewMediaController()
+	commentCtrl := v1.NewCommentController()
+	reactionCtrl := v1.NewReactionController()
+	bookmarkCtrl := v1.NewBookmarkController()
+	listCtrl := v1.NewReadingListController()
+	followCtrl := v1.NewFollowController()
+	notificationCtrl := v1.NewNotificationController()
+	eventCtrl := v1.NewEventController()
+	webhookCtrl := v1.NewWebhookController()
+	adminCtrl := v1.NewAdminController()
 	// 路由分组：/api/v1 作为统一前缀，方便做版本控制
 	apiV1 := r.Group("/api/v1")
 	{
 
 		// /api/v1/auth auth相关
-		auth := apiV1.Group("/auth")
+		auth := apiV1.Group("/auth", timeout)
 		{
 			auth.POST("/login", userCtrl.Login)
 			auth.POST("/register", userCtrl.Register)
+			auth.POST("/password/reset", userCtrl.ResetPassword)
 			// 需要登录才能访问
-			auth.GET("/me", middleware.JWT(), userCtrl.GetMe)
+			auth.GET("/me", privateCache, middleware.JWT(), middleware.RequireScope(model.ScopeUsersRead), userCtrl.GetMe)
 			// 注销（客户端清除 token 即可，后端保留接口以便未来扩展）
-			auth.POST("/logout", middleware.JWT(), userCtrl.Logout)
+			auth.POST("/logout", middleware.JWT(), middleware.RequireUserToken(), userCtrl.Logout)
+
+			// 两步验证：verify 使用登录返回的挑战 token，其余接口需要登录
+			auth.POST("/2fa/verify", twoFactorCtrl.VerifyLogin)
+			twoFactor := auth.Group("/2fa")
+			twoFactor.Use(middleware.JWT(), middleware.RequireUserToken(), middleware.DenyImpersonation())
+			{
+				twoFactor.GET("", twoFactorCtrl.Status)
+				twoFactor.POST("/enroll", twoFactorCtrl.Enroll)
+				twoFactor.POST("/confirm", twoFactorCtrl.Confirm)
+				twoFactor.POST("/disable", twoFactorCtrl.Disable)
+				twoFactor.POST("/recovery-codes", twoFactorCtrl.RegenerateRecoveryCodes)
+			}
+
+			// 第三方登录（OAuth2 / OIDC）
+			auth.GET("/oidc/providers", oidcCtrl.ListProviders)
+			auth.GET("/oidc/identities", middleware.JWT(), middleware.RequireUserToken(), middleware.DenyImpersonation(), oidcCtrl.ListIdentities)
+			auth.GET("/oidc/:provider/login", oidcCtrl.Login)
+			auth.GET("/oidc/:provider/callback", oidcCtrl.Callback)
+		}
+
+		// /api/v1/me 当前用户的个人设置，只允许登录 token 访问
+		me := apiV1.Group("/me")
+		me.Use(timeout, middleware.JWT(), middleware.RequireUserToken())
+		{
+			// 代登录时不能管理 API Key 和会话
+			noImpersonation := middleware.DenyImpersonation()
+			me.GET("/api-keys", noImpersonation, apiKeyCtrl.ListAPIKeys)
+			me.POST("/api-keys", noImpersonation, apiKeyCtrl.CreateAPIKey)
+			me.DELETE("/api-keys/:id", noImpersonation, apiKeyCtrl.RevokeAPIKey)
+
+			me.GET("/sessions", noImpersonation, sessionCtrl.ListSessions)
+			me.DELETE("/sessions", noImpersonation, sessionCtrl.RevokeOtherSessions)
+			me.DELETE("/sessions/:id", noImpersonation, sessionCtrl.RevokeSession)
+
+			me.POST("/bookmarks/list", bookmarkCtrl.ListBookmarks)
+			me.PUT("/bookmarks/:articleId", bookmarkCtrl.AddBookmark)
+			me.DELETE("/bookmarks/:articleId", bookmarkCtrl.RemoveBookmark)
+
+			me.GET("/feed", followCtrl.Feed)
+
+			me.POST("/notifications/list", notificationCtrl.ListNotifications)
+			me.GET("/notifications/unread-count", notificationCtrl.UnreadCount)
+			me.PUT("/notifications/read-all", notificationCtrl.MarkAllRead)
+			me.PUT("/notifications/:id/read", notificationCtrl.MarkRead)
+			me.GET("/notification-preferences", notificationCtrl.GetPreference)
+			me.PUT("/notification-preferences", notificationCtrl.UpdatePreference)
+
+			me.GET("/lists", listCtrl.ListReadingLists)
+			me.POST("/lists", idempotent, listCtrl.CreateReadingList)
+			me.GET("/lists/:id", listCtrl.GetReadingList)
+			me.PUT("/lists/:id", listCtrl.UpdateReadingList)
+			me.DELETE("/lists/:id", listCtrl.DeleteReadingList)
+			me.PUT("/lists/:id/order", listCtrl.ReorderReadingList)
+			me.PUT("/lists/:id/items/:articleId", listCtrl.AddReadingListItem)
+			me.DELETE("/lists/:id/items/:articleId", listCtrl.RemoveReadingListItem)
+		}
+
+		// /api/v1/events 实时事件推送（SSE / WebSocket），只允许登录 token 或其换取的连接凭证访问
+		events := apiV1.Group("/events")
+		{
+			events.POST("/ticket", timeout, middleware.JWT(), middleware.RequireUserToken(), middleware.DenyImpersonation(), eventCtrl.IssueTicket)
+			events.GET("", middleware.StreamAuth(), middleware.RequireUserToken(), eventCtrl.Stream)
+			events.GET("/ws", middleware.StreamAuth(), middleware.RequireUserToken(), eventCtrl.StreamWebSocket)
+		}
+
+		// /api/v1/webhooks 出站 webhook 管理，只允许登录 token 访问
+		webhooks := apiV1.Group("/webhooks")
+		webhooks.Use(timeout, middleware.JWT(), middleware.RequireUserToken(), middleware.DenyImpersonation())
+		{
+			webhooks.GET("/events", webhookCtrl.ListWebhookEvents)
+			webhooks.GET("", webhookCtrl.ListWebhooks)
+			webhooks.POST("", webhookCtrl.CreateWebhook)
+			webhooks.GET("/:id", webhookCtrl.GetWebhook)
+			webhooks.PUT("/:id", webhookCtrl.UpdateWebhook)
+			webhooks.DELETE("/:id", webhookCtrl.DeleteWebhook)
+			webhooks.POST("/:id/deliveries/list", webhookCtrl.ListDeliveries)
+			webhooks.POST("/:id/deliveries/:deliveryId/redeliver", webhookCtrl.Redeliver)
+		}
+
+		// 公开分享的阅读清单，无需登录
+		apiV1.GET("/shared/lists/:token", timeout, listCtrl.GetSharedReadingList)
+
+		// /api/v1/media 文件上传；下载地址自带签名校验，不需要登录
+		apiV1.GET("/media/:id/file", mediaCtrl.DownloadMedia)
+		apiV1.GET("/media/:id/file/:variant", mediaCtrl.DownloadMedia)
+		media := apiV1.Group("/media")
+		media.Use(timeout, middleware.JWT())
+		{
+			media.POST("/avatar", middleware.RequireScope(model.ScopeUsersWrite), mediaCtrl.UploadAvatar)
+			media.POST("", middleware.RequireScope(model.ScopeArticlesWrite), mediaCtrl.UploadAttachment)
+			media.POST("/list", middleware.RequireScope(model.ScopeArticlesRead), mediaCtrl.ListMedia)
+			media.GET(":id", middleware.RequireScope(model.ScopeArticlesRead), mediaCtrl.GetMedia)
+			media.DELETE(":id", middleware.RequireScope(model.ScopeArticlesWrite), mediaCtrl.DeleteMedia)
 		}
 
 		// /api/v1/articles 相关接口
 		articles := apiV1.Group("/articles")
-		articles.Use(middleware.JWT()) // 挂载中间件
+		articles.Use(timeout, middleware.JWT()) // 挂载中间件
+		{
+			// API Key 访问时按读写区分权限范围
+			read := middleware.RequireScope(model.ScopeArticlesRead)
+			write := middleware.RequireScope(model.ScopeArticlesWrite)
+
+			articles.POST("/list", read, articleCtrl.ListArticles)
+			articles.GET(":id", articleCache, read, articleCtrl.GetArticle)
+			articles.GET("/slug/:slug", articleCache, read, articleCtrl.GetArticleBySlug)
+			articles.POST("", write, idempotent, articleCtrl.CreateArticle)
+			articles.PUT(":id", write, articleCtrl.UpdateArticle)
+			articles.DELETE(":id", write, articleCtrl.DeleteArticle)
+
+			// 评论与回应
+			articles.POST("/:id/comments/list", read, commentCtrl.ListComments)
+			articles.POST("/:id/comments", write, idempotent, commentCtrl.CreateComment)
+			articles.PUT("/:id/reactions/:type", write, reactionCtrl.AddArticleReaction)
+			articles.DELETE("/:id/reactions/:type", write, reactionCtrl.RemoveArticleReaction)
+		}
+
+		// /api/v1/comments 评论的单条操作
+		comments := apiV1.Group("/comments")
+		comments.Use(timeout, middleware.JWT(), middleware.RequireScope(model.ScopeArticlesWrite))
 		{
-			articles.POST("/list", articleCtrl.ListArticles)
-			articles.GET(":id", articleCtrl.GetArticle)
-			articles.POST("", articleCtrl.CreateArticle)
-			articles.PUT(":id", articleCtrl.UpdateArticle)
-			articles.DELETE(":id", articleCtrl.DeleteArticle)
+			comments.DELETE(":id", commentCtrl.DeleteComment)
+			comments.PUT("/:id/reactions/:type", reactionCtrl.AddCommentReaction)
+			comments.DELETE("/:id/reactions/:type", reactionCtrl.RemoveCommentReaction)
 		}
+		apiV1.GET("/reactions/types", timeout, middleware.JWT(), reactionCtrl.ListReactionTypes)
 
 		// /api/v1/users 用户管理接口
 		users := apiV1.Group("/users")
-		users.Use(middleware.JWT()) // 需要登录
+		users.Use(timeout, middleware.JWT()) // 需要登录
 		{
-			users.POST("/list", userCtrl.ListUsers)
-			users.GET(":id", userCtrl.GetUser)
-			users.PUT(":id", userCtrl.UpdateUser)
-			users.DELETE(":id", userCtrl.DeleteUser)
+			read := middleware.RequireScope(model.ScopeUsersRead)
+			write := middleware.RequireScope(model.ScopeUsersWrite)
+
+			users.POST("/list", read, userCtrl.ListUsers)
+			users.GET(":id", read, userCtrl.GetUser)
+			users.PUT(":id", write, middleware.DenyImpersonation(), userCtrl.UpdateUser)
+			users.DELETE(":id", middleware.RequireUserToken(), middleware.DenyImpersonation(), userCtrl.DeleteUser) // 注销账号不允许通过 API Key 和代登录
+
+			users.PUT("/:id/follow", write, followCtrl.Follow)
+			users.DELETE("/:id/follow", write, followCtrl.Unfollow)
+			users.GET("/:id/follow-stats", read, followCtrl.FollowStats)
+			users.POST("/:id/followers/list", read, followCtrl.ListFollowers)
+			users.POST("/:id/following/list", read, followCtrl.ListFollowing)
 		}
+		// /api/v1/admin 管理员接口，只允许管理员本人的登录 token 访问
+		admin := apiV1.Group("/admin")
+		admin.Use(timeout, middleware.JWT(), middleware.RequireAdmin())
+		{
+			admin.POST("/users/list", adminCtrl.ListUsers)
+			admin.POST("/users/:id/suspend", adminCtrl.SuspendUser)
+			admin.POST("/users/:id/ban", adminCtrl.BanUser)
+			admin.POST("/users/:id/unsuspend", adminCtrl.UnsuspendUser)
+			admin.POST("/users/:id/force-password-reset", adminCtrl.ForcePasswordReset)
+			admin.POST("/users/:id/restore", adminCtrl.RestoreUser)
+			admin.PUT("/users/:id/role", adminCtrl.SetUserRole)
+			admin.POST("/users/:id/impersonate", adminCtrl.ImpersonateUser)
+			admin.GET("/users/:id/actions", adminCtrl.ListModerationActions)
+
+			// 审计日志；导出数据量大时可通过 database.route_timeouts 放宽时限
+			admin.POST("/audit-logs/list", adminCtrl.ListAuditLogs)
+			admin.POST("/audit-logs/export", adminCtrl.ExportAuditLogs)
+		}
+
+		// 作者的文章列表，公开访问
+		apiV1.GET("/users/:id/articles", timeout, articleListCache, articleCtrl.ListUserArticles)
 	}
 	return r
 }
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/cache"
+	"go-blog-api/pkg/config"
+	"go-blog-api/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+const (
+	accountCacheSize = 100000
+	accountCacheTTL  = 30 * time.Second // 其他实例上的处置最多在这段时间后生效
+)
+
+// accountCache 账号状态缓存，JWT 中间件每个请求都会查询；值为 nil 表示账号不存在或已注销
+// 只保存状态相关字段，不含密码等敏感信息
+var accountCache = cache.NewLRU[uint, *model.User](accountCacheSize, accountCacheTTL)
+
+// AccountService 校验账号能否访问业务接口（未注销、未被暂停或封禁、无需重置密码）以及是否为管理员
+type AccountService struct {
+	userRepo repository.IUserRepository
+}
+
+func NewAccountService(userRepo repository.IUserRepository) *AccountService {
+	return &AccountService{userRepo: userRepo}
+}
+
+// Check 校验账号当前能否访问业务接口，返回账号的角色
+func (s *AccountService) Check(ctx context.Context, userID uint) (string, error) {
+	user, err := s.load(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if user == nil {
+		return "", util.ErrUnauthorized.WithMsg("账号不存在或已注销")
+	}
+	if err := accountError(user, time.Now()); err != nil {
+		return "", err
+	}
+	if user.PasswordResetRequired {
+		return "", util.ErrPasswordReset
+	}
+	return user.Role, nil
+}
+
+// IsAdmin 账号是否为可正常使用的管理员
+func (s *AccountService) IsAdmin(ctx context.Context, userID uint) bool {
+	role, err := s.Check(ctx, userID)
+	return err == nil && role == model.RoleAdmin
+}
+
+// load 读取账号状态，结果会短暂缓存；查询失败时不缓存
+func (s *AccountService) load(ctx context.Context, userID uint) (*model.User, error) {
+	if user, ok := accountCache.Get(userID); ok {
+		return user, nil
+	}
+
+	var state *model.User
+	user, err := s.userRepo.GetByID(ctx, userID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+	case err != nil:
+		return nil, util.ErrDatabase
+	default:
+		state = &model.User{
+			Role:                  effectiveRole(user),
+			Status:                user.Status,
+			StatusReason:          user.StatusReason,
+			StatusUntil:           user.StatusUntil,
+			PasswordResetRequired: user.PasswordResetRequired,
+		}
+		state.ID = user.ID
+	}
+	accountCache.Set(userID, state)
+	return state, nil
+}
+
+// invalidateAccount 让本实例的缓存立即感知账号状态变化
+func invalidateAccount(userID uint) {
+	accountCache.Delete(userID)
+}
+
+// effectiveRole 实际角色：security.admin_user_ids 中的用户无论数据库中的角色如何都视为管理员
+func effectiveRole(user *model.User) string {
+	if slices.Contains(config.AppConfig.Security.AdminUserIDs, user.ID) {
+		return model.RoleAdmin
+	}
+	if user.Role == "" {
+		return model.RoleUser
+	}
+	return user.Role
+}
+
+// accountError 被暂停或封禁的账号返回对应错误（附带到期时间和原因），否则返回 nil
+func accountError(user *model.User, now time.Time) error {
+	var err *util.BizError
+	switch user.EffectiveStatus(now) {
+	case model.UserStatusSuspended:
+		err = util.ErrAccountSuspended
+	case model.UserStatusBanned:
+		err = util.ErrAccountBanned
+	default:
+		return nil
+	}
+
+	msg := err.Msg
+	if user.StatusUntil != nil {
+		msg += "，至 " + user.StatusUntil.Local().Format(time.DateTime)
+	}
+	if user.StatusReason != "" {
+		msg += "，原因：" + user.StatusReason
+	}
+	return err.WithMsg(msg)
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+// AdminService 负责管理员对用户的处置：查询、暂停/封禁、强制重置密码、恢复注销账号、设置角色和代登录
+// 每次处置都在同一事务中追加一条处置记录和审计日志
+type AdminService struct {
+	userRepo       repository.IUserRepository
+	articleRepo    repository.IArticleRepository
+	moderationRepo repository.IModerationRepository
+	sessionService *SessionService
+	auditService   *AuditService
+	tx             repository.ITransactor
+}
+
+func NewAdminService(userRepo repository.IUserRepository, articleRepo repository.IArticleRepository, moderationRepo repository.IModerationRepository, sessionService *SessionService, auditService *AuditService, tx repository.ITransactor) *AdminService {
+	return &AdminService{userRepo: userRepo, articleRepo: articleRepo, moderationRepo: moderationRepo, sessionService: sessionService, auditService: auditService, tx: tx}
+}
+
+// ListUsers 按条件查询用户
+func (s *AdminService) ListUsers(ctx context.Context, req *dto.AdminListUsersRequest) (*dto.PageResponse[dto.AdminUserResponse], error) {
+	req.SetDefaults()
+
+	now := time.Now()
+	filter := repository.UserFilter{
+		Keyword:     req.Keyword,
+		Status:      req.Status,
+		Role:        req.Role,
+		CreatedFrom: req.CreatedFrom,
+		CreatedTo:   req.CreatedTo,
+		Deleted:     req.Deleted,
+	}
+	users, total, err := s.userRepo.Search(ctx, filter, now, req.Offset(), req.PageSize)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+
+	list := make([]dto.AdminUserResponse, len(users))
+	for i := range users {
+		list[i] = adminUserResponse(&users[i], now)
+	}
+	return dto.NewPageResponse(list, total, req.Page, req.PageSize), nil
+}
+
+// Suspend 暂停用户：到期后自动恢复，期间不能登录和访问接口，已登录的会话保留
+func (s *AdminService) Suspend(ctx context.Context, adminID, userID uint, req *dto.SuspendUserRequest) (*dto.AdminUserResponse, error) {
+	return s.setStatus(ctx, adminID, userID, model.UserStatusSuspended, model.ModerationSuspend, req)
+}
+
+// Ban 封禁用户：同时吊销全部会话，解除后需要重新登录
+func (s *AdminService) Ban(ctx context.Context, adminID, userID uint, req *dto.SuspendUserRequest) (*dto.AdminUserResponse, error) {
+	resp, err := s.setStatus(ctx, adminID, userID, model.UserStatusBanned, model.ModerationBan, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sessionService.RevokeAll(ctx, userID); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *AdminService) setStatus(ctx context.Context, adminID, userID uint, status, action string, req *dto.SuspendUserRequest) (*dto.AdminUserResponse, error) {
+	now := time.Now()
+	if req.Until != nil && !req.Until.After(now) {
+		return nil, util.ErrInvalidParam.WithMsg("到期时间必须晚于当前时间")
+	}
+	user, err := s.target(ctx, adminID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.UpdateStatus(ctx, userID, status, req.Reason, req.Until); err != nil {
+			return err
+		}
+		return s.record(ctx, adminID, userID, action, req.Reason, "", req.Until)
+	})
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	invalidateAccount(userID)
+
+	user.Status, user.StatusReason, user.StatusUntil = status, req.Reason, req.Until
+	resp := adminUserResponse(user, now)
+	return &resp, nil
+}
+
+// Unsuspend 解除暂停或封禁
+func (s *AdminService) Unsuspend(ctx context.Context, adminID, userID uint, req *dto.ModerationReasonRequest) (*dto.AdminUserResponse, error) {
+	user, err := s.target(ctx, adminID, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if user.EffectiveStatus(now) == model.UserStatusActive {
+		return nil, util.ErrConflict.WithMsg("用户未被暂停或封禁")
+	}
+
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.UpdateStatus(ctx, userID, model.UserStatusActive, "", nil); err != nil {
+			return err
+		}
+		return s.record(ctx, adminID, userID, model.ModerationUnsuspend, req.Reason, "", nil)
+	})
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	invalidateAccount(userID)
+
+	user.Status, user.StatusReason, user.StatusUntil = model.UserStatusActive, "", nil
+	resp := adminUserResponse(user, now)
+	return &resp, nil
+}
+
+// ForcePasswordReset 要求用户重置密码：吊销全部会话，下次登录时必须先设置新密码
+func (s *AdminService) ForcePasswordReset(ctx context.Context, adminID, userID uint, req *dto.ModerationReasonRequest) (*dto.AdminUserResponse, error) {
+	user, err := s.target(ctx, adminID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.RequirePasswordReset(ctx, userID); err != nil {
+			return err
+		}
+		return s.record(ctx, adminID, userID, model.ModerationPasswordReset, req.Reason, "", nil)
+	})
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	invalidateAccount(userID)
+	if err := s.sessionService.RevokeAll(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	user.PasswordResetRequired = true
+	resp := adminUserResponse(user, time.Now())
+	return &resp, nil
+}
+
+// Restore 恢复已注销的用户及注销时一并删除的文章（收藏和阅读清单条目无法恢复）
+func (s *AdminService) Restore(ctx context.Context, adminID, userID uint, req *dto.ModerationReasonRequest) (*dto.AdminUserResponse, error) {
+	user, err := s.userRepo.GetByIDWithDeleted(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, util.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	if !user.DeletedAt.Valid {
+		return nil, util.ErrConflict.WithMsg("用户未注销")
+	}
+
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Restore(ctx, userID); err != nil {
+			return err
+		}
+		n, err := s.articleRepo.RestoreByUserID(ctx, userID, user.DeletedAt.Time)
+		if err != nil {
+			return err
+		}
+		return s.record(ctx, adminID, userID, model.ModerationRestore, req.Reason, "恢复文章 "+strconv.FormatInt(n, 10)+" 篇", nil)
+	})
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	invalidateAccount(userID)
+
+	user.DeletedAt = gorm.DeletedAt{}
+	resp := adminUserResponse(user, time.Now())
+	return &resp, nil
+}
+
+// SetRole 设置用户角色
+func (s *AdminService) SetRole(ctx context.Context, adminID, userID uint, req *dto.SetRoleRequest) (*dto.AdminUserResponse, error) {
+	if adminID == userID {
+		return nil, util.ErrForbidden.WithMsg("不能修改自己的角色")
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, util.ErrUserNotFound
+	}
+
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.UpdateRole(ctx, userID, req.Role); err != nil {
+			return err
+		}
+		return s.record(ctx, adminID, userID, model.ModerationSetRole, req.Reason, req.Role, nil)
+	})
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	invalidateAccount(userID)
+
+	user.Role = req.Role
+	resp := adminUserResponse(user, time.Now())
+	return &resp, nil
+}
+
+// Impersonate 代登录用户（用于排查问题）：签发短期 token，会话和 token 都标记了管理员 ID，并记录处置
+func (s *AdminService) Impersonate(ctx context.Context, adminID, userID uint, req *dto.ModerationReasonRequest, client dto.ClientInfo) (*dto.ImpersonationResponse, error) {
+	user, err := s.target(ctx, adminID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := accountError(user, time.Now()); err != nil {
+		return nil, err
+	}
+	if user.PasswordResetRequired {
+		return nil, util.ErrPasswordReset
+	}
+
+	var resp *dto.ImpersonationResponse
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = s.sessionService.StartImpersonation(ctx, user, adminID, client)
+		if err != nil {
+			return err
+		}
+		return s.record(ctx, adminID, userID, model.ModerationImpersonate, req.Reason, "", &resp.ExpiresAt)
+	})
+	if err != nil {
+		var bizErr *util.BizError
+		if errors.As(err, &bizErr) {
+			return nil, bizErr
+		}
+		return nil, util.ErrDatabase
+	}
+	return resp, nil
+}
+
+// ListActions 获取用户的处置记录
+func (s *AdminService) ListActions(ctx context.Context, userID uint, req *dto.PageRequest) (*dto.PageResponse[model.ModerationAction], error) {
+	req.SetDefaults()
+
+	actions, total, err := s.moderationRepo.ListByUserID(ctx, userID, req.Offset(), req.PageSize)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	return dto.NewPageResponse(actions, total, req.Page, req.PageSize), nil
+}
+
+// target 获取被处置的用户：不能处置自己和其他管理员
+func (s *AdminService) target(ctx context.Context, adminID, userID uint) (*model.User, error) {
+	if adminID == userID {
+		return nil, util.ErrForbidden.WithMsg("不能处置自己")
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, util.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	if effectiveRole(user) == model.RoleAdmin {
+		return nil, util.ErrForbidden.WithMsg("不能处置其他管理员")
+	}
+	return user, nil
+}
+
+func (s *AdminService) record(ctx context.Context, adminID, userID uint, action, reason, detail string, until *time.Time) error {
+	err := s.moderationRepo.Create(ctx, &model.ModerationAction{
+		UserID:  userID,
+		AdminID: adminID,
+		Action:  action,
+		Reason:  reason,
+		Detail:  detail,
+		Until:   until,
+	})
+	if err != nil {
+		return err
+	}
+
+	auditDetail := reason
+	if detail != "" {
+		auditDetail += "（" + detail + "）"
+	}
+	return s.auditService.Record(ctx, &model.AuditLog{
+		ActorID:    adminID,
+		Action:     model.AuditAdminPrefix + action,
+		TargetType: model.AuditTargetUser,
+		TargetID:   userID,
+		Detail:     auditDetail,
+	}, nil, nil)
+}
+
+func adminUserResponse(user *model.User, now time.Time) dto.AdminUserResponse {
+	resp := dto.AdminUserResponse{
+		User:                  *user,
+		IsAdmin:               effectiveRole(user) == model.RoleAdmin,
+		Status:                user.EffectiveStatus(now),
+		PasswordResetRequired: user.PasswordResetRequired,
+	}
+	if resp.Status != model.UserStatusActive {
+		resp.StatusReason = user.StatusReason
+		resp.StatusUntil = user.StatusUntil
+	}
+	if user.DeletedAt.Valid {
+		resp.DeletedAt = &user.DeletedAt.Time
+	}
+	return resp
+}
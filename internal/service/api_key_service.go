@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/util"
+)
+
+const (
+	apiKeyPrefix         = "gba"       // key 的固定前缀，便于密钥扫描工具识别
+	maxAPIKeysPerUser    = 20          // 每个用户最多可创建的 key 数量
+	apiKeyTouchInterval  = time.Minute // last_used_at 的最小更新间隔
+	apiKeyPrefixBytes    = 4
+	apiKeySecretBytes    = 24
+	apiKeyExpireDayHours = 24 * time.Hour
+)
+
+// APIKeyService 负责个人 API Key 的创建、吊销和认证
+type APIKeyService struct {
+	keyRepo repository.IAPIKeyRepository
+}
+
+func NewAPIKeyService(keyRepo repository.IAPIKeyRepository) *APIKeyService {
+	return &APIKeyService{keyRepo: keyRepo}
+}
+
+// Create 创建 API Key，返回的完整 key 只展示这一次
+func (s *APIKeyService) Create(ctx context.Context, userID uint, req *dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error) {
+	count, err := s.keyRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	if count >= maxAPIKeysPerUser {
+		return nil, util.ErrInvalidParam.WithMsg("API Key 数量已达上限")
+	}
+
+	prefix, err := randomHex(apiKeyPrefixBytes)
+	if err != nil {
+		return nil, util.ErrInternal
+	}
+	secret, err := randomHex(apiKeySecretBytes)
+	if err != nil {
+		return nil, util.ErrInternal
+	}
+
+	key := &model.APIKey{
+		UserID:     userID,
+		Name:       req.Name,
+		Prefix:     prefix,
+		SecretHash: util.HashToken(secret),
+		Scopes:     strings.Join(uniqueStrings(req.Scopes), ","),
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().Add(time.Duration(req.ExpiresInDays) * apiKeyExpireDayHours)
+		key.ExpiresAt = &expiresAt
+	}
+	if err := s.keyRepo.Create(ctx, key); err != nil {
+		return nil, util.ErrDatabase
+	}
+
+	return &dto.CreateAPIKeyResponse{
+		Key:    apiKeyPrefix + "_" + prefix + "_" + secret,
+		APIKey: *key,
+	}, nil
+}
+
+// List 获取用户的 API Key 列表（不含密钥）
+func (s *APIKeyService) List(ctx context.Context, userID uint) ([]model.APIKey, error) {
+	keys, err := s.keyRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	return keys, nil
+}
+
+// Revoke 吊销 API Key
+func (s *APIKeyService) Revoke(ctx context.Context, userID, id uint) error {
+	deleted, err := s.keyRepo.Delete(ctx, id, userID)
+	if err != nil {
+		return util.ErrDatabase
+	}
+	if !deleted {
+		return util.ErrNotFound.WithMsg("API Key 不存在")
+	}
+	return nil
+}
+
+// Authenticate 校验 "gba_<prefix>_<secret>" 格式的 key，成功时返回 key（含所属用户）
+func (s *APIKeyService) Authenticate(ctx context.Context, raw string) (*model.APIKey, error) {
+	parts := strings.SplitN(raw, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyPrefix {
+		return nil, util.ErrUnauthorized.WithMsg("API Key 格式错误")
+	}
+
+	key, err := s.keyRepo.GetByPrefix(ctx, parts[1])
+	if err != nil || key.User == nil {
+		return nil, util.ErrUnauthorized.WithMsg("API Key 无效")
+	}
+	if subtle.ConstantTimeCompare([]byte(key.SecretHash), []byte(util.HashToken(parts[2]))) != 1 {
+		return nil, util.ErrUnauthorized.WithMsg("API Key 无效")
+	}
+	now := time.Now()
+	if key.Expired(now) {
+		return nil, util.ErrTokenExpired.WithMsg("API Key 已过期")
+	}
+
+	// 最近使用时间只用于展示，写入失败不影响本次请求
+	_ = s.keyRepo.TouchLastUsed(ctx, key.ID, now, apiKeyTouchInterval)
+	return key, nil
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func uniqueStrings(items []string) []string {
+	seen := make(map[string]struct{}, len(items))
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if _, ok := seen[item]; ok {
+			continue
+		}
+		seen[item] = struct{}{}
+		result = append(result, item)
+	}
+	return result
+}
@@ -1,101 +1,380 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
 	"go-blog-api/internal/dto"
+	"go-blog-api/internal/event"
 	"go-blog-api/internal/model"
 	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/markup"
 	"go-blog-api/pkg/util"
+
+	"gorm.io/gorm"
 )
 
 type ArticleService struct {
-	articleRepo repository.IArticleRepository
+	articleRepo  repository.IArticleRepository
+	tagRepo      repository.ITagRepository
+	outboxRepo   repository.IOutboxRepository
+	auditService *AuditService
+	tx           repository.ITransactor
 }
 
-func NewArticleService(repo repository.IArticleRepository) *ArticleService {
-	return &ArticleService{articleRepo: repo}
+func NewArticleService(repo repository.IArticleRepository, tagRepo repository.ITagRepository, outboxRepo repository.IOutboxRepository, auditService *AuditService, tx repository.ITransactor) *ArticleService {
+	return &ArticleService{articleRepo: repo, tagRepo: tagRepo, outboxRepo: outboxRepo, auditService: auditService, tx: tx}
 }
 
 // Create 创建文章
-func (s *ArticleService) Create(userID uint, req *dto.CreateArticleRequest) (*model.Article, error) {
+func (s *ArticleService) Create(ctx context.Context, userID uint, req *dto.CreateArticleRequest) (*model.Article, error) {
 	article := &model.Article{
-		Title:   req.Title,
-		Content: req.Content,
-		UserID:  userID,
+		Title:         req.Title,
+		Content:       req.Content,
+		ContentFormat: req.ContentFormat,
+		UserID:        userID,
+	}
+	if article.ContentFormat == "" {
+		article.ContentFormat = markup.FormatMarkdown
+	}
+	if err := renderContent(article); err != nil {
+		return nil, err
+	}
+	slug, err := s.resolveSlug(ctx, req.Slug, req.Title, 0)
+	if err != nil {
+		return nil, err
+	}
+	article.Slug = slug
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.articleRepo.Create(article); err != nil {
+	// 文章和事件在同一事务中写入，事件由 outbox relay 投递（站点地图、实时推送、webhook）
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if article.Tags, err = s.tagRepo.FindOrCreate(ctx, tags); err != nil {
+			return err
+		}
+		if err := s.articleRepo.Create(ctx, article); err != nil {
+			return err
+		}
+		if err := s.audit(ctx, userID, model.AuditArticleCreate, article.ID, nil, article); err != nil {
+			return err
+		}
+		return s.outboxRepo.Add(ctx, event.ArticleCreated{ArticleInfo: articleInfo(article)})
+	})
+	if err != nil {
 		return nil, util.ErrDatabase
 	}
+	wakeOutboxRelay()
 
 	return article, nil
 }
 
 // GetByID 获取文章详情
-func (s *ArticleService) GetByID(id uint) (*model.Article, error) {
-	article, err := s.articleRepo.GetByID(id)
+func (s *ArticleService) GetByID(ctx context.Context, id uint) (*model.Article, error) {
+	article, err := s.articleRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, util.ErrArticleNotFound
 	}
+	s.prepareDetail(ctx, article)
 	return article, nil
 }
 
-// Update 更新文章
-func (s *ArticleService) Update(id, userID uint, req *dto.UpdateArticleRequest) (*model.Article, error) {
-	// 1. 查询文章
-	article, err := s.articleRepo.GetByID(id)
-	if err != nil {
-		return nil, util.ErrArticleNotFound
+// GetBySlug 根据 slug 获取文章；命中历史 slug 时返回当前 slug，由调用方跳转
+func (s *ArticleService) GetBySlug(ctx context.Context, slug string) (*model.Article, string, error) {
+	if article, err := s.articleRepo.GetBySlug(ctx, slug); err == nil {
+		s.prepareDetail(ctx, article)
+		return article, "", nil
 	}
 
-	// 2. 检查权限
-	if article.UserID != userID {
-		return nil, util.ErrForbidden
+	redirect, err := s.articleRepo.GetSlugRedirect(ctx, slug)
+	if err != nil {
+		return nil, "", util.ErrArticleNotFound
+	}
+	article, err := s.articleRepo.GetByID(ctx, redirect.ArticleID)
+	if err != nil {
+		return nil, "", util.ErrArticleNotFound
 	}
+	s.prepareDetail(ctx, article)
+	return article, article.Slug, nil
+}
 
-	// 3. 更新字段
-	if req.Title != "" {
-		article.Title = req.Title
+// prepareDetail 补齐历史数据缺失的 slug 和渲染缓存，并生成目录
+func (s *ArticleService) prepareDetail(ctx context.Context, article *model.Article) {
+	if article.Slug == "" {
+		if slug, err := s.resolveSlug(ctx, "", article.Title, article.ID); err == nil {
+			if s.articleRepo.UpdateSlug(ctx, article.ID, slug) == nil {
+				article.Slug = slug
+				if err := broadcast(ctx, model.BroadcastSitemapExpired, nil); err != nil {
+					log.Printf("broadcast sitemap invalidation failed: %v", err)
+				}
+			}
+		}
 	}
-	if req.Content != "" {
-		article.Content = req.Content
+	if article.ContentHTML == "" && article.Content != "" {
+		if err := renderContent(article); err == nil {
+			_ = s.articleRepo.UpdateContentHTML(ctx, article.ID, article.ContentHTML)
+		}
 	}
+	article.TOC = markup.ExtractTOC(article.ContentHTML)
+}
 
-	if err := s.articleRepo.Update(article); err != nil {
-		return nil, util.ErrDatabase
+// Update 更新文章
+// 文章在事务中加锁读取（不经过缓存），审计日志的修改前快照和权限检查都基于最新数据，只保存修改的列
+func (s *ArticleService) Update(ctx context.Context, id, userID uint, req *dto.UpdateArticleRequest) (*model.Article, error) {
+	var tags []model.Tag
+	if req.Tags != nil {
+		var err error
+		if tags, err = normalizeTags(*req.Tags); err != nil {
+			return nil, err
+		}
 	}
 
+	var article *model.Article
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		// 1. 查询文章
+		var err error
+		article, err = s.articleRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return util.ErrArticleNotFound
+			}
+			return err
+		}
+
+		// 2. 检查权限
+		if article.UserID != userID {
+			return util.ErrForbidden
+		}
+
+		// 3. 更新字段；自定义 slug 或标题变化时更新 slug，旧 slug 记入跳转表
+		before := *article
+		oldSlug := article.Slug
+		var columns []string
+		if req.Slug != "" || (req.Title != "" && req.Title != article.Title) || article.Slug == "" {
+			title := article.Title
+			if req.Title != "" {
+				title = req.Title
+			}
+			slug, err := s.resolveSlug(ctx, req.Slug, title, article.ID)
+			if err != nil {
+				return err
+			}
+			article.Slug = slug
+			columns = append(columns, "slug")
+		}
+		if req.Title != "" {
+			article.Title = req.Title
+			columns = append(columns, "title")
+		}
+		if req.Content != "" || req.ContentFormat != "" {
+			if req.Content != "" {
+				article.Content = req.Content
+			}
+			if req.ContentFormat != "" {
+				article.ContentFormat = req.ContentFormat
+			}
+			if err := renderContent(article); err != nil {
+				return err
+			}
+			columns = append(columns, "content", "content_format", "content_html")
+		}
+
+		if err := s.articleRepo.UpdateWithSlug(ctx, article, oldSlug, columns); err != nil {
+			return err
+		}
+		if req.Tags != nil {
+			found, err := s.tagRepo.FindOrCreate(ctx, tags)
+			if err != nil {
+				return err
+			}
+			if err := s.articleRepo.ReplaceTags(ctx, article, found); err != nil {
+				return err
+			}
+			article.Tags = found
+		}
+		if err := s.audit(ctx, userID, model.AuditArticleUpdate, article.ID, &before, article); err != nil {
+			return err
+		}
+		return s.outboxRepo.Add(ctx, event.ArticleUpdated{ArticleInfo: articleInfo(article)})
+	})
+	if err != nil {
+		return nil, articleError(err)
+	}
+	wakeOutboxRelay()
+
+	article.TOC = markup.ExtractTOC(article.ContentHTML)
 	return article, nil
 }
 
-// Delete 删除文章
-func (s *ArticleService) Delete(id, userID uint) error {
-	// 1. 查询文章
-	article, err := s.articleRepo.GetByID(id)
+// Delete 删除文章，文章在事务中加锁读取，审计日志记录删除前的最新数据
+func (s *ArticleService) Delete(ctx context.Context, id, userID uint) error {
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		// 1. 查询文章
+		article, err := s.articleRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return util.ErrArticleNotFound
+			}
+			return err
+		}
+
+		// 2. 检查权限
+		if article.UserID != userID {
+			return util.ErrForbidden
+		}
+
+		// 3. 删除
+		if err := s.articleRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		if err := s.audit(ctx, userID, model.AuditArticleDelete, id, article, nil); err != nil {
+			return err
+		}
+		return s.outboxRepo.Add(ctx, event.ArticleDeleted{ArticleInfo: articleInfo(article)})
+	})
 	if err != nil {
-		return util.ErrArticleNotFound
+		return articleError(err)
 	}
+	wakeOutboxRelay()
 
-	// 2. 检查权限
-	if article.UserID != userID {
-		return util.ErrForbidden
-	}
+	return nil
+}
 
-	// 3. 删除
-	if err := s.articleRepo.Delete(id); err != nil {
-		return util.ErrDatabase
+// articleError 事务中返回的业务错误原样返回，其他错误视为数据库错误
+func articleError(err error) error {
+	var bizErr *util.BizError
+	if errors.As(err, &bizErr) {
+		return bizErr
 	}
+	return util.ErrDatabase
+}
 
-	return nil
+// audit 在事务中记录文章变更的审计日志
+func (s *ArticleService) audit(ctx context.Context, actorID uint, action string, articleID uint, before, after *model.Article) error {
+	entry := &model.AuditLog{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: model.AuditTargetArticle,
+		TargetID:   articleID,
+	}
+	var from, to any
+	if before != nil {
+		from = before
+	}
+	if after != nil {
+		to = after
+	}
+	return s.auditService.Record(ctx, entry, from, to)
 }
 
 // List 获取文章列表
-func (s *ArticleService) List(req *dto.ListArticlesRequest) (*dto.PageResponse[model.Article], error) {
+func (s *ArticleService) List(ctx context.Context, req *dto.ListArticlesRequest) (*dto.PageResponse[model.Article], error) {
 	req.SetDefaults()
 
-	articles, total, err := s.articleRepo.List(req.Offset(), req.PageSize)
+	articles, total, err := s.articleRepo.List(ctx, req.Offset(), req.PageSize)
 	if err != nil {
 		return nil, util.ErrDatabase
 	}
 
 	return dto.NewPageResponse(articles, total, req.Page, req.PageSize), nil
 }
+
+// ListByUser 获取某个作者的文章列表（公开接口，不返回作者邮箱）
+func (s *ArticleService) ListByUser(ctx context.Context, userID uint, req *dto.ListArticlesRequest) (*dto.PageResponse[model.Article], error) {
+	req.SetDefaults()
+
+	articles, total, err := s.articleRepo.ListByUserID(ctx, userID, req.Offset(), req.PageSize)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	for i := range articles {
+		if articles[i].User != nil {
+			articles[i].User.Email = ""
+		}
+	}
+
+	return dto.NewPageResponse(articles, total, req.Page, req.PageSize), nil
+}
+
+// renderContent 按正文格式重新生成 content_html
+func renderContent(article *model.Article) error {
+	contentHTML, err := markup.Render(article.ContentFormat, article.Content)
+	if err != nil {
+		return util.ErrInvalidParam.WithMsg("不支持的正文格式")
+	}
+	article.ContentHTML = contentHTML
+	return nil
+}
+
+// normalizeTags 去掉空白标签名并按 slug 去重，slug 无法生成的标签名视为非法
+func normalizeTags(names []string) ([]model.Tag, error) {
+	tags := make([]model.Tag, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		slug := util.Slugify(name)
+		if slug == "" {
+			return nil, util.ErrInvalidParam.WithMsg("无法识别的标签：" + name)
+		}
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		tags = append(tags, model.Tag{Name: name, Slug: slug})
+	}
+	return tags, nil
+}
+
+// resolveSlug 校验自定义 slug，或由标题生成 slug 并在冲突时追加数字后缀
+func (s *ArticleService) resolveSlug(ctx context.Context, custom, title string, articleID uint) (string, error) {
+	if custom != "" {
+		if !util.ValidSlug(custom) {
+			return "", util.ErrInvalidParam.WithMsg("slug 只能包含小写字母、数字和连字符")
+		}
+		taken, err := s.articleRepo.SlugTaken(ctx, custom, articleID)
+		if err != nil {
+			return "", util.ErrDatabase
+		}
+		if taken {
+			return "", util.ErrSlugExists
+		}
+		return custom, nil
+	}
+
+	base := util.Slugify(title)
+	if base == "" {
+		// 无法转写的标题使用随机 slug
+		suffix, err := randomHex(4)
+		if err != nil {
+			return "", util.ErrInternal
+		}
+		base = "article-" + suffix
+	}
+	// 预留后缀长度，保证加上后缀后仍不超过上限
+	if len(base) > util.MaxSlugLength-4 {
+		base = strings.TrimRight(base[:util.MaxSlugLength-4], "-")
+	}
+
+	slug := base
+	for i := 2; ; i++ {
+		taken, err := s.articleRepo.SlugTaken(ctx, slug, articleID)
+		if err != nil {
+			return "", util.ErrDatabase
+		}
+		if !taken {
+			return slug, nil
+		}
+		if i > 100 {
+			return "", util.ErrSlugExists
+		}
+		slug = base + "-" + strconv.Itoa(i)
+	}
+}
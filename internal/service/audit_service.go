@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"reflect"
+	"unicode/utf8"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/util"
+)
+
+const (
+	auditExportBatch    = 500
+	auditMaxValueLength = 500 // 变更记录中单个字符串字段和附加信息保留的最大长度，正文等长文本会被截断
+)
+
+// auditIgnoredFields 不记入变更的字段：时间戳、渲染结果和关联数据
+var auditIgnoredFields = map[string]bool{
+	"created_at":   true,
+	"updated_at":   true,
+	"content_html": true,
+	"toc":          true,
+	"reactions":    true,
+	"user":         true,
+}
+
+// RequestMeta 写入审计日志的请求信息，由 middleware.RequestID 放入请求的 context
+type RequestMeta struct {
+	RequestID      string
+	IP             string
+	UserAgent      string
+	ImpersonatorID uint // 代登录时的管理员 ID，由 JWT 中间件补充
+}
+
+type requestMetaKey struct{}
+
+// WithRequestMeta 把请求信息放入 context
+func WithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+// RequestMetaFrom 读取 context 中的请求信息，后台任务等没有请求的场景返回零值
+func RequestMetaFrom(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(RequestMeta)
+	return meta
+}
+
+// AuditService 负责审计日志：记录登录、用户和文章的变更，供管理员查询和导出
+type AuditService struct {
+	auditRepo repository.IAuditRepository
+}
+
+func NewAuditService(auditRepo repository.IAuditRepository) *AuditService {
+	return &AuditService{auditRepo: auditRepo}
+}
+
+// Record 追加一条审计日志，before/after 为变更前后的对象（创建时 before 为 nil，删除时 after 为 nil）
+// 在事务中调用时随事务提交，写入失败会让事务回滚
+func (s *AuditService) Record(ctx context.Context, entry *model.AuditLog, before, after any) error {
+	meta := RequestMetaFrom(ctx)
+	entry.ImpersonatorID = meta.ImpersonatorID
+	entry.IP = meta.IP
+	entry.UserAgent = meta.UserAgent
+	if len(entry.UserAgent) > maxUserAgentLength {
+		entry.UserAgent = entry.UserAgent[:maxUserAgentLength]
+	}
+	entry.RequestID = meta.RequestID
+	if utf8.RuneCountInString(entry.Detail) > auditMaxValueLength {
+		entry.Detail = string([]rune(entry.Detail)[:auditMaxValueLength])
+	}
+	if before != nil || after != nil {
+		changes, err := auditChanges(before, after)
+		if err != nil {
+			return err
+		}
+		entry.Changes = changes
+	}
+	return s.auditRepo.Create(ctx, entry)
+}
+
+// RecordLogin 记录登录结果：failure 为空表示登录成功；user 为 nil 表示用户名不存在
+// 登录不因审计日志写入失败而失败，只打印日志
+func (s *AuditService) RecordLogin(ctx context.Context, user *model.User, username, method, failure string) {
+	entry := &model.AuditLog{
+		Action:     model.AuditLogin,
+		TargetType: model.AuditTargetUser,
+		Detail:     method + " " + username,
+	}
+	if user != nil {
+		entry.ActorID = user.ID
+		entry.TargetID = user.ID
+		entry.Detail = method + " " + user.Username
+	}
+	if failure != "" {
+		entry.Action = model.AuditLoginFailed
+		entry.Detail += "：" + failure
+	}
+	if err := s.Record(ctx, entry, nil, nil); err != nil {
+		log.Printf("record login audit for %q failed: %v", username, err)
+	}
+}
+
+// RecordLoginResult 根据签发结果记录登录：只拿到挑战 token 时还未完成登录，不记录
+func (s *AuditService) RecordLoginResult(ctx context.Context, user *model.User, method string, resp *dto.LoginResponse, err error) {
+	switch {
+	case err != nil:
+		s.RecordLogin(ctx, user, user.Username, method, err.Error())
+	case resp.Token != "":
+		s.RecordLogin(ctx, user, user.Username, method, "")
+	}
+}
+
+// List 按条件分页查询审计日志
+func (s *AuditService) List(ctx context.Context, req *dto.AuditLogQuery) (*dto.PageResponse[model.AuditLog], error) {
+	req.SetDefaults()
+	if err := validateAuditQuery(req); err != nil {
+		return nil, err
+	}
+
+	logs, total, err := s.auditRepo.List(ctx, auditFilter(req), req.Offset(), req.PageSize)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	return dto.NewPageResponse(logs, total, req.Page, req.PageSize), nil
+}
+
+// Export 按时间先后分批读取符合条件的全部审计日志，每批交给 fn 处理（忽略分页参数）
+func (s *AuditService) Export(ctx context.Context, req *dto.AuditLogQuery, fn func([]model.AuditLog) error) error {
+	if err := validateAuditQuery(req); err != nil {
+		return err
+	}
+
+	filter := auditFilter(req)
+	var afterID uint
+	for {
+		logs, err := s.auditRepo.ListAfter(ctx, filter, afterID, auditExportBatch)
+		if err != nil {
+			return util.ErrDatabase
+		}
+		if len(logs) == 0 {
+			return nil
+		}
+		if err := fn(logs); err != nil {
+			return err
+		}
+		if len(logs) < auditExportBatch {
+			return nil
+		}
+		afterID = logs[len(logs)-1].ID
+	}
+}
+
+func validateAuditQuery(req *dto.AuditLogQuery) error {
+	if req.TargetID > 0 && req.TargetType == "" {
+		return util.ErrInvalidParam.WithMsg("按对象 ID 筛选时需指定 target_type")
+	}
+	if req.From != nil && req.To != nil && req.From.After(*req.To) {
+		return util.ErrInvalidParam.WithMsg("开始时间不能晚于结束时间")
+	}
+	return nil
+}
+
+func auditFilter(req *dto.AuditLogQuery) repository.AuditFilter {
+	return repository.AuditFilter{
+		ActorID:    req.ActorID,
+		Action:     req.Action,
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		From:       req.From,
+		To:         req.To,
+	}
+}
+
+// auditChange 单个字段的变更，创建时没有 from，删除时没有 to
+type auditChange struct {
+	From any `json:"from,omitempty"`
+	To   any `json:"to,omitempty"`
+}
+
+// auditChanges 对比变更前后对象的 JSON 字段，只保留有变化的字段；不返回给前端的字段（密码、密钥等）不会被记录
+func auditChanges(before, after any) (json.RawMessage, error) {
+	from, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]bool, len(from)+len(to))
+	for k := range from {
+		keys[k] = true
+	}
+	for k := range to {
+		keys[k] = true
+	}
+
+	changes := make(map[string]auditChange)
+	for key := range keys {
+		if auditIgnoredFields[key] {
+			continue
+		}
+		oldValue, hasOld := from[key]
+		newValue, hasNew := to[key]
+		if hasOld && hasNew && reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		changes[key] = auditChange{From: auditValue(oldValue), To: auditValue(newValue)}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(changes)
+}
+
+// auditFields 把对象转换为 JSON 字段，nil 返回空 map
+func auditFields(v any) (map[string]any, error) {
+	fields := make(map[string]any)
+	if v == nil {
+		return fields, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// auditValue 截断过长的字符串，避免正文等长文本撑大审计日志
+func auditValue(v any) any {
+	if s, ok := v.(string); ok && utf8.RuneCountInString(s) > auditMaxValueLength {
+		return string([]rune(s)[:auditMaxValueLength]) + "…"
+	}
+	return v
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/util"
+)
+
+// BookmarkService 负责文章收藏
+type BookmarkService struct {
+	bookmarkRepo repository.IBookmarkRepository
+	articleRepo  repository.IArticleRepository
+}
+
+func NewBookmarkService(bookmarkRepo repository.IBookmarkRepository, articleRepo repository.IArticleRepository) *BookmarkService {
+	return &BookmarkService{bookmarkRepo: bookmarkRepo, articleRepo: articleRepo}
+}
+
+// Add 收藏文章，重复收藏不报错
+func (s *BookmarkService) Add(ctx context.Context, userID, articleID uint) error {
+	if _, err := s.articleRepo.GetByID(ctx, articleID); err != nil {
+		return util.ErrArticleNotFound
+	}
+	if _, err := s.bookmarkRepo.Add(ctx, userID, articleID); err != nil {
+		return util.ErrDatabase
+	}
+	return nil
+}
+
+// Remove 取消收藏，未收藏时不报错
+func (s *BookmarkService) Remove(ctx context.Context, userID, articleID uint) error {
+	if _, err := s.bookmarkRepo.Remove(ctx, userID, articleID); err != nil {
+		return util.ErrDatabase
+	}
+	return nil
+}
+
+// List 获取收藏列表
+func (s *BookmarkService) List(ctx context.Context, userID uint, req *dto.ListBookmarksRequest) (*dto.PageResponse[model.Bookmark], error) {
+	req.SetDefaults()
+
+	bookmarks, total, err := s.bookmarkRepo.ListByUserID(ctx, userID, req.Offset(), req.PageSize)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	return dto.NewPageResponse(bookmarks, total, req.Page, req.PageSize), nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+)
+
+// 跨实例广播：outbox 事件只由领取到它的一个实例处理，而实时推送和进程内缓存失效需要每个实例都执行，
+// 这类操作写入 broadcast_messages，由每个实例的监听协程按 ID 轮询后在本实例执行
+
+const (
+	broadcastPollInterval    = time.Second
+	broadcastPollBatch       = 500
+	broadcastWriteTimeout    = 5 * time.Second
+	broadcastGapWait         = 10 * time.Second // 跳过的 ID 可能属于尚未提交的写入，在这段时间内继续查询
+	broadcastMaxGap          = 1000             // 一次跳过的 ID 过多时（自增步长、回滚）不再逐个等待
+	broadcastRetention       = 10 * time.Minute
+	broadcastCleanupInterval = time.Minute
+)
+
+var (
+	// broadcaster 在 StartBroadcastListener 后可用；未启动时（如单元测试）直接在本实例执行
+	broadcaster *broadcastListener
+	// broadcastWake 本实例写入消息后立即轮询一次，不必等到下一个周期
+	broadcastWake = make(chan struct{}, 1)
+)
+
+// realtimeMessage 广播的实时推送内容
+type realtimeMessage struct {
+	Type   string          `json:"type"`
+	Topics []string        `json:"topics"`
+	Data   json.RawMessage `json:"data"`
+}
+
+type broadcastListener struct {
+	repo   repository.IBroadcastRepository
+	cursor uint               // 已处理的最大 ID
+	gaps   map[uint]time.Time // 小于 cursor 但还没读到的 ID 及发现时间
+}
+
+// StartBroadcastListener 启动本实例的广播监听，从启动时最新的消息之后开始接收
+func StartBroadcastListener() {
+	repo := repository.NewBroadcastRepository()
+	cursor, err := repo.LastID(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to start broadcast listener: %v", err)
+	}
+	l := &broadcastListener{repo: repo, cursor: cursor, gaps: make(map[uint]time.Time)}
+	broadcaster = l
+
+	go func() {
+		ticker := time.NewTicker(broadcastPollInterval)
+		defer ticker.Stop()
+		lastCleanup := time.Now()
+		for {
+			l.poll(time.Now())
+			if time.Since(lastCleanup) > broadcastCleanupInterval {
+				lastCleanup = time.Now()
+				if _, err := repo.DeleteBefore(context.Background(), lastCleanup.Add(-broadcastRetention)); err != nil {
+					log.Printf("broadcast cleanup failed: %v", err)
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-broadcastWake:
+			}
+		}
+	}()
+}
+
+// broadcast 写入一条广播消息，所有实例（包括本实例）都会执行
+func broadcast(ctx context.Context, kind string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	if broadcaster == nil {
+		handleBroadcast(kind, data)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), broadcastWriteTimeout)
+	defer cancel()
+	if err := broadcaster.repo.Create(ctx, &model.BroadcastMessage{Kind: kind, Payload: string(data)}); err != nil {
+		return err
+	}
+	select {
+	case broadcastWake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// poll 处理新消息；ID 不连续时记下空缺，之后的轮询中一并查询，等待这些写入提交
+func (l *broadcastListener) poll(now time.Time) {
+	gaps := make([]uint, 0, len(l.gaps))
+	for id, seen := range l.gaps {
+		if now.Sub(seen) > broadcastGapWait {
+			delete(l.gaps, id)
+			continue
+		}
+		gaps = append(gaps, id)
+	}
+
+	msgs, err := l.repo.ListAfter(context.Background(), l.cursor, gaps, broadcastPollBatch)
+	if err != nil {
+		log.Printf("list broadcast messages failed: %v", err)
+		return
+	}
+	for i := range msgs {
+		msg := &msgs[i]
+		if msg.ID > l.cursor {
+			if msg.ID-l.cursor <= broadcastMaxGap {
+				for id := l.cursor + 1; id < msg.ID; id++ {
+					l.gaps[id] = now
+				}
+			}
+			l.cursor = msg.ID
+		}
+		delete(l.gaps, msg.ID)
+		handleBroadcast(msg.Kind, []byte(msg.Payload))
+	}
+}
+
+// handleBroadcast 在本实例执行一条广播消息
+func handleBroadcast(kind string, payload []byte) {
+	switch kind {
+	case model.BroadcastRealtime:
+		var msg realtimeMessage
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			log.Printf("decode realtime broadcast failed: %v", err)
+			return
+		}
+		if _, err := eventHub.Publish(msg.Type, msg.Topics, msg.Data); err != nil {
+			log.Printf("publish %s event failed: %v", msg.Type, err)
+		}
+	case model.BroadcastSitemapExpired:
+		InvalidateSitemap()
+	default:
+		log.Printf("unknown broadcast message kind %q", kind)
+	}
+}
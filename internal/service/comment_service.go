@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/event"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/util"
+)
+
+type CommentService struct {
+	commentRepo repository.ICommentRepository
+	articleRepo repository.IArticleRepository
+	outboxRepo  repository.IOutboxRepository
+	tx          repository.ITransactor
+}
+
+func NewCommentService(commentRepo repository.ICommentRepository, articleRepo repository.IArticleRepository, outboxRepo repository.IOutboxRepository, tx repository.ITransactor) *CommentService {
+	return &CommentService{commentRepo: commentRepo, articleRepo: articleRepo, outboxRepo: outboxRepo, tx: tx}
+}
+
+// Create 发表评论
+func (s *CommentService) Create(ctx context.Context, userID, articleID uint, req *dto.CreateCommentRequest) (*model.Comment, error) {
+	if _, err := s.articleRepo.GetByID(ctx, articleID); err != nil {
+		return nil, util.ErrArticleNotFound
+	}
+
+	comment := &model.Comment{
+		Content:   req.Content,
+		ArticleID: articleID,
+		UserID:    userID,
+		ParentID:  req.ParentID,
+	}
+	// 回复的评论必须属于同一篇文章
+	if req.ParentID != nil {
+		parent, err := s.commentRepo.GetByID(ctx, *req.ParentID)
+		if err != nil || parent.ArticleID != articleID {
+			return nil, util.ErrCommentNotFound.WithMsg("回复的评论不存在")
+		}
+	}
+	// 评论和事件在同一事务中写入，通知和实时推送由 outbox relay 完成
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.commentRepo.Create(ctx, comment); err != nil {
+			return err
+		}
+		return s.outboxRepo.Add(ctx, event.CommentCreated{
+			CommentID: comment.ID,
+			ArticleID: comment.ArticleID,
+			UserID:    comment.UserID,
+			ParentID:  comment.ParentID,
+		})
+	})
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	wakeOutboxRelay()
+	return comment, nil
+}
+
+// List 获取文章的评论列表
+func (s *CommentService) List(ctx context.Context, articleID uint, req *dto.ListCommentsRequest) (*dto.PageResponse[model.Comment], error) {
+	req.SetDefaults()
+
+	if _, err := s.articleRepo.GetByID(ctx, articleID); err != nil {
+		return nil, util.ErrArticleNotFound
+	}
+
+	comments, total, err := s.commentRepo.ListByArticleID(ctx, articleID, req.Offset(), req.PageSize)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	return dto.NewPageResponse(comments, total, req.Page, req.PageSize), nil
+}
+
+// Delete 删除评论：评论者本人或文章作者可以删除
+func (s *CommentService) Delete(ctx context.Context, id, userID uint) error {
+	comment, err := s.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return util.ErrCommentNotFound
+	}
+
+	if comment.UserID != userID {
+		article, err := s.articleRepo.GetByID(ctx, comment.ArticleID)
+		if err != nil || article.UserID != userID {
+			return util.ErrForbidden
+		}
+	}
+
+	if err := s.commentRepo.Delete(ctx, id); err != nil {
+		return util.ErrDatabase
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/event"
+	"go-blog-api/internal/model"
+	"go-blog-api/pkg/events"
+	"go-blog-api/pkg/util"
+)
+
+const (
+	eventReplaySize     = 1000 // 保留最近的事件数，用于 Last-Event-ID 续传
+	eventClientBuffer   = 64   // 每个连接的待发送队列，积压超过后断开该连接
+	eventTicketPurpose  = "event-stream"
+	eventTicketLifetime = time.Hour
+	maxEventTopics      = 50
+)
+
+// 推送的事件类型，与领域事件同名
+const (
+	EventArticleCreated      = event.ArticleCreatedName
+	EventArticleUpdated      = event.ArticleUpdatedName
+	EventArticleDeleted      = event.ArticleDeletedName
+	EventCommentCreated      = event.CommentCreatedName
+	EventNotificationCreated = "notification.created"
+)
+
+// 订阅主题：articles 为全站文章动态；article:{id} 为单篇文章的更新和评论；
+// author:{id} 为某个作者的文章动态；user:{id} 为用户自己的通知，只能订阅自己的
+const (
+	TopicArticles      = "articles"
+	topicArticlePrefix = "article:"
+	topicAuthorPrefix  = "author:"
+	topicUserPrefix    = "user:"
+)
+
+// eventHub 进程内的事件中心；事件经 broadcast 分发到每个实例，各实例推送给自己的连接
+var eventHub = events.NewHub(eventReplaySize, eventClientBuffer)
+
+// EventService 负责实时事件的订阅鉴权和连接凭证
+type EventService struct{}
+
+func NewEventService() *EventService {
+	return &EventService{}
+}
+
+// ResolveTopics 校验请求订阅的主题；未指定时默认订阅全站文章动态和自己的通知
+func (s *EventService) ResolveTopics(userID uint, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return []string{TopicArticles, userTopic(userID)}, nil
+	}
+	if len(requested) > maxEventTopics {
+		return nil, util.ErrInvalidParam.WithMsg(fmt.Sprintf("最多订阅 %d 个主题", maxEventTopics))
+	}
+
+	topics := make([]string, 0, len(requested))
+	for _, t := range uniqueStrings(requested) {
+		if t == TopicArticles {
+			topics = append(topics, t)
+			continue
+		}
+		prefix, id, ok := parseTopic(t)
+		if !ok {
+			return nil, util.ErrInvalidParam.WithMsg("无效的主题: " + t)
+		}
+		if prefix == topicUserPrefix && id != userID {
+			return nil, util.ErrForbidden.WithMsg("不能订阅其他用户的通知")
+		}
+		topics = append(topics, t)
+	}
+	return topics, nil
+}
+
+// Subscribe 订阅事件，lastID 为客户端收到的最后一条事件 ID（0 表示不续传）
+func (s *EventService) Subscribe(topics []string, lastID uint64) (*events.Subscription, []events.Event, bool) {
+	return eventHub.Subscribe(topics, lastID)
+}
+
+// Unsubscribe 取消订阅
+func (s *EventService) Unsubscribe(sub *events.Subscription) {
+	eventHub.Unsubscribe(sub)
+}
+
+// IssueTicket 签发连接凭证：浏览器的 EventSource 和 WebSocket 无法设置 Authorization 头，改用查询参数携带凭证
+// 凭证绑定登录会话，会话被吊销后凭证随之失效
+func (s *EventService) IssueTicket(userID uint, sessionID string) *dto.EventTicketResponse {
+	expiresAt := time.Now().Add(eventTicketLifetime)
+	msg := fmt.Sprintf("%d.%s.%d", userID, sessionID, expiresAt.Unix())
+	return &dto.EventTicketResponse{
+		Ticket:    msg + "." + util.SignMessage(eventTicketPurpose, msg),
+		ExpiresAt: expiresAt,
+	}
+}
+
+// VerifyTicket 校验连接凭证，返回用户 ID 和会话 ID
+func (s *EventService) VerifyTicket(ticket string) (uint, string, error) {
+	msg, signature, ok := cutLast(ticket, ".")
+	if !ok || !util.VerifyMessage(eventTicketPurpose, msg, signature) {
+		return 0, "", util.ErrUnauthorized.WithMsg("无效的连接凭证")
+	}
+	parts := strings.SplitN(msg, ".", 3)
+	if len(parts) != 3 {
+		return 0, "", util.ErrUnauthorized.WithMsg("无效的连接凭证")
+	}
+	userID, err1 := strconv.ParseUint(parts[0], 10, 32)
+	exp, err2 := strconv.ParseInt(parts[2], 10, 64)
+	if err1 != nil || err2 != nil {
+		return 0, "", util.ErrUnauthorized.WithMsg("无效的连接凭证")
+	}
+	if time.Now().Unix() > exp {
+		return 0, "", util.ErrTokenExpired.WithMsg("连接凭证已过期")
+	}
+	return uint(userID), parts[1], nil
+}
+
+// publishEvent 把事件广播给所有实例推送
+func publishEvent(ctx context.Context, eventType string, topics []string, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return broadcast(ctx, model.BroadcastRealtime, realtimeMessage{Type: eventType, Topics: topics, Data: raw})
+}
+
+// publishArticleEvent 推送文章事件，只包含摘要信息，客户端需要正文时再请求详情
+func publishArticleEvent(ctx context.Context, eventType string, info event.ArticleInfo) error {
+	return publishEvent(ctx, eventType, []string{TopicArticles, articleTopic(info.ArticleID), authorTopic(info.UserID)}, articleEventData(info))
+}
+
+func articleTopic(id uint) string { return topicArticlePrefix + strconv.FormatUint(uint64(id), 10) }
+func authorTopic(id uint) string  { return topicAuthorPrefix + strconv.FormatUint(uint64(id), 10) }
+func userTopic(id uint) string    { return topicUserPrefix + strconv.FormatUint(uint64(id), 10) }
+
+// parseTopic 解析 article:{id}、author:{id}、user:{id} 形式的主题
+func parseTopic(topic string) (prefix string, id uint, ok bool) {
+	for _, p := range []string{topicArticlePrefix, topicAuthorPrefix, topicUserPrefix} {
+		if rest, found := strings.CutPrefix(topic, p); found {
+			n, err := strconv.ParseUint(rest, 10, 32)
+			if err != nil || n == 0 {
+				return "", 0, false
+			}
+			return p, uint(n), true
+		}
+	}
+	return "", 0, false
+}
+
+// cutLast 按最后一个分隔符切分字符串
+func cutLast(s, sep string) (before, after string, found bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/event"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// 订阅者名称，记录在 outbox 中用于跳过已处理成功的订阅者，不要随意修改
+const (
+	subscriberSitemap       = "sitemap"
+	subscriberRealtime      = "realtime"
+	subscriberWebhooks      = "webhooks"
+	subscriberNotifications = "notifications"
+)
+
+type articleEvent interface {
+	event.Event
+	Article() event.ArticleInfo
+}
+
+type userEvent interface {
+	event.Event
+	User() event.UserInfo
+}
+
+// registerEventSubscribers 注册领域事件的订阅者；订阅者可能收到重复事件，需要保持幂等
+func registerEventSubscribers(bus *event.Bus) {
+	commentRepo := repository.NewCommentRepository()
+	notifier := NewNotificationService(repository.NewNotificationRepository(), repository.NewUserRepository(),
+		repository.NewCachedArticleRepository(), commentRepo)
+
+	// 站点地图：文章变更时使各实例的缓存失效
+	invalidateSitemap := func(ctx context.Context, _ string, _ event.ArticleInfo, _ event.Meta) error {
+		return broadcast(ctx, model.BroadcastSitemapExpired, nil)
+	}
+	onArticleEvent[event.ArticleCreated](bus, subscriberSitemap, invalidateSitemap)
+	onArticleEvent[event.ArticleUpdated](bus, subscriberSitemap, invalidateSitemap)
+	onArticleEvent[event.ArticleDeleted](bus, subscriberSitemap, invalidateSitemap)
+
+	// 实时推送（SSE / WebSocket），广播到每个实例推送给各自的连接
+	pushArticle := func(ctx context.Context, name string, info event.ArticleInfo, _ event.Meta) error {
+		return publishArticleEvent(ctx, name, info)
+	}
+	onArticleEvent[event.ArticleCreated](bus, subscriberRealtime, pushArticle)
+	onArticleEvent[event.ArticleUpdated](bus, subscriberRealtime, pushArticle)
+	onArticleEvent[event.ArticleDeleted](bus, subscriberRealtime, pushArticle)
+	event.Subscribe(bus, subscriberRealtime, func(ctx context.Context, _ event.Meta, e event.CommentCreated) error {
+		comment, err := commentRepo.GetByID(ctx, e.CommentID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil // 评论已被删除
+		}
+		if err != nil {
+			return err
+		}
+		return publishEvent(ctx, EventCommentCreated, []string{articleTopic(comment.ArticleID)}, comment)
+	})
+
+	// webhook：同一事件重试时使用相同的事件 ID
+	articleWebhook := func(ctx context.Context, name string, info event.ArticleInfo, meta event.Meta) error {
+		return enqueueWebhookEvent(ctx, meta, name, info.UserID, articleEventData(info))
+	}
+	onArticleEvent[event.ArticleCreated](bus, subscriberWebhooks, articleWebhook)
+	onArticleEvent[event.ArticleUpdated](bus, subscriberWebhooks, articleWebhook)
+	onArticleEvent[event.ArticleDeleted](bus, subscriberWebhooks, articleWebhook)
+	userWebhook := func(ctx context.Context, name string, info event.UserInfo, meta event.Meta) error {
+		return enqueueWebhookEvent(ctx, meta, name, info.UserID, dto.UserEventData{
+			ID:        info.UserID,
+			Username:  info.Username,
+			Avatar:    info.Avatar,
+			CreatedAt: info.CreatedAt,
+		})
+	}
+	onUserEvent[event.UserRegistered](bus, subscriberWebhooks, userWebhook)
+	onUserEvent[event.UserUpdated](bus, subscriberWebhooks, userWebhook)
+	onUserEvent[event.UserDeleted](bus, subscriberWebhooks, userWebhook)
+
+	// 站内通知：评论、回复、@提及、关注和回应；事件 ID 与接收者唯一，重复投递不会重复通知
+	event.Subscribe(bus, subscriberNotifications, func(ctx context.Context, meta event.Meta, e event.CommentCreated) error {
+		return notifier.dispatch(ctx, notificationEvent{EventID: meta.ID, Type: model.NotificationComment, ActorID: e.UserID, CommentID: e.CommentID})
+	})
+	event.Subscribe(bus, subscriberNotifications, func(ctx context.Context, meta event.Meta, e event.UserFollowed) error {
+		return notifier.dispatch(ctx, notificationEvent{EventID: meta.ID, Type: model.NotificationFollow, ActorID: e.FollowerID, UserID: e.FolloweeID})
+	})
+	event.Subscribe(bus, subscriberNotifications, func(ctx context.Context, meta event.Meta, e event.ReactionAdded) error {
+		ev := notificationEvent{EventID: meta.ID, Type: model.NotificationReaction, ActorID: e.UserID, Reaction: e.Reaction, TargetType: e.TargetType}
+		if e.TargetType == model.ReactionTargetComment {
+			ev.CommentID = e.TargetID
+		} else {
+			ev.ArticleID = e.TargetID
+		}
+		return notifier.dispatch(ctx, ev)
+	})
+}
+
+func onArticleEvent[T articleEvent](bus *event.Bus, subscriber string, fn func(ctx context.Context, name string, info event.ArticleInfo, meta event.Meta) error) {
+	event.Subscribe(bus, subscriber, func(ctx context.Context, meta event.Meta, e T) error {
+		return fn(ctx, e.EventName(), e.Article(), meta)
+	})
+}
+
+func onUserEvent[T userEvent](bus *event.Bus, subscriber string, fn func(ctx context.Context, name string, info event.UserInfo, meta event.Meta) error) {
+	event.Subscribe(bus, subscriber, func(ctx context.Context, meta event.Meta, e T) error {
+		return fn(ctx, e.EventName(), e.User(), meta)
+	})
+}
+
+// enqueueWebhookEvent 为订阅的 webhook 生成投递任务；webhook 投递未启动时忽略
+func enqueueWebhookEvent(ctx context.Context, meta event.Meta, name string, ownerID uint, data any) error {
+	if webhookDispatcher == nil {
+		return nil
+	}
+	return webhookDispatcher.enqueue(ctx, fmt.Sprintf("evt_%d", meta.ID), name, ownerID, data, meta.OccurredAt)
+}
+
+// articleInfo 文章事件的摘要信息
+func articleInfo(article *model.Article) event.ArticleInfo {
+	return event.ArticleInfo{
+		ArticleID: article.ID,
+		UserID:    article.UserID,
+		Slug:      article.Slug,
+		Title:     article.Title,
+		UpdatedAt: article.UpdatedAt,
+	}
+}
+
+// userInfo 用户事件的公开信息
+func userInfo(user *model.User) event.UserInfo {
+	return event.UserInfo{
+		UserID:    user.ID,
+		Username:  user.Username,
+		Avatar:    user.Avatar,
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+func articleEventData(info event.ArticleInfo) dto.ArticleEventData {
+	return dto.ArticleEventData{
+		ID:        info.ArticleID,
+		Slug:      info.Slug,
+		Title:     info.Title,
+		UserID:    info.UserID,
+		UpdatedAt: info.UpdatedAt,
+	}
+}
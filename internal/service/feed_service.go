@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/config"
+	"go-blog-api/pkg/feed"
+	"go-blog-api/pkg/markup"
+	"go-blog-api/pkg/util"
+)
+
+const (
+	defaultFeedItems  = 20
+	maxFeedItems      = 100
+	defaultSummaryLen = 200
+)
+
+// FeedService 生成全站、作者和标签维度的订阅源（所有未删除的文章都视为已发布）
+type FeedService struct {
+	articleRepo repository.IArticleRepository
+	userRepo    repository.IUserRepository
+	tagRepo     repository.ITagRepository
+}
+
+func NewFeedService(articleRepo repository.IArticleRepository, userRepo repository.IUserRepository, tagRepo repository.ITagRepository) *FeedService {
+	return &FeedService{articleRepo: articleRepo, userRepo: userRepo, tagRepo: tagRepo}
+}
+
+// Build 构建订阅源，authorID 为 0 表示全站
+func (s *FeedService) Build(ctx context.Context, authorID uint) (*feed.Feed, error) {
+	cfg := config.AppConfig.Site
+	site := strings.TrimRight(cfg.URL, "/")
+	limit := feedLimit()
+	f := newFeed(site)
+
+	var articles []model.Article
+	var err error
+	if authorID == 0 {
+		articles, _, err = s.articleRepo.List(ctx, 0, limit)
+	} else {
+		author, uerr := s.userRepo.GetByID(ctx, authorID)
+		if uerr != nil {
+			return nil, util.ErrUserNotFound
+		}
+		f.Title = fmt.Sprintf("%s - %s", author.Username, cfg.Title)
+		f.Description = fmt.Sprintf("%s 的最新文章", author.Username)
+		f.Link = authorURL(site, author.ID)
+		articles, _, err = s.articleRepo.ListByUserID(ctx, authorID, 0, limit)
+	}
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+
+	s.addItems(f, articles, site)
+	if err := s.touch(ctx, f, authorID, 0); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// BuildTag 构建标签订阅源
+func (s *FeedService) BuildTag(ctx context.Context, slug string) (*feed.Feed, error) {
+	cfg := config.AppConfig.Site
+	site := strings.TrimRight(cfg.URL, "/")
+
+	tag, err := s.tagRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, util.ErrTagNotFound
+	}
+	f := newFeed(site)
+	f.Title = fmt.Sprintf("%s - %s", tag.Name, cfg.Title)
+	f.Description = fmt.Sprintf("标签「%s」的最新文章", tag.Name)
+	f.Link = tagURL(site, tag.Slug)
+
+	articles, _, err := s.articleRepo.ListByTag(ctx, tag.ID, 0, feedLimit())
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	s.addItems(f, articles, site)
+	if tag.ArticlesChangedAt != nil && tag.ArticlesChangedAt.After(f.Updated) {
+		f.Updated = *tag.ArticlesChangedAt
+	}
+	if err := s.touch(ctx, f, 0, tag.ID); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// touch 订阅源更新时间至少为范围内文章最近一次修改或删除的时间：
+// 只看当前条目时，删除最新的文章会让更新时间倒退，阅读器和缓存会认为内容没有变化
+func (s *FeedService) touch(ctx context.Context, f *feed.Feed, authorID, tagID uint) error {
+	last, err := s.articleRepo.LastModified(ctx, authorID, tagID)
+	if err != nil {
+		return util.ErrDatabase
+	}
+	if last.After(f.Updated) {
+		f.Updated = last
+	}
+	return nil
+}
+
+// feedLimit 订阅源条目数，取配置值并限制在合理范围内
+func feedLimit() int {
+	limit := config.AppConfig.Feed.ItemCount
+	if limit <= 0 {
+		limit = defaultFeedItems
+	}
+	if limit > maxFeedItems {
+		limit = maxFeedItems
+	}
+	return limit
+}
+
+// newFeed 以站点信息初始化订阅源
+func newFeed(site string) *feed.Feed {
+	cfg := config.AppConfig.Site
+	return &feed.Feed{
+		Title:       cfg.Title,
+		Description: cfg.Description,
+		Link:        site,
+		Language:    cfg.Language,
+		Updated:     time.Unix(0, 0), // 没有文章时使用固定值，保证响应稳定可缓存
+	}
+}
+
+// addItems 追加条目，订阅源更新时间取条目中最新的更新时间
+func (s *FeedService) addItems(f *feed.Feed, articles []model.Article, site string) {
+	for i := range articles {
+		item := s.feedItem(&articles[i], site)
+		if item.Updated.After(f.Updated) {
+			f.Updated = item.Updated
+		}
+		f.Items = append(f.Items, item)
+	}
+}
+
+func (s *FeedService) feedItem(article *model.Article, site string) feed.Item {
+	cfg := config.AppConfig.Feed
+	summaryLen := cfg.SummaryLen
+	if summaryLen <= 0 {
+		summaryLen = defaultSummaryLen
+	}
+
+	contentHTML := article.ContentHTML
+	if contentHTML == "" && article.Content != "" {
+		// 历史数据尚未生成渲染缓存
+		contentHTML, _ = markup.Render(article.ContentFormat, article.Content)
+	}
+
+	item := feed.Item{
+		ID:        fmt.Sprintf("%s/articles/%d", site, article.ID), // 使用 ID 而非 slug，修改标题后阅读器不会当作新文章
+		Title:     article.Title,
+		Link:      articleURL(site, article),
+		Summary:   markup.Summary(contentHTML, summaryLen),
+		Published: article.CreatedAt,
+		Updated:   article.UpdatedAt,
+	}
+	if cfg.FullContent {
+		item.Content = contentHTML
+	}
+	if article.User != nil {
+		item.Author = feed.Person{Name: article.User.Username, URL: authorURL(site, article.User.ID)}
+	}
+	return item
+}
+
+// articleURL 前端文章页地址，历史数据没有 slug 时使用 ID
+func articleURL(site string, article *model.Article) string {
+	if article.Slug != "" {
+		return site + "/articles/" + article.Slug
+	}
+	return fmt.Sprintf("%s/articles/%d", site, article.ID)
+}
+
+// authorURL 前端作者页地址
+func authorURL(site string, userID uint) string {
+	return fmt.Sprintf("%s/users/%d", site, userID)
+}
+
+// tagURL 前端标签页地址
+func tagURL(site, slug string) string {
+	return site + "/tags/" + slug
+}
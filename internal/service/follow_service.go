@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/event"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/util"
+)
+
+// FollowService 负责关注关系和基于关注的首页时间线
+type FollowService struct {
+	followRepo  repository.IFollowRepository
+	userRepo    repository.IUserRepository
+	articleRepo repository.IArticleRepository
+	outboxRepo  repository.IOutboxRepository
+	tx          repository.ITransactor
+}
+
+func NewFollowService(followRepo repository.IFollowRepository, userRepo repository.IUserRepository, articleRepo repository.IArticleRepository, outboxRepo repository.IOutboxRepository, tx repository.ITransactor) *FollowService {
+	return &FollowService{followRepo: followRepo, userRepo: userRepo, articleRepo: articleRepo, outboxRepo: outboxRepo, tx: tx}
+}
+
+// Follow 关注用户，重复关注不报错
+func (s *FollowService) Follow(ctx context.Context, followerID, followeeID uint) error {
+	if followerID == followeeID {
+		return util.ErrBadRequest.WithMsg("不能关注自己")
+	}
+	if _, err := s.userRepo.GetByID(ctx, followeeID); err != nil {
+		return util.ErrUserNotFound
+	}
+
+	// 关注关系和事件在同一事务中写入，通知由 outbox relay 投递；重复关注不产生事件
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		added, err := s.followRepo.Add(ctx, followerID, followeeID)
+		if err != nil || !added {
+			return err
+		}
+		return s.outboxRepo.Add(ctx, event.UserFollowed{FollowerID: followerID, FolloweeID: followeeID})
+	})
+	if err != nil {
+		return util.ErrDatabase
+	}
+	wakeOutboxRelay()
+	return nil
+}
+
+// Unfollow 取消关注，未关注时不报错
+func (s *FollowService) Unfollow(ctx context.Context, followerID, followeeID uint) error {
+	if _, err := s.followRepo.Remove(ctx, followerID, followeeID); err != nil {
+		return util.ErrDatabase
+	}
+	return nil
+}
+
+// Stats 获取用户的粉丝数、关注数，以及当前用户是否已关注
+func (s *FollowService) Stats(ctx context.Context, userID, viewerID uint) (*dto.FollowStatsResponse, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, util.ErrUserNotFound
+	}
+
+	followers, err := s.followRepo.CountFollowers(ctx, userID)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	following, err := s.followRepo.CountFollowing(ctx, userID)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+
+	resp := &dto.FollowStatsResponse{Followers: followers, Following: following}
+	if viewerID != 0 && viewerID != userID {
+		if resp.FollowedByMe, err = s.followRepo.Exists(ctx, viewerID, userID); err != nil {
+			return nil, util.ErrDatabase
+		}
+	}
+	return resp, nil
+}
+
+// Followers 获取粉丝列表
+func (s *FollowService) Followers(ctx context.Context, userID uint, req *dto.ListFollowsRequest) (*dto.PageResponse[model.User], error) {
+	req.SetDefaults()
+
+	users, total, err := s.followRepo.ListFollowers(ctx, userID, req.Offset(), req.PageSize)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	return dto.NewPageResponse(users, total, req.Page, req.PageSize), nil
+}
+
+// Following 获取关注列表
+func (s *FollowService) Following(ctx context.Context, userID uint, req *dto.ListFollowsRequest) (*dto.PageResponse[model.User], error) {
+	req.SetDefaults()
+
+	users, total, err := s.followRepo.ListFollowing(ctx, userID, req.Offset(), req.PageSize)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	return dto.NewPageResponse(users, total, req.Page, req.PageSize), nil
+}
+
+// Feed 获取关注作者的最新文章（游标分页）
+func (s *FollowService) Feed(ctx context.Context, userID uint, req *dto.CursorRequest) (*dto.CursorResponse[model.Article], error) {
+	req.SetDefaults()
+
+	var beforeID uint
+	if req.Cursor != "" {
+		id, err := decodeCursor(req.Cursor)
+		if err != nil {
+			return nil, util.ErrInvalidParam.WithMsg("无效的游标")
+		}
+		beforeID = id
+	}
+
+	// 多取一条判断是否还有下一页
+	articles, err := s.articleRepo.ListFeed(ctx, userID, beforeID, req.Limit+1)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+
+	resp := &dto.CursorResponse[model.Article]{List: articles}
+	if len(articles) > req.Limit {
+		resp.List = articles[:req.Limit]
+		resp.NextCursor = encodeCursor(resp.List[req.Limit-1].ID)
+	}
+	return resp, nil
+}
+
+// 游标对客户端不透明，避免客户端依赖其内部结构
+func encodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+func decodeCursor(cursor string) (uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseUint(string(raw), 10, 32)
+	return uint(id), err
+}
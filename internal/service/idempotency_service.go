@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/config"
+	"go-blog-api/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultIdempotencyTTL      = 24 * time.Hour
+	defaultIdempotencyLock     = time.Minute
+	idempotencyCleanupInterval = time.Hour
+	// idempotencyBeginAttempts 记录被并发删除或接管时的重试次数
+	idempotencyBeginAttempts = 3
+)
+
+// errIdempotencyLockLost 锁已到期并被其他请求接管，本次的保存或释放没有生效
+var errIdempotencyLockLost = errors.New("idempotency key taken over by another request")
+
+// IdempotencyService 负责 Idempotency-Key：同一用户的同一 key 只执行一次，重试时返回首次响应
+type IdempotencyService struct {
+	repo repository.IIdempotencyRepository
+	ttl  time.Duration
+	lock time.Duration
+}
+
+func NewIdempotencyService(repo repository.IIdempotencyRepository) *IdempotencyService {
+	cfg := config.AppConfig.Idempotency
+	ttl := time.Duration(cfg.TTLSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	lock := time.Duration(cfg.LockSeconds) * time.Second
+	if lock <= 0 {
+		lock = defaultIdempotencyLock
+	}
+	return &IdempotencyService{repo: repo, ttl: ttl, lock: lock}
+}
+
+// Begin 开始处理带 Idempotency-Key 的请求
+// 返回的记录未完成时表示当前请求获得执行权，处理后须调用 Complete 或 Release；已完成时直接返回其中保存的响应
+// key 已用于不同的请求时返回 ErrIdempotencyReused，首个请求仍在处理中时返回 ErrIdempotencyBusy
+func (s *IdempotencyService) Begin(ctx context.Context, userID uint, key, requestHash string) (*model.IdempotencyKey, error) {
+	for range idempotencyBeginAttempts {
+		now := time.Now()
+		token, err := randomHex(16)
+		if err != nil {
+			return nil, util.ErrInternal
+		}
+		record := &model.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			RequestHash: requestHash,
+			LockedUntil: now.Add(s.lock),
+			LockToken:   token,
+			ExpiresAt:   now.Add(s.ttl),
+		}
+		err = s.repo.Create(ctx, record)
+		if err == nil {
+			return record, nil
+		}
+		if !errors.Is(err, repository.ErrDuplicateIdempotencyKey) {
+			return nil, util.ErrDatabase
+		}
+
+		existing, err := s.repo.Get(ctx, userID, key)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue // 刚被释放，重新创建
+		}
+		if err != nil {
+			return nil, util.ErrDatabase
+		}
+
+		expired := !existing.ExpiresAt.After(now)
+		if !expired && existing.RequestHash != requestHash {
+			return nil, util.ErrIdempotencyReused
+		}
+		// 已过期，或首个请求处理中断（锁已到期），由当前请求接管重新执行
+		if expired || (!existing.Completed() && !existing.LockedUntil.After(now)) {
+			record.ID = existing.ID
+			record.CreatedAt = existing.CreatedAt
+			ok, err := s.repo.TakeOver(ctx, record, now)
+			if err != nil {
+				return nil, util.ErrDatabase
+			}
+			if ok {
+				return record, nil
+			}
+			continue // 被其他请求抢先接管
+		}
+		if !existing.Completed() {
+			return nil, util.ErrIdempotencyBusy
+		}
+		return existing, nil
+	}
+	return nil, util.ErrIdempotencyBusy
+}
+
+// Complete 保存首次响应，之后使用相同 key 的重试直接返回该响应
+// 处理超过锁时长、记录已被其他请求接管时不覆盖对方的结果，返回 errIdempotencyLockLost
+func (s *IdempotencyService) Complete(ctx context.Context, record *model.IdempotencyKey, statusCode int, contentType string, body []byte) error {
+	ok, err := s.repo.Complete(ctx, record.ID, record.LockToken, statusCode, contentType, body)
+	if err != nil {
+		return util.ErrDatabase
+	}
+	if !ok {
+		return errIdempotencyLockLost
+	}
+	return nil
+}
+
+// Release 放弃执行权（处理失败时），之后的重试会重新执行；记录已被接管时不删除，返回 errIdempotencyLockLost
+func (s *IdempotencyService) Release(ctx context.Context, record *model.IdempotencyKey) error {
+	ok, err := s.repo.Delete(ctx, record.ID, record.LockToken)
+	if err != nil {
+		return util.ErrDatabase
+	}
+	if !ok {
+		return errIdempotencyLockLost
+	}
+	return nil
+}
+
+// StartIdempotencyCleanup 定期清理过期的幂等记录
+func StartIdempotencyCleanup() {
+	repo := repository.NewIdempotencyRepository()
+	go func() {
+		ticker := time.NewTicker(idempotencyCleanupInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := repo.DeleteExpired(context.Background(), time.Now()); err != nil {
+				log.Printf("idempotency cleanup failed: %v", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"io"
+	"log"
+	"time"
+
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/config"
+	"go-blog-api/pkg/imaging"
+	"go-blog-api/pkg/storage"
+)
+
+const (
+	mediaQueueSize      = 1024
+	mediaSweepInterval  = 5 * time.Minute // 补偿扫描间隔：处理重启前或队列溢出时遗留的图片
+	mediaSweepBatch     = 100
+	mediaProcessTimeout = 2 * time.Minute
+)
+
+// imageVariantSpec 派生尺寸规格：square 为居中裁剪的正方形，否则等比缩放到 size x size 以内
+type imageVariantSpec struct {
+	name   string
+	size   int
+	square bool
+}
+
+var (
+	avatarVariants = []imageVariantSpec{
+		{name: "avatar_64", size: 64, square: true},
+		{name: "avatar_128", size: 128, square: true},
+		{name: "avatar_256", size: 256, square: true},
+	}
+	attachmentVariants = []imageVariantSpec{
+		{name: "thumbnail", size: 200, square: true},
+		{name: "medium", size: 800},
+		{name: "large", size: 1600},
+	}
+)
+
+// mediaQueue 待处理的图片 ID，上传接口只负责入队
+var mediaQueue = make(chan uint, mediaQueueSize)
+
+// StartMediaWorkers 启动后台图片处理协程和补偿扫描
+func StartMediaWorkers(store storage.BlobStore) {
+	svc := NewMediaService(repository.NewMediaRepository(), repository.NewUserRepository(), store, repository.NewTransactor())
+	workers := config.AppConfig.Storage.ImageWorkers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for id := range mediaQueue {
+				ctx, cancel := context.WithTimeout(context.Background(), mediaProcessTimeout)
+				if err := svc.Process(ctx, id); err != nil {
+					log.Printf("media %d processing failed: %v", id, err)
+				}
+				cancel()
+			}
+		}()
+	}
+
+	go func() {
+		svc.sweepPending(context.Background(), time.Now())
+		ticker := time.NewTicker(mediaSweepInterval)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			svc.sweepPending(context.Background(), now.Add(-mediaSweepInterval))
+		}
+	}()
+}
+
+// enqueueMediaProcessing 非阻塞入队，队列满时留给补偿扫描处理
+func enqueueMediaProcessing(id uint) {
+	select {
+	case mediaQueue <- id:
+	default:
+		log.Printf("media queue full, media %d will be processed by sweep", id)
+	}
+}
+
+func (s *MediaService) sweepPending(ctx context.Context, before time.Time) {
+	ids, err := s.mediaRepo.ListPendingIDs(ctx, before, mediaSweepBatch)
+	if err != nil {
+		log.Printf("media sweep failed: %v", err)
+		return
+	}
+	for _, id := range ids {
+		enqueueMediaProcessing(id)
+	}
+}
+
+// Process 生成图片的派生尺寸并记录原图宽高；失败时标记为 failed，原图仍可正常访问
+func (s *MediaService) Process(ctx context.Context, id uint) error {
+	media, err := s.mediaRepo.GetByID(ctx, id)
+	if err != nil || media.Status != model.MediaStatusPending {
+		return nil // 已删除或已处理（补偿扫描可能重复入队）
+	}
+
+	variants, err := s.buildVariants(ctx, media)
+	if err != nil {
+		_ = s.mediaRepo.UpdateStatus(ctx, id, model.MediaStatusFailed)
+		return err
+	}
+	return s.mediaRepo.SaveProcessed(ctx, media, variants)
+}
+
+func (s *MediaService) buildVariants(ctx context.Context, media *model.Media) ([]model.MediaVariant, error) {
+	reader, err := s.store.Open(ctx, media.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := imaging.Decode(data, media.Orientation, config.AppConfig.Storage.MaxImagePixels)
+	if err != nil {
+		return nil, err
+	}
+	media.Width, media.Height = img.Bounds().Dx(), img.Bounds().Dy()
+
+	specs := attachmentVariants
+	if media.Kind == model.MediaKindAvatar {
+		specs = avatarVariants
+	}
+
+	variants := make([]model.MediaVariant, 0, len(specs))
+	for _, spec := range specs {
+		var resized *image.NRGBA
+		if spec.square {
+			resized = imaging.Square(img, spec.size)
+		} else {
+			resized = imaging.Fit(img, spec.size, spec.size)
+		}
+		encoded, mimeType, err := imaging.Encode(resized)
+		if err != nil {
+			return nil, err
+		}
+
+		// 派生图同样按原图内容寻址，相同内容的多条记录共享
+		key := media.StorageKey + "_" + spec.name
+		exists, err := s.store.Exists(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			if err := s.store.Put(ctx, key, bytes.NewReader(encoded), int64(len(encoded)), mimeType); err != nil {
+				return nil, err
+			}
+		}
+
+		variants = append(variants, model.MediaVariant{
+			MediaID:    media.ID,
+			Name:       spec.name,
+			StorageKey: key,
+			MimeType:   mimeType,
+			Width:      resized.Bounds().Dx(),
+			Height:     resized.Bounds().Dy(),
+			Size:       int64(len(encoded)),
+		})
+	}
+	return variants, nil
+}
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/config"
+	"go-blog-api/pkg/imaging"
+	"go-blog-api/pkg/storage"
+	"go-blog-api/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+const mediaURLPurpose = "media-download"
+
+// 各用途允许的文件类型（以服务端嗅探结果为准，不信任客户端声明的 Content-Type）
+var (
+	avatarMimeTypes     = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+	attachmentMimeTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp", "application/pdf", "text/plain; charset=utf-8", "application/zip"}
+)
+
+// DownloadFile 待下载的文件（原图或某个派生尺寸）
+type DownloadFile struct {
+	Media      *model.Media
+	Variant    string // 为空表示原图
+	StorageKey string
+	MimeType   string
+	Size       int64
+	Reader     io.ReadCloser
+}
+
+// MediaService 负责文件上传、去重存储、签名下载地址
+type MediaService struct {
+	mediaRepo repository.IMediaRepository
+	userRepo  repository.IUserRepository
+	store     storage.BlobStore
+	tx        repository.ITransactor
+}
+
+func NewMediaService(mediaRepo repository.IMediaRepository, userRepo repository.IUserRepository, store storage.BlobStore, tx repository.ITransactor) *MediaService {
+	return &MediaService{mediaRepo: mediaRepo, userRepo: userRepo, store: store, tx: tx}
+}
+
+// MaxUploadSize 各用途允许的最大文件大小（字节）
+func MaxUploadSize(kind string) int64 {
+	cfg := config.AppConfig.Storage
+	if kind == model.MediaKindAvatar {
+		return int64(cfg.MaxAvatarMB) << 20
+	}
+	return int64(cfg.MaxAttachmentMB) << 20
+}
+
+// Upload 保存上传的文件；头像上传成功后同时更新用户的 Avatar
+func (s *MediaService) Upload(ctx context.Context, userID uint, kind, filename string, data []byte) (*model.Media, error) {
+	// 1. 大小与类型校验
+	if int64(len(data)) > MaxUploadSize(kind) {
+		return nil, util.ErrFileTooLarge
+	}
+	if len(data) == 0 {
+		return nil, util.ErrInvalidParam.WithMsg("文件为空")
+	}
+	mimeType := http.DetectContentType(data)
+	allowed := attachmentMimeTypes
+	if kind == model.MediaKindAvatar {
+		allowed = avatarMimeTypes
+	}
+	if !slices.Contains(allowed, mimeType) {
+		return nil, util.ErrUnsupportedMedia.WithMsg("不支持的文件类型: " + mimeType)
+	}
+
+	// 2. 图片先去除 EXIF 等元数据
+	isImage := strings.HasPrefix(mimeType, "image/")
+	if isImage {
+		var err error
+		if data, mimeType, err = sanitizeImage(data, mimeType); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. 同一用户重复上传相同内容时直接复用（按实际存储的内容去重，与存储 key 一致）
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	media, err := s.mediaRepo.GetByUserAndHash(ctx, userID, kind, hash)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, util.ErrDatabase
+	}
+
+	if media == nil {
+		key := fmt.Sprintf("%s/%s/%s", hash[:2], hash[2:4], hash)
+		media = &model.Media{
+			UserID:       userID,
+			Kind:         kind,
+			StorageKey:   key,
+			ContentHash:  hash,
+			MimeType:     mimeType,
+			Size:         int64(len(data)),
+			OriginalName: sanitizeFilename(filename),
+			Status:       model.MediaStatusReady,
+		}
+		if isImage {
+			media.Status = model.MediaStatusPending
+		}
+		// 4. 按内容哈希存储，不同用户上传的相同文件只保存一份；
+		// 持有存储对象的锁行写入记录，与删除最后一个引用互斥
+		if err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+			if err := s.mediaRepo.LockBlob(ctx, key); err != nil {
+				return err
+			}
+			exists, err := s.store.Exists(ctx, key)
+			if err != nil {
+				return util.ErrInternal.WithMsg("文件存储失败")
+			}
+			if !exists {
+				if err := s.store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), mimeType); err != nil {
+					return util.ErrInternal.WithMsg("文件存储失败")
+				}
+			}
+			return s.mediaRepo.Create(ctx, media)
+		}); err != nil {
+			return nil, mediaError(err)
+		}
+
+		// 5. 缩略图等派生尺寸异步生成，不阻塞上传请求
+		if isImage {
+			enqueueMediaProcessing(media.ID)
+		}
+	}
+	s.fillURLs(media, time.Now())
+
+	// 6. 头像直接生效
+	if kind == model.MediaKindAvatar {
+		if err := s.userRepo.UpdateAvatar(ctx, userID, media.URL); err != nil {
+			return nil, util.ErrDatabase
+		}
+	}
+
+	return media, nil
+}
+
+// Get 获取文件详情（含新的下载地址），只能查看自己的文件
+func (s *MediaService) Get(ctx context.Context, userID, id uint) (*model.Media, error) {
+	media, err := s.mediaRepo.GetByID(ctx, id)
+	if err != nil || media.UserID != userID {
+		return nil, util.ErrMediaNotFound
+	}
+	s.fillURLs(media, time.Now())
+	return media, nil
+}
+
+// List 获取当前用户上传的文件
+func (s *MediaService) List(ctx context.Context, userID uint, req *dto.PageRequest) (*dto.PageResponse[model.Media], error) {
+	req.SetDefaults()
+
+	list, total, err := s.mediaRepo.ListByUserID(ctx, userID, req.Offset(), req.PageSize)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	now := time.Now()
+	for i := range list {
+		s.fillURLs(&list[i], now)
+	}
+
+	return dto.NewPageResponse(list, total, req.Page, req.PageSize), nil
+}
+
+// Delete 删除文件，存储对象在没有任何记录引用后才真正删除
+func (s *MediaService) Delete(ctx context.Context, userID, id uint) error {
+	media, err := s.mediaRepo.GetByID(ctx, id)
+	if err != nil || media.UserID != userID {
+		return util.ErrMediaNotFound
+	}
+
+	// 引用计数和删除存储对象都在存储对象的锁行下进行，并发上传会等待本事务结束后重新写入
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.mediaRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		if err := s.mediaRepo.LockBlob(ctx, media.StorageKey); err != nil {
+			return err
+		}
+		count, err := s.mediaRepo.CountByStorageKey(ctx, media.StorageKey)
+		if err != nil || count > 0 {
+			return err
+		}
+		if err := s.mediaRepo.DeleteBlob(ctx, media.StorageKey); err != nil {
+			return err
+		}
+		// 存储对象删除失败只会留下孤儿文件，不影响本次操作结果
+		_ = s.store.Delete(ctx, media.StorageKey)
+		for _, v := range media.Variants {
+			_ = s.store.Delete(ctx, v.StorageKey)
+		}
+		return nil
+	})
+	if err != nil {
+		return util.ErrDatabase
+	}
+
+	// 删除的是当前头像时恢复默认头像
+	if media.Kind == model.MediaKindAvatar {
+		if user, err := s.userRepo.GetByID(ctx, userID); err == nil && user.Avatar == s.mediaURL(media, time.Now()) {
+			if err := s.userRepo.UpdateAvatar(ctx, userID, defaultAvatar()); err != nil {
+				return util.ErrDatabase
+			}
+		}
+	}
+	return nil
+}
+
+// mediaError 事务中返回的业务错误原样返回，其他错误视为数据库错误
+func mediaError(err error) error {
+	var bizErr *util.BizError
+	if errors.As(err, &bizErr) {
+		return bizErr
+	}
+	return util.ErrDatabase
+}
+
+// Open 校验下载地址并打开文件：头像公开访问，附件需要有效的签名
+// variant 为空时返回原图；指定的派生尺寸尚未生成时回退到原图
+func (s *MediaService) Open(ctx context.Context, id uint, variant, expires, signature string) (*DownloadFile, error) {
+	media, err := s.mediaRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, util.ErrMediaNotFound
+	}
+
+	if media.Kind != model.MediaKindAvatar {
+		exp, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil || time.Now().Unix() > exp ||
+			!util.VerifyMessage(mediaURLPurpose, fmt.Sprintf("%d:%d", media.ID, exp), signature) {
+			return nil, util.ErrForbidden.WithMsg("下载链接无效或已过期")
+		}
+	}
+
+	file := &DownloadFile{Media: media, StorageKey: media.StorageKey, MimeType: media.MimeType, Size: media.Size}
+	if variant != "" {
+		found := false
+		for _, v := range media.Variants {
+			if v.Name == variant {
+				file.StorageKey, file.MimeType, file.Size, file.Variant = v.StorageKey, v.MimeType, v.Size, v.Name
+				found = true
+				break
+			}
+		}
+		if !found && media.Status != model.MediaStatusPending {
+			return nil, util.ErrMediaNotFound.WithMsg("图片尺寸不存在")
+		}
+	}
+
+	file.Reader, err = s.store.Open(ctx, file.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, util.ErrMediaNotFound
+		}
+		return nil, util.ErrInternal
+	}
+	return file, nil
+}
+
+// fillURLs 填充原图和各派生尺寸的下载地址
+func (s *MediaService) fillURLs(media *model.Media, now time.Time) {
+	media.URL = s.mediaURL(media, now)
+	for i := range media.Variants {
+		media.Variants[i].URL = s.variantURL(media, media.Variants[i].Name, now)
+	}
+}
+
+// variantURL 派生尺寸地址为原图地址加上尺寸名，签名参数与原图相同
+func (s *MediaService) variantURL(media *model.Media, name string, now time.Time) string {
+	raw := s.mediaURL(media, now)
+	base, query, _ := strings.Cut(raw, "?")
+	if query == "" {
+		return base + "/" + name
+	}
+	return base + "/" + name + "?" + query
+}
+
+// mediaURL 生成下载地址：头像为固定地址，附件为带过期时间的签名地址
+func (s *MediaService) mediaURL(media *model.Media, now time.Time) string {
+	cfg := config.AppConfig.Storage
+	base := strings.TrimSuffix(cfg.PublicBaseURL, "/") + fmt.Sprintf("/api/v1/media/%d/file", media.ID)
+	if media.Kind == model.MediaKindAvatar {
+		return base
+	}
+
+	exp := now.Add(time.Duration(cfg.SignedURLMinutes) * time.Minute).Unix()
+	q := url.Values{}
+	q.Set("expires", strconv.FormatInt(exp, 10))
+	q.Set("signature", util.SignMessage(mediaURLPurpose, fmt.Sprintf("%d:%d", media.ID, exp)))
+	return base + "?" + q.Encode()
+}
+
+// sanitizeImage 去除图片元数据；带 EXIF 旋转标记的照片按方向重新编码，
+// 原图地址直接用作头像，不能依赖已被去掉的方向标记
+func sanitizeImage(data []byte, mimeType string) ([]byte, string, error) {
+	orientation := imaging.JPEGOrientation(data)
+	stripped, err := imaging.StripMetadata(data)
+	if err != nil {
+		return nil, "", util.ErrUnsupportedMedia.WithMsg("图片文件已损坏或格式不正确")
+	}
+	if orientation == 1 {
+		return stripped, mimeType, nil
+	}
+
+	img, err := imaging.Decode(stripped, orientation, config.AppConfig.Storage.MaxImagePixels)
+	if errors.Is(err, imaging.ErrTooLarge) {
+		return nil, "", util.ErrFileTooLarge.WithMsg("图片像素过大")
+	}
+	if err != nil {
+		return nil, "", util.ErrUnsupportedMedia.WithMsg("图片文件已损坏或格式不正确")
+	}
+	encoded, encodedType, err := imaging.Encode(img)
+	if err != nil {
+		return nil, "", util.ErrInternal.WithMsg("图片处理失败")
+	}
+	return encoded, encodedType, nil
+}
+
+// sanitizeFilename 只保留文件名部分，并限制长度
+func sanitizeFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	if len(name) > 255 {
+		name = name[:255]
+	}
+	return name
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"time"
+
+	"go-blog-api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxMentionsPerComment = 10
+	// notificationDedupWindow 关注、回应在该时间内对同一对象重复触发（取消后重新关注、反复点赞）只通知一次
+	notificationDedupWindow = 24 * time.Hour
+)
+
+// notificationEvent 触发通知的事件，由 outbox 订阅者根据领域事件构造；只携带 ID，接收者在投递时查询
+type notificationEvent struct {
+	EventID    uint   // outbox 事件 ID，与接收者一起作为通知的唯一键，重复投递时不会重复通知
+	Type       string // comment / follow / reaction
+	ActorID    uint
+	CommentID  uint   // comment、评论的 reaction
+	ArticleID  uint   // 文章的 reaction
+	UserID     uint   // follow 的被关注者
+	Reaction   string // reaction 类型
+	TargetType string // reaction 的对象类型
+}
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9_]{1,100})`)
+
+// dispatch 解析事件的接收者，按偏好过滤后写入通知；返回的错误由 outbox relay 重试
+func (s *NotificationService) dispatch(ctx context.Context, ev notificationEvent) error {
+	notifications, err := s.resolve(ctx, ev)
+	if err != nil || len(notifications) == 0 {
+		return err
+	}
+
+	recipients := make([]uint, len(notifications))
+	for i, n := range notifications {
+		recipients[i] = n.UserID
+	}
+	prefs, err := s.notificationRepo.ListPreferences(ctx, recipients)
+	if err != nil {
+		return err
+	}
+	byUser := make(map[uint]*model.NotificationPreference, len(prefs))
+	for i := range prefs {
+		byUser[prefs[i].UserID] = &prefs[i]
+	}
+
+	since := time.Now().Add(-notificationDedupWindow)
+	enabled := notifications[:0]
+	for _, n := range notifications {
+		if pref, ok := byUser[n.UserID]; ok && !pref.Enabled(n.Type) {
+			continue
+		}
+		if n.Type == model.NotificationFollow || n.Type == model.NotificationReaction {
+			dup, err := s.notificationRepo.ExistsSince(ctx, &n, since)
+			if err != nil {
+				return err
+			}
+			if dup {
+				continue
+			}
+		}
+		enabled = append(enabled, n)
+	}
+	// 重试时已写入的通知被唯一键跳过，只推送本次新写入的
+	created, err := s.notificationRepo.CreateBatch(ctx, enabled)
+	if err != nil {
+		return err
+	}
+	for i := range created {
+		// 推送失败时通知已写入，重试也不会再推送，只记录日志
+		if err := publishEvent(ctx, EventNotificationCreated, []string{userTopic(created[i].UserID)}, created[i]); err != nil {
+			log.Printf("publish notification %d failed: %v", created[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// resolve 计算事件对应的通知；同一事件每个接收者最多一条，不通知触发者本人
+// 评论、文章等已被删除时跳过，其他数据库错误返回给调用方重试
+func (s *NotificationService) resolve(ctx context.Context, ev notificationEvent) ([]model.Notification, error) {
+	var result []model.Notification
+	notified := map[uint]bool{ev.ActorID: true}
+	add := func(userID uint, n model.Notification) {
+		if userID == 0 || notified[userID] {
+			return
+		}
+		notified[userID] = true
+		n.UserID, n.ActorID = userID, ev.ActorID
+		if ev.EventID != 0 {
+			eventID := ev.EventID
+			n.EventID = &eventID
+		}
+		result = append(result, n)
+	}
+
+	switch ev.Type {
+	case model.NotificationFollow:
+		add(ev.UserID, model.Notification{Type: model.NotificationFollow})
+
+	case model.NotificationComment:
+		comment, err := s.commentRepo.GetByID(ctx, ev.CommentID)
+		if err != nil {
+			return nil, skipNotFound(err) // 评论已被删除
+		}
+		base := model.Notification{ArticleID: comment.ArticleID, CommentID: comment.ID}
+
+		// 优先级：回复 > 提及 > 评论文章
+		if comment.ParentID != nil {
+			parent, err := s.commentRepo.GetByID(ctx, *comment.ParentID)
+			if err == nil {
+				n := base
+				n.Type = model.NotificationReply
+				add(parent.UserID, n)
+			} else if err = skipNotFound(err); err != nil {
+				return nil, err
+			}
+		}
+		users, err := s.userRepo.ListByUsernames(ctx, parseMentions(comment.Content))
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			n := base
+			n.Type = model.NotificationMention
+			add(u.ID, n)
+		}
+		article, err := s.articleRepo.GetByID(ctx, comment.ArticleID)
+		if err != nil {
+			return result, skipNotFound(err)
+		}
+		n := base
+		n.Type = model.NotificationComment
+		add(article.UserID, n)
+
+	case model.NotificationReaction:
+		n := model.Notification{Type: model.NotificationReaction, Reaction: ev.Reaction}
+		switch ev.TargetType {
+		case model.ReactionTargetArticle:
+			article, err := s.articleRepo.GetByID(ctx, ev.ArticleID)
+			if err != nil {
+				return nil, skipNotFound(err)
+			}
+			n.ArticleID = article.ID
+			add(article.UserID, n)
+		case model.ReactionTargetComment:
+			comment, err := s.commentRepo.GetByID(ctx, ev.CommentID)
+			if err != nil {
+				return nil, skipNotFound(err)
+			}
+			n.ArticleID, n.CommentID = comment.ArticleID, comment.ID
+			add(comment.UserID, n)
+		}
+	}
+	return result, nil
+}
+
+// skipNotFound 记录不存在（已被删除）时不再通知，返回 nil；其他错误原样返回
+func skipNotFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
+
+// parseMentions 提取评论中 @ 的用户名（去重，最多 maxMentionsPerComment 个）
+func parseMentions(content string) []string {
+	var names []string
+	for _, m := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		names = append(names, m[1])
+	}
+	names = uniqueStrings(names)
+	if len(names) > maxMentionsPerComment {
+		names = names[:maxMentionsPerComment]
+	}
+	return names
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+// NotificationService 负责站内通知的查询、已读状态和通知偏好；通知的生成见 notification_dispatcher.go
+type NotificationService struct {
+	notificationRepo repository.INotificationRepository
+	userRepo         repository.IUserRepository
+	articleRepo      repository.IArticleRepository
+	commentRepo      repository.ICommentRepository
+}
+
+func NewNotificationService(notificationRepo repository.INotificationRepository, userRepo repository.IUserRepository, articleRepo repository.IArticleRepository, commentRepo repository.ICommentRepository) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		articleRepo:      articleRepo,
+		commentRepo:      commentRepo,
+	}
+}
+
+// List 获取通知列表，附带未读总数
+func (s *NotificationService) List(ctx context.Context, userID uint, req *dto.ListNotificationsRequest) (*dto.NotificationListResponse, error) {
+	req.SetDefaults()
+
+	notifications, total, err := s.notificationRepo.ListByUserID(ctx, userID, req.UnreadOnly, req.Offset(), req.PageSize)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	unread, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+
+	return &dto.NotificationListResponse{
+		PageResponse: *dto.NewPageResponse(notifications, total, req.Page, req.PageSize),
+		UnreadCount:  unread,
+	}, nil
+}
+
+// UnreadCount 获取未读通知数
+func (s *NotificationService) UnreadCount(ctx context.Context, userID uint) (*dto.UnreadCountResponse, error) {
+	unread, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	return &dto.UnreadCountResponse{UnreadCount: unread}, nil
+}
+
+// MarkRead 标记单条通知为已读；已读、不存在或不属于该用户时同样返回成功，不暴露他人通知是否存在
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id uint) error {
+	if _, err := s.notificationRepo.MarkRead(ctx, id, userID, time.Now()); err != nil {
+		return util.ErrDatabase
+	}
+	return nil
+}
+
+// MarkAllRead 标记所有通知为已读
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID uint) error {
+	if _, err := s.notificationRepo.MarkAllRead(ctx, userID, time.Now()); err != nil {
+		return util.ErrDatabase
+	}
+	return nil
+}
+
+// GetPreference 获取通知偏好，未设置过时返回默认值
+func (s *NotificationService) GetPreference(ctx context.Context, userID uint) (*model.NotificationPreference, error) {
+	pref, err := s.notificationRepo.GetPreference(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.DefaultNotificationPreference(userID), nil
+	}
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	return pref, nil
+}
+
+// UpdatePreference 更新通知偏好
+func (s *NotificationService) UpdatePreference(ctx context.Context, userID uint, req *dto.UpdateNotificationPreferenceRequest) (*model.NotificationPreference, error) {
+	pref, err := s.GetPreference(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for field, value := range map[*bool]*bool{
+		&pref.Comment:  req.Comment,
+		&pref.Reply:    req.Reply,
+		&pref.Mention:  req.Mention,
+		&pref.Follow:   req.Follow,
+		&pref.Reaction: req.Reaction,
+	} {
+		if value != nil {
+			*field = *value
+		}
+	}
+
+	if err := s.notificationRepo.SavePreference(ctx, pref); err != nil {
+		return nil, util.ErrDatabase
+	}
+	return pref, nil
+}
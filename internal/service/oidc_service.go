@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/oidc"
+	"go-blog-api/pkg/util"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// OIDCStateTTL 授权流程（跳转到提供方再回调）的最长时间
+const OIDCStateTTL = 10 * time.Minute
+
+var usernameSanitizer = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// oidcState 授权过程中需要在回调时校验的数据，加密后存放在 Cookie 中
+type oidcState struct {
+	Provider  string `json:"p"`
+	State     string `json:"s"`
+	Nonce     string `json:"n"`
+	Verifier  string `json:"v"`
+	ExpiresAt int64  `json:"e"`
+}
+
+// OIDCService 负责第三方登录：发起授权、处理回调、绑定/自动创建用户
+type OIDCService struct {
+	providers      map[string]*oidc.Provider
+	userRepo       repository.IUserRepository
+	identityRepo   repository.IUserIdentityRepository
+	sessionService *SessionService
+	auditService   *AuditService
+	tx             repository.ITransactor
+}
+
+func NewOIDCService(providers map[string]*oidc.Provider, userRepo repository.IUserRepository, identityRepo repository.IUserIdentityRepository, sessionService *SessionService, auditService *AuditService, tx repository.ITransactor) *OIDCService {
+	return &OIDCService{providers: providers, userRepo: userRepo, identityRepo: identityRepo, sessionService: sessionService, auditService: auditService, tx: tx}
+}
+
+// Providers 列出已启用的登录方式
+func (s *OIDCService) Providers() *dto.OIDCProvidersResponse {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return &dto.OIDCProvidersResponse{Providers: names}
+}
+
+// ListIdentities 列出用户已绑定的第三方身份
+func (s *OIDCService) ListIdentities(ctx context.Context, userID uint) ([]model.UserIdentity, error) {
+	identities, err := s.identityRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	return identities, nil
+}
+
+// Begin 发起授权，返回提供方授权地址和需要写入 Cookie 的加密状态
+func (s *OIDCService) Begin(ctx context.Context, providerName string) (authURL, sealedState string, err error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", "", util.ErrProviderNotFound
+	}
+
+	st := oidcState{Provider: providerName, ExpiresAt: time.Now().Add(OIDCStateTTL).Unix()}
+	for _, field := range []*string{&st.State, &st.Nonce, &st.Verifier} {
+		if *field, err = oidc.RandomString(32); err != nil {
+			return "", "", util.ErrInternal
+		}
+	}
+
+	authURL, err = provider.AuthCodeURL(ctx, st.State, st.Nonce, st.Verifier)
+	if err != nil {
+		return "", "", util.ErrOAuthFailed.WithMsg("第三方登录服务不可用")
+	}
+
+	raw, _ := json.Marshal(st)
+	if sealedState, err = util.EncryptString(string(raw)); err != nil {
+		return "", "", util.ErrInternal
+	}
+	return authURL, sealedState, nil
+}
+
+// Complete 处理回调：校验 state、换取令牌、校验 id_token，最后签发本站 JWT
+func (s *OIDCService) Complete(ctx context.Context, providerName, sealedState, state, code string, client dto.ClientInfo) (*dto.LoginResponse, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, util.ErrProviderNotFound
+	}
+
+	// 1. 校验 state，防止 CSRF 和授权码注入
+	st, err := openOIDCState(sealedState)
+	if err != nil || st.Provider != providerName || st.State != state || time.Now().Unix() > st.ExpiresAt {
+		return nil, util.ErrOAuthState
+	}
+	if code == "" {
+		return nil, util.ErrOAuthFailed
+	}
+
+	// 2. 授权码 + PKCE 换取令牌，并校验身份
+	token, err := provider.Exchange(ctx, code, st.Verifier)
+	if err != nil {
+		return nil, util.ErrOAuthFailed
+	}
+	identity, err := provider.Identify(ctx, token, st.Nonce)
+	if err != nil {
+		return nil, util.ErrOAuthFailed
+	}
+
+	// 3. 查找或创建本地用户
+	user, err := s.resolveUser(ctx, providerName, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.sessionService.IssueLogin(ctx, user, client)
+	s.auditService.RecordLoginResult(ctx, user, "oidc:"+providerName, resp, err)
+	return resp, err
+}
+
+// resolveUser 已绑定则直接登录；邮箱已验证且存在同邮箱账号时自动绑定；否则创建新用户
+func (s *OIDCService) resolveUser(ctx context.Context, providerName string, identity *oidc.Identity) (*model.User, error) {
+	linked, err := s.identityRepo.GetByProviderSubject(ctx, providerName, identity.Subject)
+	if err == nil {
+		user, err := s.userRepo.GetByID(ctx, linked.UserID)
+		if err != nil {
+			return nil, util.ErrUserNotFound
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, util.ErrDatabase
+	}
+
+	if identity.Email == "" {
+		return nil, util.ErrOAuthFailed.WithMsg("第三方账号未提供邮箱")
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, identity.Email)
+	switch {
+	case err == nil:
+		// 未经提供方验证的邮箱不能用来接管已有账号
+		if !identity.EmailVerified {
+			return nil, util.ErrEmailExists.WithMsg("邮箱已被注册，请使用密码登录")
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		user = nil
+	default:
+		return nil, util.ErrDatabase
+	}
+
+	// 自动创建的用户和身份绑定在同一事务中写入，避免留下无法登录的账号
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if user == nil {
+			created, err := s.provisionUser(ctx, identity)
+			if err != nil {
+				return err
+			}
+			user = created
+		}
+		if err := s.identityRepo.Create(ctx, &model.UserIdentity{
+			UserID:   user.ID,
+			Provider: providerName,
+			Subject:  identity.Subject,
+			Email:    identity.Email,
+		}); err != nil {
+			return util.ErrDatabase
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// provisionUser 首次登录自动创建用户，密码为随机值（只能通过第三方登录）
+func (s *OIDCService) provisionUser(ctx context.Context, identity *oidc.Identity) (*model.User, error) {
+	username, err := s.availableUsername(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	randomPwd := make([]byte, 32)
+	if _, err := rand.Read(randomPwd); err != nil {
+		return nil, util.ErrInternal
+	}
+	hashedPwd, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(randomPwd)), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, util.ErrInternal
+	}
+
+	avatar := identity.Picture
+	if avatar == "" {
+		avatar = defaultAvatar()
+	}
+	user := &model.User{
+		Username: username,
+		Password: string(hashedPwd),
+		Email:    identity.Email,
+		Avatar:   avatar,
+	}
+	if err := s.userRepo.CreateUser(ctx, user); err != nil {
+		return nil, userError(err)
+	}
+	return user, nil
+}
+
+// availableUsername 从外部资料推导用户名，冲突时追加随机后缀
+func (s *OIDCService) availableUsername(ctx context.Context, identity *oidc.Identity) (string, error) {
+	base := identity.Username
+	if base == "" {
+		base = strings.SplitN(identity.Email, "@", 2)[0]
+	}
+	base = usernameSanitizer.ReplaceAllString(base, "")
+	if base == "" {
+		base = "user"
+	}
+	if len(base) > 80 {
+		base = base[:80]
+	}
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		if _, err := s.userRepo.GetByUsername(ctx, candidate); errors.Is(err, gorm.ErrRecordNotFound) {
+			return candidate, nil
+		}
+		suffix := make([]byte, 3)
+		if _, err := rand.Read(suffix); err != nil {
+			return "", util.ErrInternal
+		}
+		candidate = base + "_" + hex.EncodeToString(suffix)
+	}
+	return "", util.ErrUsernameExists
+}
+
+func openOIDCState(sealed string) (*oidcState, error) {
+	raw, err := util.DecryptString(sealed)
+	if err != nil {
+		return nil, err
+	}
+	var st oidcState
+	if err := json.Unmarshal([]byte(raw), &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/config"
+	"go-blog-api/pkg/oidc"
+	"go-blog-api/pkg/oidc/oidctest"
+	"go-blog-api/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+var initTestConfig sync.Once
+
+// setupTestConfig 测试用配置：HS256 签名密钥、字段加密密钥和两步验证挑战有效期
+func setupTestConfig() {
+	initTestConfig.Do(func() {
+		config.AppConfig = &config.Config{
+			JWT:       config.JWTConfig{Secret: "test-secret", ExpireHours: 1, Issuer: "go-blog-api"},
+			Security:  config.SecurityConfig{EncryptionKey: "test-encryption-key"},
+			TwoFactor: config.TwoFactorConfig{ChallengeExpireMinutes: 5},
+		}
+		util.InitJWTKeys()
+	})
+}
+
+// fakeUserRepo 内存中的用户表，只实现第三方登录用到的方法
+type fakeUserRepo struct {
+	repository.IUserRepository
+	users []*model.User
+}
+
+func (r *fakeUserRepo) CreateUser(ctx context.Context, user *model.User) error {
+	user.ID = uint(len(r.users) + 1)
+	r.users = append(r.users, user)
+	return nil
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id uint) (*model.User, error) {
+	for _, u := range r.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepo) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepo) GetByUsername(ctx context.Context, username string) (*model.User, error) {
+	for _, u := range r.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type fakeIdentityRepo struct {
+	identities []model.UserIdentity
+}
+
+func (r *fakeIdentityRepo) Create(ctx context.Context, identity *model.UserIdentity) error {
+	r.identities = append(r.identities, *identity)
+	return nil
+}
+
+func (r *fakeIdentityRepo) GetByProviderSubject(ctx context.Context, provider, subject string) (*model.UserIdentity, error) {
+	for i := range r.identities {
+		if r.identities[i].Provider == provider && r.identities[i].Subject == subject {
+			return &r.identities[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeIdentityRepo) ListByUserID(ctx context.Context, userID uint) ([]model.UserIdentity, error) {
+	var out []model.UserIdentity
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			out = append(out, identity)
+		}
+	}
+	return out, nil
+}
+
+type fakeSessionRepo struct {
+	repository.ISessionRepository
+}
+
+func (r *fakeSessionRepo) Create(ctx context.Context, session *model.Session) error {
+	return nil
+}
+
+type fakeAuditRepo struct {
+	repository.IAuditRepository
+	logs []model.AuditLog
+}
+
+func (r *fakeAuditRepo) Create(ctx context.Context, log *model.AuditLog) error {
+	r.logs = append(r.logs, *log)
+	return nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type oidcFixture struct {
+	srv        *oidctest.Server
+	svc        *OIDCService
+	users      *fakeUserRepo
+	identities *fakeIdentityRepo
+}
+
+func newOIDCFixture(t *testing.T) *oidcFixture {
+	t.Helper()
+	setupTestConfig()
+
+	srv := oidctest.NewServer()
+	t.Cleanup(srv.Close)
+	f := &oidcFixture{srv: srv, users: &fakeUserRepo{}, identities: &fakeIdentityRepo{}}
+	providers := map[string]*oidc.Provider{"mock": oidc.NewProvider(srv.ProviderConfig("mock"), nil)}
+	f.svc = NewOIDCService(providers, f.users, f.identities, NewSessionService(&fakeSessionRepo{}),
+		NewAuditService(&fakeAuditRepo{}), fakeTransactor{})
+	return f
+}
+
+// login 完整走一遍 Begin → 提供方授权 → Complete
+func (f *oidcFixture) login(t *testing.T) (*dto.LoginResponse, error) {
+	t.Helper()
+	ctx := context.Background()
+
+	authURL, sealed, err := f.svc.Begin(ctx, "mock")
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	code, state, err := f.srv.Login(authURL)
+	if err != nil {
+		t.Fatalf("login at provider: %v", err)
+	}
+	return f.svc.Complete(ctx, "mock", sealed, state, code, dto.ClientInfo{})
+}
+
+func TestOIDCCompleteRejectsStateMismatch(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+
+	authURL, sealed, err := f.svc.Begin(ctx, "mock")
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	code, _, err := f.srv.Login(authURL)
+	if err != nil {
+		t.Fatalf("login at provider: %v", err)
+	}
+
+	_, err = f.svc.Complete(ctx, "mock", sealed, "forged-state", code, dto.ClientInfo{})
+	if !errors.Is(err, util.ErrOAuthState) {
+		t.Fatalf("err = %v, want ErrOAuthState", err)
+	}
+	if len(f.users.users) != 0 {
+		t.Fatal("user created despite state mismatch")
+	}
+}
+
+func TestOIDCAutoProvisionsOnFirstLogin(t *testing.T) {
+	f := newOIDCFixture(t)
+
+	resp, err := f.login(t)
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if resp.Token == "" || resp.User == nil {
+		t.Fatalf("unexpected login response %+v", resp)
+	}
+	if len(f.users.users) != 1 || f.users.users[0].Username != "alice" || f.users.users[0].Email != "alice@example.com" {
+		t.Fatalf("unexpected users %+v", f.users.users)
+	}
+	if len(f.identities.identities) != 1 {
+		t.Fatalf("identities = %+v, want one", f.identities.identities)
+	}
+	identity := f.identities.identities[0]
+	if identity.Provider != "mock" || identity.Subject != "user-1" || identity.UserID != resp.User.ID {
+		t.Fatalf("unexpected identity %+v", identity)
+	}
+
+	// 再次登录使用已绑定的身份，不会重复创建
+	again, err := f.login(t)
+	if err != nil {
+		t.Fatalf("second Complete: %v", err)
+	}
+	if again.User.ID != resp.User.ID || len(f.users.users) != 1 || len(f.identities.identities) != 1 {
+		t.Fatal("second login created another user or identity")
+	}
+}
+
+func TestOIDCLogsInLinkedIdentity(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+
+	// 已绑定的身份以绑定的用户登录，即使提供方返回的邮箱属于另一个用户
+	linked := &model.User{Username: "bob", Email: "bob@example.com"}
+	other := &model.User{Username: "alice", Email: "alice@example.com"}
+	_ = f.users.CreateUser(ctx, linked)
+	_ = f.users.CreateUser(ctx, other)
+	_ = f.identities.Create(ctx, &model.UserIdentity{UserID: linked.ID, Provider: "mock", Subject: "user-1"})
+
+	resp, err := f.login(t)
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if resp.User.ID != linked.ID {
+		t.Fatalf("logged in as user %d, want %d", resp.User.ID, linked.ID)
+	}
+	if len(f.users.users) != 2 || len(f.identities.identities) != 1 {
+		t.Fatal("login with linked identity created a user or identity")
+	}
+}
+
+func TestOIDCLinksVerifiedEmailToExistingUser(t *testing.T) {
+	f := newOIDCFixture(t)
+	existing := &model.User{Username: "alice_local", Email: "alice@example.com"}
+	_ = f.users.CreateUser(context.Background(), existing)
+
+	resp, err := f.login(t)
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if resp.User.ID != existing.ID || len(f.users.users) != 1 {
+		t.Fatalf("logged in as %+v, want existing user %d", resp.User, existing.ID)
+	}
+	if len(f.identities.identities) != 1 || f.identities.identities[0].UserID != existing.ID {
+		t.Fatalf("identity not linked to existing user: %+v", f.identities.identities)
+	}
+}
+
+func TestOIDCRefusesUnverifiedEmailTakeover(t *testing.T) {
+	f := newOIDCFixture(t)
+	_ = f.users.CreateUser(context.Background(), &model.User{Username: "alice_local", Email: "alice@example.com"})
+	f.srv.User.EmailVerified = false
+
+	if _, err := f.login(t); err == nil {
+		t.Fatal("unverified email linked to existing account")
+	}
+	if len(f.identities.identities) != 0 {
+		t.Fatal("identity created for unverified email")
+	}
+}
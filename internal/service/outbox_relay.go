@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"go-blog-api/internal/event"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+)
+
+const (
+	outboxPollInterval    = 2 * time.Second
+	outboxPollBatch       = 100
+	outboxHandleTimeout   = 30 * time.Second
+	outboxMaxAttempts     = 10
+	outboxRetryBase       = 5 * time.Second // 第 n 次失败后等待 base * 2^(n-1)
+	outboxRetryMax        = 30 * time.Minute
+	outboxRetention       = 7 * 24 * time.Hour // 已完成事件的保留时间
+	outboxCleanupInterval = time.Hour
+)
+
+var (
+	// domainEvents 领域事件总线，订阅者在 StartOutboxRelay 中注册
+	domainEvents = event.NewBus()
+	// outboxWake 事务提交后唤醒 relay，不必等到下一个扫描周期
+	outboxWake = make(chan struct{}, 1)
+)
+
+// OutboxRelay 把 outbox 中的事件投递给订阅者：每个订阅者处理成功后记录下来，
+// 部分失败时只重试失败的订阅者，超过最大重试次数后标记为 failed
+type OutboxRelay struct {
+	outboxRepo repository.IOutboxRepository
+	bus        *event.Bus
+}
+
+func NewOutboxRelay(outboxRepo repository.IOutboxRepository, bus *event.Bus) *OutboxRelay {
+	return &OutboxRelay{outboxRepo: outboxRepo, bus: bus}
+}
+
+// StartOutboxRelay 注册订阅者并启动后台 relay；需在 StartWebhookWorkers 之后调用
+func StartOutboxRelay() {
+	registerEventSubscribers(domainEvents)
+	relay := NewOutboxRelay(repository.NewOutboxRepository(), domainEvents)
+
+	go func() {
+		ticker := time.NewTicker(outboxPollInterval)
+		defer ticker.Stop()
+		lastCleanup := time.Now()
+		for {
+			relay.poll()
+			if time.Since(lastCleanup) > outboxCleanupInterval {
+				lastCleanup = time.Now()
+				if _, err := relay.outboxRepo.DeleteProcessedBefore(context.Background(), lastCleanup.Add(-outboxRetention)); err != nil {
+					log.Printf("outbox cleanup failed: %v", err)
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-outboxWake:
+			}
+		}
+	}()
+}
+
+// wakeOutboxRelay 通知 relay 立即扫描一次，在写入 outbox 的事务提交后调用
+func wakeOutboxRelay() {
+	select {
+	case outboxWake <- struct{}{}:
+	default:
+	}
+}
+
+// poll 按写入顺序处理到期的事件
+func (r *OutboxRelay) poll() {
+	now := time.Now()
+	due, err := r.outboxRepo.ListDue(context.Background(), now, outboxPollBatch)
+	if err != nil {
+		log.Printf("list outbox events failed: %v", err)
+		return
+	}
+	lease := now.Add(outboxHandleTimeout + time.Minute)
+	for i := range due {
+		ok, err := r.outboxRepo.Claim(context.Background(), due[i].ID, now, lease)
+		if err != nil || !ok {
+			continue
+		}
+		r.process(&due[i])
+	}
+}
+
+// process 投递一个事件给尚未处理成功的订阅者并保存结果
+func (r *OutboxRelay) process(row *model.OutboxEvent) {
+	row.Attempts++
+	subscribers := r.bus.Subscribers(row.Name)
+
+	var failures []string
+	if len(subscribers) > 0 {
+		e, err := r.bus.Decode(row.Name, []byte(row.Payload))
+		if err != nil {
+			r.fail(row, "decode: "+err.Error(), true)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), outboxHandleTimeout)
+		meta := event.Meta{ID: row.ID, OccurredAt: row.CreatedAt, Attempt: row.Attempts}
+		done := row.CompletedSet()
+		for _, sub := range subscribers {
+			if done[sub.Name] {
+				continue
+			}
+			if err := sub.Handle(ctx, meta, e); err != nil {
+				failures = append(failures, sub.Name+": "+err.Error())
+				continue
+			}
+			done[sub.Name] = true
+			if row.Completed != "" {
+				row.Completed += ","
+			}
+			row.Completed += sub.Name
+		}
+		cancel()
+	}
+
+	if len(failures) > 0 {
+		r.fail(row, strings.Join(failures, "; "), row.Attempts >= outboxMaxAttempts)
+		return
+	}
+	now := time.Now()
+	row.Status = model.OutboxStatusDone
+	row.ProcessedAt = &now
+	row.LastError = ""
+	if err := r.outboxRepo.Save(context.Background(), row); err != nil {
+		log.Printf("save outbox event %d failed: %v", row.ID, err)
+	}
+}
+
+// fail 记录失败原因，未达上限时按指数退避安排重试
+func (r *OutboxRelay) fail(row *model.OutboxEvent, reason string, final bool) {
+	row.LastError = truncate(reason, 500)
+	if final {
+		row.Status = model.OutboxStatusFailed
+		log.Printf("outbox event %d (%s) failed permanently: %s", row.ID, row.Name, reason)
+	} else {
+		d := outboxRetryBase
+		for i := 1; i < row.Attempts && d < outboxRetryMax; i++ {
+			d *= 2
+		}
+		row.NextAttemptAt = time.Now().Add(min(d, outboxRetryMax))
+	}
+	if err := r.outboxRepo.Save(context.Background(), row); err != nil {
+		log.Printf("save outbox event %d failed: %v", row.ID, err)
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"go-blog-api/internal/event"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/config"
+	"go-blog-api/pkg/util"
+)
+
+// ReactionService 负责文章和评论的点赞与表情回应
+type ReactionService struct {
+	reactionRepo repository.IReactionRepository
+	articleRepo  repository.IArticleRepository
+	commentRepo  repository.ICommentRepository
+	outboxRepo   repository.IOutboxRepository
+	tx           repository.ITransactor
+}
+
+func NewReactionService(reactionRepo repository.IReactionRepository, articleRepo repository.IArticleRepository, commentRepo repository.ICommentRepository, outboxRepo repository.IOutboxRepository, tx repository.ITransactor) *ReactionService {
+	return &ReactionService{reactionRepo: reactionRepo, articleRepo: articleRepo, commentRepo: commentRepo, outboxRepo: outboxRepo, tx: tx}
+}
+
+// Types 当前允许的回应类型，like 始终排在第一位
+func (s *ReactionService) Types() []string {
+	return uniqueStrings(append([]string{model.ReactionLike}, config.AppConfig.Reaction.Types...))
+}
+
+// Add 添加回应，重复添加不报错（幂等）
+func (s *ReactionService) Add(ctx context.Context, userID uint, targetType string, targetID uint, reactionType string) (*model.ReactionSummary, error) {
+	if err := s.checkTarget(ctx, targetType, targetID, reactionType); err != nil {
+		return nil, err
+	}
+
+	// 回应和事件在同一事务中写入，通知由 outbox relay 投递；重复回应不产生事件
+	reaction := &model.Reaction{UserID: userID, TargetType: targetType, TargetID: targetID, Type: reactionType}
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		added, err := s.reactionRepo.Add(ctx, reaction)
+		if err != nil || !added {
+			return err
+		}
+		return s.outboxRepo.Add(ctx, event.ReactionAdded{UserID: userID, TargetType: targetType, TargetID: targetID, Reaction: reactionType})
+	})
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	wakeOutboxRelay()
+	return s.summaries(ctx, targetType, []uint{targetID}, userID)[targetID], nil
+}
+
+// Remove 取消回应，未回应过时不报错（幂等）
+func (s *ReactionService) Remove(ctx context.Context, userID uint, targetType string, targetID uint, reactionType string) (*model.ReactionSummary, error) {
+	if err := s.checkTarget(ctx, targetType, targetID, reactionType); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.reactionRepo.Remove(ctx, userID, targetType, targetID, reactionType); err != nil {
+		return nil, util.ErrDatabase
+	}
+	return s.summaries(ctx, targetType, []uint{targetID}, userID)[targetID], nil
+}
+
+// FillArticles 为文章列表填充回应汇总，viewerID 为 0 时不计算"我的回应"
+func (s *ReactionService) FillArticles(ctx context.Context, articles []model.Article, viewerID uint) {
+	ids := make([]uint, len(articles))
+	for i := range articles {
+		ids[i] = articles[i].ID
+	}
+	summaries := s.summaries(ctx, model.ReactionTargetArticle, ids, viewerID)
+	for i := range articles {
+		articles[i].Reactions = summaries[articles[i].ID]
+	}
+}
+
+// FillArticle 为单篇文章填充回应汇总
+func (s *ReactionService) FillArticle(ctx context.Context, article *model.Article, viewerID uint) {
+	article.Reactions = s.summaries(ctx, model.ReactionTargetArticle, []uint{article.ID}, viewerID)[article.ID]
+}
+
+// FillComments 为评论列表填充回应汇总
+func (s *ReactionService) FillComments(ctx context.Context, comments []model.Comment, viewerID uint) {
+	ids := make([]uint, len(comments))
+	for i := range comments {
+		ids[i] = comments[i].ID
+	}
+	summaries := s.summaries(ctx, model.ReactionTargetComment, ids, viewerID)
+	for i := range comments {
+		comments[i].Reactions = summaries[comments[i].ID]
+	}
+}
+
+func (s *ReactionService) checkTarget(ctx context.Context, targetType string, targetID uint, reactionType string) error {
+	if !slices.Contains(s.Types(), reactionType) {
+		return util.ErrInvalidParam.WithMsg("不支持的回应类型")
+	}
+
+	switch targetType {
+	case model.ReactionTargetArticle:
+		if _, err := s.articleRepo.GetByID(ctx, targetID); err != nil {
+			return util.ErrArticleNotFound
+		}
+	case model.ReactionTargetComment:
+		if _, err := s.commentRepo.GetByID(ctx, targetID); err != nil {
+			return util.ErrCommentNotFound
+		}
+	default:
+		return util.ErrInvalidParam
+	}
+	return nil
+}
+
+// summaries 批量查询计数和当前用户的回应（两次查询，与列表长度无关）
+// 回应只是附加信息，查询失败时返回空汇总，不影响主体数据
+func (s *ReactionService) summaries(ctx context.Context, targetType string, ids []uint, viewerID uint) map[uint]*model.ReactionSummary {
+	result := make(map[uint]*model.ReactionSummary, len(ids))
+	for _, id := range ids {
+		result[id] = &model.ReactionSummary{Counts: map[string]int64{}, Mine: []string{}}
+	}
+
+	if counts, err := s.reactionRepo.CountsByTargets(ctx, targetType, ids); err == nil {
+		for _, c := range counts {
+			if sum, ok := result[c.TargetID]; ok {
+				sum.Counts[c.Type] = c.Count
+			}
+		}
+	}
+
+	if viewerID != 0 {
+		if mine, err := s.reactionRepo.ListByUserAndTargets(ctx, viewerID, targetType, ids); err == nil {
+			for _, r := range mine {
+				if sum, ok := result[r.TargetID]; ok {
+					sum.Mine = append(sum.Mine, r.Type)
+					sum.LikedByMe = sum.LikedByMe || r.Type == model.ReactionLike
+				}
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/util"
+)
+
+const (
+	maxReadingLists     = 100 // 每个用户最多创建的阅读清单数
+	maxReadingListItems = 500 // 每个清单最多包含的文章数
+)
+
+// ReadingListService 负责阅读清单的管理和分享
+type ReadingListService struct {
+	listRepo    repository.IReadingListRepository
+	articleRepo repository.IArticleRepository
+}
+
+func NewReadingListService(listRepo repository.IReadingListRepository, articleRepo repository.IArticleRepository) *ReadingListService {
+	return &ReadingListService{listRepo: listRepo, articleRepo: articleRepo}
+}
+
+// Create 创建阅读清单
+func (s *ReadingListService) Create(ctx context.Context, userID uint, req *dto.CreateReadingListRequest) (*model.ReadingList, error) {
+	count, err := s.listRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	if count >= maxReadingLists {
+		return nil, util.ErrBadRequest.WithMsg("阅读清单数量已达上限")
+	}
+
+	token, err := randomHex(16)
+	if err != nil {
+		return nil, util.ErrInternal
+	}
+	list := &model.ReadingList{
+		UserID:      userID,
+		Name:        req.Name,
+		Description: req.Description,
+		Public:      req.Public,
+		ShareToken:  token,
+	}
+	if err := s.listRepo.Create(ctx, list); err != nil {
+		return nil, util.ErrDatabase
+	}
+	return list, nil
+}
+
+// List 获取用户的阅读清单（不含条目）
+func (s *ReadingListService) List(ctx context.Context, userID uint) ([]model.ReadingList, error) {
+	lists, err := s.listRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	return lists, nil
+}
+
+// Get 获取自己的阅读清单及其文章
+func (s *ReadingListService) Get(ctx context.Context, userID, id uint) (*model.ReadingList, error) {
+	list, err := s.owned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.withItems(ctx, list)
+}
+
+// GetShared 通过分享 token 获取公开的阅读清单
+func (s *ReadingListService) GetShared(ctx context.Context, token string) (*model.ReadingList, error) {
+	list, err := s.listRepo.GetByShareToken(ctx, token)
+	if err != nil || !list.Public {
+		return nil, util.ErrListNotFound
+	}
+	return s.withItems(ctx, list)
+}
+
+// Update 更新阅读清单名称、描述或公开状态
+func (s *ReadingListService) Update(ctx context.Context, userID, id uint, req *dto.UpdateReadingListRequest) (*model.ReadingList, error) {
+	list, err := s.owned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		list.Name = req.Name
+	}
+	if req.Description != nil {
+		list.Description = *req.Description
+	}
+	if req.Public != nil {
+		list.Public = *req.Public
+	}
+	if err := s.listRepo.Update(ctx, list); err != nil {
+		return nil, util.ErrDatabase
+	}
+	return list, nil
+}
+
+// Delete 删除阅读清单
+func (s *ReadingListService) Delete(ctx context.Context, userID, id uint) error {
+	if _, err := s.owned(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := s.listRepo.Delete(ctx, id); err != nil {
+		return util.ErrDatabase
+	}
+	return nil
+}
+
+// AddItem 把文章追加到清单末尾，已在清单中时不报错
+func (s *ReadingListService) AddItem(ctx context.Context, userID, id, articleID uint) error {
+	if _, err := s.owned(ctx, userID, id); err != nil {
+		return err
+	}
+	if _, err := s.articleRepo.GetByID(ctx, articleID); err != nil {
+		return util.ErrArticleNotFound
+	}
+
+	count, err := s.listRepo.CountItems(ctx, id)
+	if err != nil {
+		return util.ErrDatabase
+	}
+	if count >= maxReadingListItems {
+		return util.ErrBadRequest.WithMsg("阅读清单中的文章数量已达上限")
+	}
+
+	if _, err := s.listRepo.AddItem(ctx, id, articleID); err != nil {
+		return util.ErrDatabase
+	}
+	return nil
+}
+
+// RemoveItem 从清单中移除文章，不在清单中时不报错
+func (s *ReadingListService) RemoveItem(ctx context.Context, userID, id, articleID uint) error {
+	if _, err := s.owned(ctx, userID, id); err != nil {
+		return err
+	}
+	if _, err := s.listRepo.RemoveItem(ctx, id, articleID); err != nil {
+		return util.ErrDatabase
+	}
+	return nil
+}
+
+// Reorder 调整清单中文章的顺序
+func (s *ReadingListService) Reorder(ctx context.Context, userID, id uint, req *dto.ReorderReadingListRequest) (*model.ReadingList, error) {
+	list, err := s.owned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.listRepo.Reorder(ctx, id, req.ArticleIDs); err != nil {
+		return nil, util.ErrDatabase
+	}
+	return s.withItems(ctx, list)
+}
+
+// owned 获取属于当前用户的清单；不存在和不属于当前用户都返回 404，避免泄露清单是否存在
+func (s *ReadingListService) owned(ctx context.Context, userID, id uint) (*model.ReadingList, error) {
+	list, err := s.listRepo.GetByID(ctx, id)
+	if err != nil || list.UserID != userID {
+		return nil, util.ErrListNotFound
+	}
+	return list, nil
+}
+
+func (s *ReadingListService) withItems(ctx context.Context, list *model.ReadingList) (*model.ReadingList, error) {
+	items, err := s.listRepo.ListItems(ctx, list.ID)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	list.Items = items
+	list.ItemCount = int64(len(items))
+	return list, nil
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/cache"
+	"go-blog-api/pkg/config"
+	"go-blog-api/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+const (
+	sessionCacheSize     = 100000
+	sessionCacheTTL      = 30 * time.Second // 其他实例上吊销的会话最多在这段时间后生效
+	sessionTouchInterval = time.Minute      // last_seen_at 的最小更新间隔
+	maxUserAgentLength   = 255
+	// defaultImpersonationTTL 代登录 token 的默认有效期，可在 security.impersonation_minutes 中配置
+	defaultImpersonationTTL = time.Hour
+)
+
+// cachedSession 会话校验结果的缓存，valid=false 的条目用于挡住已吊销 token 的重复查询
+type cachedSession struct {
+	id        uint
+	userID    uint
+	valid     bool
+	lastSeen  time.Time
+	expiresAt time.Time
+}
+
+// sessionCache 进程内共享，JWT 中间件每个请求都会查询
+var sessionCache = cache.NewLRU[string, cachedSession](sessionCacheSize, sessionCacheTTL)
+
+// SessionService 负责登录会话：签发带 sid 的 token、会话列表与吊销
+type SessionService struct {
+	sessionRepo repository.ISessionRepository
+}
+
+func NewSessionService(sessionRepo repository.ISessionRepository) *SessionService {
+	return &SessionService{sessionRepo: sessionRepo}
+}
+
+// IssueLogin 为已通过身份校验的用户签发登录结果：被暂停或封禁的账号拒绝登录，
+// 被要求重置密码的用户只拿到重置密码 token，开启两步验证的用户只拿到挑战 token
+func (s *SessionService) IssueLogin(ctx context.Context, user *model.User, client dto.ClientInfo) (*dto.LoginResponse, error) {
+	if err := accountError(user, time.Now()); err != nil {
+		return nil, err
+	}
+	if user.PasswordResetRequired {
+		token, err := util.GeneratePasswordResetToken(user.ID, user.Username)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.LoginResponse{
+			PasswordResetRequired: true,
+			ChallengeToken:        token,
+		}, nil
+	}
+	if user.TOTPEnabled {
+		challenge, err := util.GenerateChallengeToken(user.ID, user.Username)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.LoginResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+		}, nil
+	}
+	return s.StartSession(ctx, user, client)
+}
+
+// StartSession 创建会话并签发正式 token（两步验证等后续步骤也经过这里，再次校验账号状态）
+func (s *SessionService) StartSession(ctx context.Context, user *model.User, client dto.ClientInfo) (*dto.LoginResponse, error) {
+	now := time.Now()
+	if err := accountError(user, now); err != nil {
+		return nil, err
+	}
+	session, err := s.createSession(ctx, user.ID, 0, client, now.Add(time.Duration(config.AppConfig.JWT.ExpireHours)*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := util.GenerateToken(user.ID, user.Username, session.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.LoginResponse{
+		Token: token,
+		User:  user,
+	}, nil
+}
+
+// StartImpersonation 为管理员创建代登录 target 的会话，会话和 token 都带有管理员 ID
+func (s *SessionService) StartImpersonation(ctx context.Context, target *model.User, impersonatorID uint, client dto.ClientInfo) (*dto.ImpersonationResponse, error) {
+	ttl := time.Duration(config.AppConfig.Security.ImpersonationMinutes) * time.Minute
+	if ttl <= 0 {
+		ttl = defaultImpersonationTTL
+	}
+	expiresAt := time.Now().Add(ttl)
+	session, err := s.createSession(ctx, target.ID, impersonatorID, client, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := util.GenerateImpersonationToken(target.ID, target.Username, session.SessionID, impersonatorID, ttl)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ImpersonationResponse{
+		Token:          token,
+		ExpiresAt:      expiresAt,
+		User:           target,
+		ImpersonatorID: impersonatorID,
+	}, nil
+}
+
+func (s *SessionService) createSession(ctx context.Context, userID, impersonatorID uint, client dto.ClientInfo, expiresAt time.Time) (*model.Session, error) {
+	sessionID, err := randomHex(16)
+	if err != nil {
+		return nil, util.ErrInternal
+	}
+
+	userAgent := client.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	session := &model.Session{
+		SessionID:      sessionID,
+		UserID:         userID,
+		UserAgent:      userAgent,
+		IP:             client.IP,
+		LastSeenAt:     time.Now(),
+		ExpiresAt:      expiresAt,
+		ImpersonatorID: impersonatorID,
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, util.ErrDatabase
+	}
+	return session, nil
+}
+
+// Validate 校验 token 中的会话是否仍然有效，结果会短暂缓存以避免每个请求都查库
+func (s *SessionService) Validate(ctx context.Context, sessionID string, userID uint) bool {
+	if sessionID == "" {
+		return false
+	}
+
+	now := time.Now()
+	cached, ok := sessionCache.Get(sessionID)
+	if !ok {
+		session, err := s.sessionRepo.GetBySessionID(ctx, sessionID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			cached = cachedSession{valid: false}
+		case err != nil:
+			// 查询失败（包括请求取消、超时）不缓存，避免有效会话被误判为已吊销
+			return false
+		default:
+			cached = cachedSession{
+				id:        session.ID,
+				userID:    session.UserID,
+				valid:     true,
+				lastSeen:  session.LastSeenAt,
+				expiresAt: session.ExpiresAt,
+			}
+		}
+		sessionCache.Set(sessionID, cached)
+	}
+
+	if !cached.valid || cached.userID != userID || now.After(cached.expiresAt) {
+		return false
+	}
+
+	// 限频更新最近活跃时间，写入失败不影响本次请求
+	if now.Sub(cached.lastSeen) > sessionTouchInterval {
+		if err := s.sessionRepo.TouchLastSeen(ctx, cached.id, now); err == nil {
+			cached.lastSeen = now
+			sessionCache.Set(sessionID, cached)
+		}
+	}
+	return true
+}
+
+// List 获取用户的活跃会话，并标记当前会话
+func (s *SessionService) List(ctx context.Context, userID uint, currentSessionID string) ([]dto.SessionResponse, error) {
+	sessions, err := s.sessionRepo.ListActiveByUserID(ctx, userID, time.Now())
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+
+	result := make([]dto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, dto.SessionResponse{
+			Session: session,
+			Current: session.SessionID == currentSessionID,
+		})
+	}
+	return result, nil
+}
+
+// Revoke 吊销指定会话（设备下线）
+func (s *SessionService) Revoke(ctx context.Context, userID, id uint) error {
+	session, err := s.sessionRepo.Revoke(ctx, id, userID)
+	if err != nil {
+		return util.ErrNotFound.WithMsg("会话不存在")
+	}
+	invalidateSession(session.SessionID)
+	return nil
+}
+
+// RevokeBySessionID 吊销当前会话（注销登录）
+func (s *SessionService) RevokeBySessionID(ctx context.Context, userID uint, sessionID string) error {
+	session, err := s.sessionRepo.GetBySessionID(ctx, sessionID)
+	if err != nil || session.UserID != userID {
+		return util.ErrNotFound.WithMsg("会话不存在")
+	}
+	return s.Revoke(ctx, userID, session.ID)
+}
+
+// RevokeOthers 吊销除当前会话外的全部会话
+func (s *SessionService) RevokeOthers(ctx context.Context, userID uint, currentSessionID string) error {
+	sessionIDs, err := s.sessionRepo.RevokeOthers(ctx, userID, currentSessionID)
+	if err != nil {
+		return util.ErrDatabase
+	}
+	for _, sessionID := range sessionIDs {
+		invalidateSession(sessionID)
+	}
+	return nil
+}
+
+// RevokeAll 吊销用户的全部会话（封禁、强制重置密码）
+func (s *SessionService) RevokeAll(ctx context.Context, userID uint) error {
+	sessionIDs, err := s.sessionRepo.RevokeAll(ctx, userID)
+	if err != nil {
+		return util.ErrDatabase
+	}
+	for _, sessionID := range sessionIDs {
+		invalidateSession(sessionID)
+	}
+	return nil
+}
+
+// invalidateSession 让本实例的缓存立即感知吊销
+func invalidateSession(sessionID string) {
+	sessionCache.Set(sessionID, cachedSession{valid: false})
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/cache"
+	"go-blog-api/pkg/config"
+	"go-blog-api/pkg/sitemap"
+	"go-blog-api/pkg/util"
+)
+
+const (
+	sitemapCacheKey = "sitemap"
+	sitemapCacheTTL = time.Hour // 兜底过期时间，文章变更时会主动失效
+	sitemapBatch    = 1000
+)
+
+// sitemapCache 缓存生成好的 sitemap 分片，文章增删改时失效
+var (
+	sitemapCache = cache.NewLRU[string, *sitemapSnapshot](1, sitemapCacheTTL)
+	sitemapBuild sync.Mutex
+)
+
+type sitemapSnapshot struct {
+	pages    [][]byte    // 每个分片最多 sitemap.MaxURLs 条
+	lastMods []time.Time // 每个分片的最近修改时间
+}
+
+// SitemapPage 一个 sitemap 文档
+type SitemapPage struct {
+	Body         []byte
+	LastModified time.Time
+}
+
+// SitemapService 生成站点地图：首页、文章页、作者页和标签页
+type SitemapService struct {
+	articleRepo repository.IArticleRepository
+	tagRepo     repository.ITagRepository
+}
+
+func NewSitemapService(articleRepo repository.IArticleRepository, tagRepo repository.ITagRepository) *SitemapService {
+	return &SitemapService{articleRepo: articleRepo, tagRepo: tagRepo}
+}
+
+// InvalidateSitemap 使本实例缓存的站点地图失效，下次访问时重新生成；其他实例通过 broadcast 通知
+func InvalidateSitemap() {
+	sitemapCache.Delete(sitemapCacheKey)
+}
+
+// Root 返回 /sitemap.xml：URL 不超过上限时直接返回 urlset，否则返回指向各分片的索引
+// pageURL 根据分片序号（从 1 开始）生成分片地址
+func (s *SitemapService) Root(ctx context.Context, pageURL func(n int) string) (*SitemapPage, error) {
+	snap, err := s.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(snap.pages) == 1 {
+		return &SitemapPage{Body: snap.pages[0], LastModified: snap.lastMods[0]}, nil
+	}
+
+	index := make([]sitemap.Index, len(snap.pages))
+	var lastMod time.Time
+	for i := range snap.pages {
+		index[i] = sitemap.Index{Loc: pageURL(i + 1), LastMod: snap.lastMods[i]}
+		if snap.lastMods[i].After(lastMod) {
+			lastMod = snap.lastMods[i]
+		}
+	}
+	body, err := sitemap.EncodeIndex(index)
+	if err != nil {
+		return nil, util.ErrInternal
+	}
+	return &SitemapPage{Body: body, LastModified: lastMod}, nil
+}
+
+// Page 返回第 n 个分片（从 1 开始）
+func (s *SitemapService) Page(ctx context.Context, n int) (*SitemapPage, error) {
+	snap, err := s.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n < 1 || n > len(snap.pages) {
+		return nil, util.ErrNotFound
+	}
+	return &SitemapPage{Body: snap.pages[n-1], LastModified: snap.lastMods[n-1]}, nil
+}
+
+func (s *SitemapService) snapshot(ctx context.Context) (*sitemapSnapshot, error) {
+	if snap, ok := sitemapCache.Get(sitemapCacheKey); ok {
+		return snap, nil
+	}
+
+	// 同一时间只生成一次，避免缓存失效瞬间的并发请求重复扫表
+	sitemapBuild.Lock()
+	defer sitemapBuild.Unlock()
+	if snap, ok := sitemapCache.Get(sitemapCacheKey); ok {
+		return snap, nil
+	}
+
+	snap, err := s.build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sitemapCache.Set(sitemapCacheKey, snap)
+	return snap, nil
+}
+
+func (s *SitemapService) build(ctx context.Context) (*sitemapSnapshot, error) {
+	site := strings.TrimRight(config.AppConfig.Site.URL, "/")
+
+	var urls []sitemap.URL
+	var latest time.Time
+	authors := make(map[uint]time.Time)
+
+	var afterID uint
+	for {
+		articles, err := s.articleRepo.ListForSitemap(ctx, afterID, sitemapBatch)
+		if err != nil {
+			return nil, util.ErrDatabase
+		}
+		for i := range articles {
+			a := &articles[i]
+			urls = append(urls, sitemap.URL{Loc: articleURL(site, a), LastMod: a.UpdatedAt})
+			if a.UpdatedAt.After(authors[a.UserID]) {
+				authors[a.UserID] = a.UpdatedAt
+			}
+			if a.UpdatedAt.After(latest) {
+				latest = a.UpdatedAt
+			}
+		}
+		if len(articles) < sitemapBatch {
+			break
+		}
+		afterID = articles[len(articles)-1].ID
+	}
+
+	// 作者页按 ID 排序，保证输出稳定
+	authorIDs := make([]uint, 0, len(authors))
+	for id := range authors {
+		authorIDs = append(authorIDs, id)
+	}
+	sort.Slice(authorIDs, func(i, j int) bool { return authorIDs[i] < authorIDs[j] })
+	for _, id := range authorIDs {
+		urls = append(urls, sitemap.URL{Loc: authorURL(site, id), LastMod: authors[id]})
+	}
+
+	// 标签页按标签 ID 排序，最近修改时间为标签下文章的最新更新时间
+	tags, err := s.tagRepo.ListForSitemap(ctx)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	for _, t := range tags {
+		urls = append(urls, sitemap.URL{Loc: tagURL(site, t.Slug), LastMod: t.LastMod})
+	}
+	urls = append([]sitemap.URL{{Loc: site + "/", LastMod: latest}}, urls...)
+
+	snap := &sitemapSnapshot{}
+	for start := 0; start < len(urls); start += sitemap.MaxURLs {
+		end := min(start+sitemap.MaxURLs, len(urls))
+		body, err := sitemap.Encode(urls[start:end])
+		if err != nil {
+			return nil, util.ErrInternal
+		}
+		var lastMod time.Time
+		for _, u := range urls[start:end] {
+			if u.LastMod.After(lastMod) {
+				lastMod = u.LastMod
+			}
+		}
+		snap.pages = append(snap.pages, body)
+		snap.lastMods = append(snap.lastMods, lastMod)
+	}
+	return snap, nil
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/config"
+	"go-blog-api/pkg/util"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	defaultTOTPMaxFailures = 5
+	defaultTOTPLock        = 15 * time.Minute
+)
+
+// TwoFactorService 负责 TOTP 两步验证：绑定、确认、恢复码与两步登录
+type TwoFactorService struct {
+	userRepo       repository.IUserRepository
+	codeRepo       repository.IRecoveryCodeRepository
+	sessionService *SessionService
+	auditService   *AuditService
+	tx             repository.ITransactor
+}
+
+func NewTwoFactorService(userRepo repository.IUserRepository, codeRepo repository.IRecoveryCodeRepository, sessionService *SessionService, auditService *AuditService, tx repository.ITransactor) *TwoFactorService {
+	return &TwoFactorService{userRepo: userRepo, codeRepo: codeRepo, sessionService: sessionService, auditService: auditService, tx: tx}
+}
+
+// Enroll 生成新的 TOTP 密钥（加密保存，待确认后才生效）
+func (s *TwoFactorService) Enroll(ctx context.Context, userID uint) (*dto.TwoFactorEnrollResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, util.ErrUserNotFound
+	}
+	if user.TOTPEnabled {
+		return nil, util.ErrTwoFactorEnabled
+	}
+
+	secret, err := util.GenerateTOTPSecret()
+	if err != nil {
+		return nil, util.ErrInternal
+	}
+	encrypted, err := util.EncryptString(secret)
+	if err != nil {
+		return nil, util.ErrInternal
+	}
+	saved, err := s.userRepo.SetTOTPSecret(ctx, userID, encrypted)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	if !saved {
+		return nil, util.ErrTwoFactorEnabled
+	}
+
+	return &dto.TwoFactorEnrollResponse{
+		Secret:     secret,
+		OtpauthURI: util.TOTPURI(config.AppConfig.TwoFactor.Issuer, user.Username, secret),
+	}, nil
+}
+
+// Confirm 使用第一个验证码确认绑定，开启两步验证并返回恢复码
+func (s *TwoFactorService) Confirm(ctx context.Context, userID uint, req *dto.TwoFactorConfirmRequest) (*dto.RecoveryCodesResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, util.ErrUserNotFound
+	}
+	if user.TOTPEnabled {
+		return nil, util.ErrTwoFactorEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, util.ErrInvalidParam.WithMsg("请先获取两步验证密钥")
+	}
+
+	secret, err := util.DecryptString(user.TOTPSecret)
+	if err != nil {
+		return nil, util.ErrInternal
+	}
+	counter, ok := util.VerifyTOTP(secret, req.Code, time.Now())
+	if !ok {
+		return nil, util.ErrInvalidOTP
+	}
+
+	// 条件更新：并发的确认请求或重新生成密钥时只有一个能生效，确认用的验证码也不能再用于登录
+	enabled, err := s.userRepo.EnableTOTP(ctx, userID, user.TOTPSecret, counter)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	if !enabled {
+		return nil, util.ErrInvalidOTP
+	}
+
+	codes, err := s.generateRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable 关闭两步验证，需要同时校验密码和验证码（或恢复码）
+func (s *TwoFactorService) Disable(ctx context.Context, userID uint, req *dto.TwoFactorDisableRequest) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return util.ErrUserNotFound
+	}
+	if !user.TOTPEnabled {
+		return util.ErrTwoFactorDisabled
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		return util.ErrInvalidCredentials
+	}
+	if err := s.verifyCode(ctx, user, req.Code); err != nil {
+		return err
+	}
+
+	// 关闭状态和恢复码一起提交，避免留下已关闭两步验证的恢复码
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.DisableTOTP(ctx, userID); err != nil {
+			return err
+		}
+		return s.codeRepo.DeleteByUserID(ctx, userID)
+	})
+	if err != nil {
+		return util.ErrDatabase
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，旧恢复码全部作废
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID uint, req *dto.TwoFactorConfirmRequest) (*dto.RecoveryCodesResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, util.ErrUserNotFound
+	}
+	if !user.TOTPEnabled {
+		return nil, util.ErrTwoFactorDisabled
+	}
+	if err := s.verifyCode(ctx, user, req.Code); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.generateRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Status 查询两步验证状态
+func (s *TwoFactorService) Status(ctx context.Context, userID uint) (*dto.TwoFactorStatusResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, util.ErrUserNotFound
+	}
+	resp := &dto.TwoFactorStatusResponse{Enabled: user.TOTPEnabled}
+	if user.TOTPEnabled {
+		if resp.RemainingRecoveryCodes, err = s.codeRepo.CountUnused(ctx, userID); err != nil {
+			return nil, util.ErrDatabase
+		}
+	}
+	return resp, nil
+}
+
+// VerifyLogin 两步登录第二步：用挑战 token + 验证码换取正式 token
+// 挑战 token 只能成功使用一次，验证码错误次数过多时锁定账号的两步登录并作废未使用的挑战
+func (s *TwoFactorService) VerifyLogin(ctx context.Context, req *dto.TwoFactorLoginRequest, client dto.ClientInfo) (*dto.LoginResponse, error) {
+	claims, err := util.ParseChallengeToken(req.ChallengeToken)
+	if err != nil || claims.IssuedAt == nil {
+		return nil, util.ErrChallengeExpired
+	}
+	issuedAt := claims.IssuedAt.Time
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, util.ErrChallengeExpired
+	}
+	if !user.TOTPEnabled {
+		return nil, util.ErrChallengeExpired
+	}
+	if user.TOTPChallengeAfter != nil && !issuedAt.After(*user.TOTPChallengeAfter) {
+		return nil, util.ErrChallengeExpired
+	}
+	if err := s.verifyCode(ctx, user, req.Code); err != nil {
+		s.auditService.RecordLogin(ctx, user, user.Username, "two_factor", err.Error())
+		return nil, err
+	}
+
+	// 条件更新保证同一挑战 token 的并发请求只有一个能登录
+	consumed, err := s.userRepo.ConsumeTOTPChallenge(ctx, user.ID, issuedAt, time.Now())
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	if !consumed {
+		return nil, util.ErrChallengeExpired
+	}
+
+	resp, err := s.sessionService.StartSession(ctx, user, client)
+	s.auditService.RecordLoginResult(ctx, user, "two_factor", resp, err)
+	return resp, err
+}
+
+// verifyCode 校验验证码并累计错误次数，锁定期间直接拒绝
+func (s *TwoFactorService) verifyCode(ctx context.Context, user *model.User, code string) error {
+	now := time.Now()
+	if user.TOTPLockedUntil != nil && user.TOTPLockedUntil.After(now) {
+		return util.ErrTwoFactorLocked
+	}
+
+	err := s.checkCode(ctx, user, code)
+	if !errors.Is(err, util.ErrInvalidOTP) {
+		return err
+	}
+
+	cfg := config.AppConfig.TwoFactor
+	maxFailures := cfg.MaxFailures
+	if maxFailures <= 0 {
+		maxFailures = defaultTOTPMaxFailures
+	}
+	lock := time.Duration(cfg.LockMinutes) * time.Minute
+	if lock <= 0 {
+		lock = defaultTOTPLock
+	}
+	locked, ferr := s.userRepo.RecordTOTPFailure(ctx, user.ID, maxFailures, now, now.Add(lock))
+	if ferr != nil {
+		return util.ErrDatabase
+	}
+	if locked {
+		return util.ErrTwoFactorLocked
+	}
+	return err
+}
+
+// checkCode 校验 6 位 TOTP 验证码，其他格式按恢复码处理
+func (s *TwoFactorService) checkCode(ctx context.Context, user *model.User, code string) error {
+	code = strings.TrimSpace(code)
+	if len(code) == 6 && isDigits(code) {
+		secret, err := util.DecryptString(user.TOTPSecret)
+		if err != nil {
+			return util.ErrInternal
+		}
+		counter, ok := util.VerifyTOTP(secret, code, time.Now())
+		if !ok {
+			return util.ErrInvalidOTP
+		}
+		// 同一时间步的验证码只能使用一次
+		advanced, err := s.userRepo.AdvanceTOTPCounter(ctx, user.ID, counter)
+		if err != nil {
+			return util.ErrDatabase
+		}
+		if !advanced {
+			return util.ErrInvalidOTP
+		}
+		return nil
+	}
+
+	ok, err := s.codeRepo.Consume(ctx, user.ID, util.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return util.ErrDatabase
+	}
+	if !ok {
+		return util.ErrInvalidOTP
+	}
+	return nil
+}
+
+// generateRecoveryCodes 生成一组恢复码，数据库只保存哈希
+func (s *TwoFactorService) generateRecoveryCodes(ctx context.Context, userID uint) ([]string, error) {
+	count := config.AppConfig.TwoFactor.RecoveryCodeCount
+	if count <= 0 {
+		count = 10
+	}
+
+	codes := make([]string, 0, count)
+	hashes := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, util.ErrInternal
+		}
+		raw := strings.ToLower(base32.StdEncoding.EncodeToString(buf))[:10]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, util.HashToken(raw))
+	}
+
+	if err := s.codeRepo.ReplaceForUser(ctx, userID, hashes); err != nil {
+		return nil, util.ErrDatabase
+	}
+	return codes, nil
+}
+
+// normalizeRecoveryCode 忽略大小写、空格和分隔符
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/util"
+)
+
+func (r *fakeUserRepo) RecordTOTPFailure(ctx context.Context, id uint, maxFailures int, now, lockUntil time.Time) (bool, error) {
+	u, err := r.GetByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	u.TOTPFailures++
+	if u.TOTPFailures < maxFailures {
+		return false, nil
+	}
+	u.TOTPFailures = 0
+	u.TOTPLockedUntil = &lockUntil
+	u.TOTPChallengeAfter = &now
+	return true, nil
+}
+
+func (r *fakeUserRepo) ConsumeTOTPChallenge(ctx context.Context, id uint, issuedAt, now time.Time) (bool, error) {
+	u, err := r.GetByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if u.TOTPChallengeAfter != nil && !u.TOTPChallengeAfter.Before(issuedAt) {
+		return false, nil
+	}
+	if u.TOTPLockedUntil != nil && u.TOTPLockedUntil.After(now) {
+		return false, nil
+	}
+	u.TOTPFailures = 0
+	u.TOTPChallengeAfter = &issuedAt
+	return true, nil
+}
+
+// fakeRecoveryCodeRepo 未使用的恢复码哈希
+type fakeRecoveryCodeRepo struct {
+	repository.IRecoveryCodeRepository
+	unused map[string]bool
+}
+
+func (r *fakeRecoveryCodeRepo) Consume(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	if !r.unused[codeHash] {
+		return false, nil
+	}
+	delete(r.unused, codeHash)
+	return true, nil
+}
+
+func newTwoFactorFixture(t *testing.T, codes ...string) (*TwoFactorService, *model.User) {
+	t.Helper()
+	setupTestConfig()
+
+	users := &fakeUserRepo{}
+	user := &model.User{Username: "alice", TOTPEnabled: true}
+	_ = users.CreateUser(context.Background(), user)
+	recovery := &fakeRecoveryCodeRepo{unused: make(map[string]bool)}
+	for _, code := range codes {
+		recovery.unused[util.HashToken(normalizeRecoveryCode(code))] = true
+	}
+	svc := NewTwoFactorService(users, recovery, NewSessionService(&fakeSessionRepo{}),
+		NewAuditService(&fakeAuditRepo{}), fakeTransactor{})
+	return svc, user
+}
+
+func challengeFor(t *testing.T, user *model.User) string {
+	t.Helper()
+	token, err := util.GenerateChallengeToken(user.ID, user.Username)
+	if err != nil {
+		t.Fatalf("GenerateChallengeToken: %v", err)
+	}
+	return token
+}
+
+func TestTwoFactorLoginLocksAfterRepeatedFailures(t *testing.T) {
+	svc, user := newTwoFactorFixture(t, "valid-code1")
+	ctx := context.Background()
+	challenge := challengeFor(t, user)
+
+	for i := 1; i < defaultTOTPMaxFailures; i++ {
+		_, err := svc.VerifyLogin(ctx, &dto.TwoFactorLoginRequest{ChallengeToken: challenge, Code: "wrong-code"}, dto.ClientInfo{})
+		if !errors.Is(err, util.ErrInvalidOTP) {
+			t.Fatalf("attempt %d: err = %v, want ErrInvalidOTP", i, err)
+		}
+	}
+	_, err := svc.VerifyLogin(ctx, &dto.TwoFactorLoginRequest{ChallengeToken: challenge, Code: "wrong-code"}, dto.ClientInfo{})
+	if !errors.Is(err, util.ErrTwoFactorLocked) {
+		t.Fatalf("err = %v, want ErrTwoFactorLocked", err)
+	}
+
+	// 锁定后挑战 token 作废，即使验证码正确也不能登录
+	if _, err := svc.VerifyLogin(ctx, &dto.TwoFactorLoginRequest{ChallengeToken: challenge, Code: "valid-code1"}, dto.ClientInfo{}); !errors.Is(err, util.ErrChallengeExpired) {
+		t.Fatalf("err = %v, want ErrChallengeExpired", err)
+	}
+	// 锁定期间重新登录拿到的新挑战同样被拒绝
+	user.TOTPChallengeAfter = nil
+	if _, err := svc.VerifyLogin(ctx, &dto.TwoFactorLoginRequest{ChallengeToken: challenge, Code: "valid-code1"}, dto.ClientInfo{}); !errors.Is(err, util.ErrTwoFactorLocked) {
+		t.Fatalf("err = %v, want ErrTwoFactorLocked", err)
+	}
+}
+
+func TestTwoFactorChallengeIsSingleUse(t *testing.T) {
+	svc, user := newTwoFactorFixture(t, "valid-code1", "valid-code2")
+	ctx := context.Background()
+	challenge := challengeFor(t, user)
+
+	resp, err := svc.VerifyLogin(ctx, &dto.TwoFactorLoginRequest{ChallengeToken: challenge, Code: "valid-code1"}, dto.ClientInfo{})
+	if err != nil {
+		t.Fatalf("VerifyLogin: %v", err)
+	}
+	if resp.Token == "" {
+		t.Fatal("no token issued")
+	}
+
+	_, err = svc.VerifyLogin(ctx, &dto.TwoFactorLoginRequest{ChallengeToken: challenge, Code: "valid-code2"}, dto.ClientInfo{})
+	if !errors.Is(err, util.ErrChallengeExpired) {
+		t.Fatalf("reused challenge: err = %v, want ErrChallengeExpired", err)
+	}
+}
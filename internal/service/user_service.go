@@ -1,59 +1,116 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"strconv"
+
 	"go-blog-api/internal/dto"
+	"go-blog-api/internal/event"
 	"go-blog-api/internal/model"
 	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/config"
 	"go-blog-api/pkg/util"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+// defaultAvatar 新用户的默认头像，可在 storage.default_avatar 中配置
+func defaultAvatar() string {
+	if avatar := config.AppConfig.Storage.DefaultAvatar; avatar != "" {
+		return avatar
+	}
+	return "https://example.com/default-avatar.png"
+}
+
 // UserService 负责和“用户相关”的业务逻辑
 type UserService struct {
-	userRepo repository.IUserRepository
+	userRepo       repository.IUserRepository
+	articleRepo    repository.IArticleRepository
+	outboxRepo     repository.IOutboxRepository
+	sessionService *SessionService
+	auditService   *AuditService
+	tx             repository.ITransactor
 }
 
 // NewUserService 构造函数，目前内部自己创建依赖
 // 后面我们会讨论如何通过依赖注入把这个依赖从外部传进来
-func NewUserService(userRepo repository.IUserRepository) *UserService {
-	return &UserService{userRepo: userRepo}
+func NewUserService(userRepo repository.IUserRepository, articleRepo repository.IArticleRepository, outboxRepo repository.IOutboxRepository, sessionService *SessionService, auditService *AuditService, tx repository.ITransactor) *UserService {
+	return &UserService{userRepo: userRepo, articleRepo: articleRepo, outboxRepo: outboxRepo, sessionService: sessionService, auditService: auditService, tx: tx}
 }
 
 // Login 用户登录
-func (s *UserService) Login(req *dto.LoginRequest) (*dto.LoginResponse, error) {
+func (s *UserService) Login(ctx context.Context, req *dto.LoginRequest, client dto.ClientInfo) (*dto.LoginResponse, error) {
 	// 1. 查询用户
-	user, err := s.userRepo.GetByUsername(req.Username)
+	user, err := s.userRepo.GetByUsername(ctx, req.Username)
 	if err != nil {
+		s.auditService.RecordLogin(ctx, nil, req.Username, "password", "用户不存在")
 		return nil, util.ErrInvalidCredentials
 	}
 
 	// 2. 校验密码
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
+		s.auditService.RecordLogin(ctx, user, user.Username, "password", "密码错误")
 		return nil, util.ErrInvalidCredentials
 	}
 
-	// 3. 生成 Token
-	token, err := util.GenerateToken(user.ID, user.Username)
+	// 3. 校验账号状态，创建会话并生成 Token（被暂停或封禁的账号拒绝登录，需重置密码或开启两步验证的用户只拿到对应的 token）
+	resp, err := s.sessionService.IssueLogin(ctx, user, client)
+	s.auditService.RecordLoginResult(ctx, user, "password", resp, err)
+	return resp, err
+}
+
+// ResetPassword 管理员要求重置密码后，用登录返回的重置密码 token 设置新密码并继续登录
+func (s *UserService) ResetPassword(ctx context.Context, req *dto.ResetPasswordRequest, client dto.ClientInfo) (*dto.LoginResponse, error) {
+	claims, err := util.ParsePasswordResetToken(req.ChallengeToken)
+	if err != nil {
+		return nil, util.ErrChallengeExpired
+	}
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil || !user.PasswordResetRequired {
+		return nil, util.ErrChallengeExpired
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.NewPassword)) == nil {
+		return nil, util.ErrInvalidParam.WithMsg("新密码不能与原密码相同")
+	}
+
+	hashedPwd, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
 	}
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.UpdatePassword(ctx, user.ID, string(hashedPwd)); err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, &model.AuditLog{
+			ActorID:    user.ID,
+			Action:     model.AuditPasswordReset,
+			TargetType: model.AuditTargetUser,
+			TargetID:   user.ID,
+		}, nil, nil)
+	})
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	invalidateAccount(user.ID)
+	user.Password = string(hashedPwd)
+	user.PasswordResetRequired = false
 
-	return &dto.LoginResponse{
-		Token: token,
-		User:  *user,
-	}, nil
+	// 开启两步验证的用户仍需完成第二步
+	resp, err := s.sessionService.IssueLogin(ctx, user, client)
+	s.auditService.RecordLoginResult(ctx, user, "password_reset", resp, err)
+	return resp, err
 }
 
 // Register 注册新用户
-func (s *UserService) Register(req dto.RegisterRequest) error {
-	// 1. 检查用户名是否存在
-	if _, err := s.userRepo.GetByUsername(req.Username); err == nil {
+func (s *UserService) Register(ctx context.Context, req dto.RegisterRequest) error {
+	// 1. 检查用户名是否存在（提前给出友好提示，并发注册由唯一索引兜底）
+	if _, err := s.userRepo.GetByUsername(ctx, req.Username); err == nil {
 		return util.ErrUsernameExists
 	}
 	// 2. 检查邮箱是否存在
-	if _, err := s.userRepo.GetByEmail(req.Email); err == nil {
+	if _, err := s.userRepo.GetByEmail(ctx, req.Email); err == nil {
 		return util.ErrEmailExists
 	}
 	// 3. 密码加密
@@ -66,14 +123,24 @@ func (s *UserService) Register(req dto.RegisterRequest) error {
 		Username: req.Username,
 		Password: string(hashedPwd),
 		Email:    req.Email,
-		Avatar:   "https://example.com/default-avatar.png",
+		Avatar:   defaultAvatar(),
+	}
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.CreateUser(ctx, user); err != nil {
+			return err
+		}
+		return s.outboxRepo.Add(ctx, event.UserRegistered{UserInfo: userInfo(user)})
+	})
+	if err != nil {
+		return userError(err)
 	}
-	return s.userRepo.CreateUser(user)
+	wakeOutboxRelay()
+	return nil
 }
 
 // GetByID 获取用户详情
-func (s *UserService) GetByID(id uint) (*model.User, error) {
-	user, err := s.userRepo.GetByID(id)
+func (s *UserService) GetByID(ctx context.Context, id uint) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, util.ErrUserNotFound
 	}
@@ -81,54 +148,100 @@ func (s *UserService) GetByID(id uint) (*model.User, error) {
 }
 
 // Update 更新用户信息
-func (s *UserService) Update(id uint, req *dto.UpdateUserRequest) (*model.User, error) {
+func (s *UserService) Update(ctx context.Context, id uint, req *dto.UpdateUserRequest) (*model.User, error) {
 	// 1. 查询用户
-	user, err := s.userRepo.GetByID(id)
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, util.ErrUserNotFound
 	}
 
+	before := *user
+	fields := make(map[string]any)
+
 	// 2. 检查邮箱是否被其他用户使用
 	if req.Email != "" && req.Email != user.Email {
-		if existingUser, _ := s.userRepo.GetByEmail(req.Email); existingUser != nil && existingUser.ID != id {
+		if existingUser, _ := s.userRepo.GetByEmail(ctx, req.Email); existingUser != nil && existingUser.ID != id {
 			return nil, util.ErrEmailExists
 		}
 		user.Email = req.Email
+		fields["email"] = req.Email
 	}
 
 	// 3. 更新头像
 	if req.Avatar != "" {
 		user.Avatar = req.Avatar
+		fields["avatar"] = req.Avatar
 	}
 
-	// 4. 保存
-	if err := s.userRepo.Update(user); err != nil {
-		return nil, util.ErrDatabase
+	// 4. 只保存修改的列，避免覆盖同时发生的封禁、角色等变更
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.UpdateProfile(ctx, id, fields); err != nil {
+			return err
+		}
+		if err := s.auditService.Record(ctx, &model.AuditLog{
+			ActorID:    id,
+			Action:     model.AuditUserUpdate,
+			TargetType: model.AuditTargetUser,
+			TargetID:   id,
+		}, &before, user); err != nil {
+			return err
+		}
+		return s.outboxRepo.Add(ctx, event.UserUpdated{UserInfo: userInfo(user)})
+	})
+	if err != nil {
+		return nil, userError(err)
 	}
+	wakeOutboxRelay()
 
 	return user, nil
 }
 
-// Delete 删除用户
-func (s *UserService) Delete(id uint) error {
+// Delete 删除用户及其全部文章
+func (s *UserService) Delete(ctx context.Context, id uint) error {
 	// 1. 检查用户是否存在
-	if _, err := s.userRepo.GetByID(id); err != nil {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
 		return util.ErrUserNotFound
 	}
 
-	// 2. 删除
-	if err := s.userRepo.Delete(id); err != nil {
+	// 2. 在同一事务中删除用户和他的全部文章
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		articles, err := s.articleRepo.DeleteByUserID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.auditService.Record(ctx, &model.AuditLog{
+			ActorID:    id,
+			Action:     model.AuditUserDelete,
+			TargetType: model.AuditTargetUser,
+			TargetID:   id,
+			Detail:     "同时删除文章 " + strconv.Itoa(len(articles)) + " 篇",
+		}, user, nil); err != nil {
+			return err
+		}
+		events := []event.Event{event.UserDeleted{UserInfo: userInfo(user)}}
+		for i := range articles {
+			events = append(events, event.ArticleDeleted{ArticleInfo: articleInfo(&articles[i])})
+		}
+		return s.outboxRepo.Add(ctx, events...)
+	})
+	if err != nil {
 		return util.ErrDatabase
 	}
+	invalidateAccount(id)
+	wakeOutboxRelay()
 
 	return nil
 }
 
 // List 获取用户列表
-func (s *UserService) List(req *dto.ListUsersRequest) (*dto.PageResponse[model.User], error) {
+func (s *UserService) List(ctx context.Context, req *dto.ListUsersRequest) (*dto.PageResponse[model.User], error) {
 	req.SetDefaults()
 
-	users, total, err := s.userRepo.List(req.Offset(), req.PageSize, req.Keyword)
+	users, total, err := s.userRepo.List(ctx, req.Offset(), req.PageSize, req.Keyword)
 	if err != nil {
 		return nil, util.ErrDatabase
 	}
@@ -136,8 +249,18 @@ func (s *UserService) List(req *dto.ListUsersRequest) (*dto.PageResponse[model.U
 	return dto.NewPageResponse(users, total, req.Page, req.PageSize), nil
 }
 
-// Logout 注销用户（当前实现为无状态，服务端无需额外操作）
-func (s *UserService) Logout(userID uint) error {
-	// 如果将来需要在服务端维护 token 黑名单或会话表，可在这里实现
-	return nil
+// userError 把 repository 返回的唯一约束冲突转换为业务错误
+func userError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrDuplicateUsername):
+		return util.ErrUsernameExists
+	case errors.Is(err, repository.ErrDuplicateEmail):
+		return util.ErrEmailExists
+	}
+	return util.ErrDatabase
+}
+
+// Logout 注销用户：吊销当前会话，携带该会话 token 的请求将被拒绝
+func (s *UserService) Logout(ctx context.Context, userID uint, sessionID string) error {
+	return s.sessionService.RevokeBySessionID(ctx, userID, sessionID)
 }
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/config"
+	"go-blog-api/pkg/util"
+)
+
+const (
+	webhookPollInterval    = 5 * time.Second
+	webhookPollBatch       = 100
+	webhookRetryBase       = 30 * time.Second // 第 n 次失败后等待 base * 2^(n-1)
+	webhookRetryMax        = 6 * time.Hour
+	webhookResponseMaxSize = 2 << 10 // 投递记录中保存的响应体上限
+	webhookUserAgent       = "go-blog-api-webhook/1.0"
+)
+
+var (
+	// webhookDispatcher 在 StartWebhookWorkers 后可用；未启动时事件不会生成投递任务
+	webhookDispatcher *WebhookService
+	webhookClient     *http.Client
+	// webhookWake 有新的投递任务时唤醒扫描协程，不必等到下一个扫描周期
+	webhookWake = make(chan struct{}, 1)
+)
+
+// StartWebhookWorkers 启动 webhook 投递：扫描协程定期领取到期的任务，交给投递协程发送
+func StartWebhookWorkers() {
+	cfg := config.AppConfig.Webhook
+	webhookClient = newWebhookClient(cfg)
+	svc := NewWebhookService(repository.NewWebhookRepository(), NewAccountService(repository.NewUserRepository()))
+	webhookDispatcher = svc
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	jobs := make(chan model.WebhookDelivery)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for delivery := range jobs {
+				svc.deliver(context.Background(), &delivery)
+			}
+		}()
+	}
+
+	go func() {
+		ticker := time.NewTicker(webhookPollInterval)
+		defer ticker.Stop()
+		for {
+			svc.poll(context.Background(), jobs)
+			select {
+			case <-ticker.C:
+			case <-webhookWake:
+			}
+		}
+	}()
+}
+
+// wakeWebhookWorkers 通知扫描协程立即扫描一次
+func wakeWebhookWorkers() {
+	select {
+	case webhookWake <- struct{}{}:
+	default:
+	}
+}
+
+// enqueue 为订阅了该事件的 webhook 生成投递任务；ownerID 为事件所属用户（文章作者或用户本人）
+// eventID 在同一事件的重复投递中保持不变，接收方可用于去重
+func (s *WebhookService) enqueue(ctx context.Context, eventID, event string, ownerID uint, data any, occurredAt time.Time) error {
+	webhooks, err := s.webhookRepo.ListActiveForOwner(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+	var targets []model.Webhook
+	for _, w := range webhooks {
+		if w.Subscribes(event) {
+			targets = append(targets, w)
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(dto.WebhookPayload{ID: eventID, Event: event, CreatedAt: occurredAt, Data: data})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	deliveries := make([]model.WebhookDelivery, len(targets))
+	for i, w := range targets {
+		deliveries[i] = model.WebhookDelivery{
+			WebhookID:     w.ID,
+			EventID:       eventID,
+			Event:         event,
+			Payload:       string(payload),
+			Status:        model.DeliveryStatusPending,
+			NextAttemptAt: &now,
+		}
+	}
+	if err := s.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+	wakeWebhookWorkers()
+	return nil
+}
+
+// poll 领取到期的投递任务
+func (s *WebhookService) poll(ctx context.Context, jobs chan<- model.WebhookDelivery) {
+	now := time.Now()
+	due, err := s.webhookRepo.ListDueDeliveries(ctx, now, webhookPollBatch)
+	if err != nil {
+		log.Printf("list due webhook deliveries failed: %v", err)
+		return
+	}
+	// 租约覆盖一次请求的最长耗时，进程中途退出时任务会在租约到期后被重新领取
+	lease := now.Add(2*webhookTimeout() + time.Minute)
+	for _, d := range due {
+		ok, err := s.webhookRepo.ClaimDelivery(ctx, d.ID, now, lease)
+		if err != nil || !ok {
+			continue
+		}
+		jobs <- d
+	}
+}
+
+// deliver 发送一次投递并记录结果：成功清零连续失败次数；失败按指数退避安排重试，连续失败过多时停用 webhook
+func (s *WebhookService) deliver(ctx context.Context, d *model.WebhookDelivery) {
+	webhook, err := s.webhookRepo.GetByID(ctx, d.WebhookID)
+	switch {
+	case err != nil:
+		s.finish(ctx, d, "webhook 已删除")
+		return
+	case !webhook.Active:
+		s.finish(ctx, d, "webhook 已停用")
+		return
+	}
+	secret, err := util.DecryptString(webhook.Secret)
+	if err != nil {
+		s.finish(ctx, d, "签名密钥无法解密")
+		return
+	}
+
+	start := time.Now()
+	code, body, sendErr := sendWebhook(webhook.URL, secret, d, start)
+	now := time.Now()
+	d.Attempts++
+	d.ResponseCode = code
+	d.ResponseBody = body
+	d.DurationMs = now.Sub(start).Milliseconds()
+	d.Error = ""
+
+	if sendErr == nil {
+		d.Status = model.DeliveryStatusSuccess
+		d.DeliveredAt = &now
+		d.NextAttemptAt = nil
+		if err := s.webhookRepo.RecordSuccess(ctx, webhook.ID); err != nil {
+			log.Printf("webhook %d record success failed: %v", webhook.ID, err)
+		}
+	} else {
+		d.Error = truncate(sendErr.Error(), 500)
+		if d.Attempts >= webhookMaxAttempts() {
+			d.Status = model.DeliveryStatusFailed
+			d.NextAttemptAt = nil
+		} else {
+			next := now.Add(webhookBackoff(d.Attempts))
+			d.NextAttemptAt = &next
+		}
+		disabled, err := s.webhookRepo.RecordFailure(ctx, webhook.ID, config.AppConfig.Webhook.DisableAfterFailures, now)
+		if err != nil {
+			log.Printf("webhook %d record failure failed: %v", webhook.ID, err)
+		}
+		if disabled {
+			log.Printf("webhook %d disabled after repeated failures", webhook.ID)
+		}
+	}
+
+	if err := s.webhookRepo.SaveDelivery(ctx, d); err != nil {
+		log.Printf("save webhook delivery %d failed: %v", d.ID, err)
+	}
+}
+
+// finish 不再尝试投递，直接标记为失败
+func (s *WebhookService) finish(ctx context.Context, d *model.WebhookDelivery, reason string) {
+	d.Status = model.DeliveryStatusFailed
+	d.NextAttemptAt = nil
+	d.Error = reason
+	if err := s.webhookRepo.SaveDelivery(ctx, d); err != nil {
+		log.Printf("save webhook delivery %d failed: %v", d.ID, err)
+	}
+}
+
+// sendWebhook 发送请求，2xx 视为成功
+// 签名为 HMAC-SHA256(secret, "{timestamp}.{body}")，接收方应校验签名并拒绝时间戳过旧的请求以防重放
+func sendWebhook(target, secret string, d *model.WebhookDelivery, now time.Time) (int, string, error) {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader([]byte(d.Payload)))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set("X-Webhook-Event", d.Event)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(d.ID), 10))
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+SignWebhookPayload(secret, timestamp, []byte(d.Payload)))
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseMaxSize))
+	body := strings.ToValidUTF8(string(raw), "")
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, body, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, body, nil
+}
+
+// SignWebhookPayload 计算 webhook 签名（hex 编码）
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newWebhookClient 创建投递用的 HTTP 客户端：不跟随重定向，默认禁止连接内网地址（防止 SSRF）
+func newWebhookClient(cfg config.WebhookConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !cfg.AllowPrivateNetworks {
+		dialer.Control = webhookDialControl
+	}
+	return &http.Client{
+		Timeout: webhookTimeout(),
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConnsPerHost: 2,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// webhookDialControl 在建立连接时检查解析后的 IP，避免通过 DNS 重新绑定绕过创建时的校验
+func webhookDialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return errors.New("webhook 不允许连接内网地址 " + host)
+	}
+	return nil
+}
+
+func webhookTimeout() time.Duration {
+	if s := config.AppConfig.Webhook.TimeoutSeconds; s > 0 {
+		return time.Duration(s) * time.Second
+	}
+	return 10 * time.Second
+}
+
+func webhookMaxAttempts() int {
+	if n := config.AppConfig.Webhook.MaxAttempts; n > 0 {
+		return n
+	}
+	return 8
+}
+
+// webhookBackoff 第 attempts 次失败后的等待时间
+func webhookBackoff(attempts int) time.Duration {
+	d := webhookRetryBase
+	for i := 1; i < attempts && d < webhookRetryMax; i++ {
+		d *= 2
+	}
+	return min(d, webhookRetryMax)
+}
+
+// truncate 按字节截断字符串，不截断多字节字符
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-blog-api/internal/dto"
+	"go-blog-api/internal/model"
+	"go-blog-api/internal/repository"
+	"go-blog-api/pkg/config"
+	"go-blog-api/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+const (
+	webhookSecretPrefix    = "whsec_"
+	webhookSecretBytes     = 24
+	defaultWebhooksPerUser = 10
+)
+
+// WebhookService 负责 webhook 的管理、投递记录查询和手动重新投递；事件投递见 webhook_dispatcher.go
+type WebhookService struct {
+	webhookRepo    repository.IWebhookRepository
+	accountService *AccountService
+}
+
+func NewWebhookService(webhookRepo repository.IWebhookRepository, accountService *AccountService) *WebhookService {
+	return &WebhookService{webhookRepo: webhookRepo, accountService: accountService}
+}
+
+// Create 创建 webhook，返回的签名密钥只展示这一次
+func (s *WebhookService) Create(ctx context.Context, userID uint, req *dto.CreateWebhookRequest) (*dto.CreateWebhookResponse, error) {
+	if req.Global && !s.accountService.IsAdmin(ctx, userID) {
+		return nil, util.ErrForbidden.WithMsg("只有管理员可以创建全站 webhook")
+	}
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	count, err := s.webhookRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	limit := config.AppConfig.Webhook.MaxPerUser
+	if limit <= 0 {
+		limit = defaultWebhooksPerUser
+	}
+	if count >= int64(limit) {
+		return nil, util.ErrInvalidParam.WithMsg("webhook 数量已达上限")
+	}
+
+	random, err := randomHex(webhookSecretBytes)
+	if err != nil {
+		return nil, util.ErrInternal
+	}
+	secret := webhookSecretPrefix + random
+	encrypted, err := util.EncryptString(secret)
+	if err != nil {
+		return nil, util.ErrInternal
+	}
+
+	webhook := &model.Webhook{
+		UserID:      userID,
+		URL:         req.URL,
+		Description: req.Description,
+		Secret:      encrypted,
+		Events:      strings.Join(uniqueStrings(req.Events), ","),
+		Global:      req.Global,
+		Active:      true,
+	}
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, util.ErrDatabase
+	}
+	return &dto.CreateWebhookResponse{Secret: secret, Webhook: *webhook}, nil
+}
+
+// List 获取当前用户创建的 webhook
+func (s *WebhookService) List(ctx context.Context, userID uint) ([]model.Webhook, error) {
+	webhooks, err := s.webhookRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	return webhooks, nil
+}
+
+// Get 获取 webhook 详情，只能查看自己创建的
+func (s *WebhookService) Get(ctx context.Context, userID, id uint) (*model.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && webhook.UserID != userID) {
+		return nil, util.ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	return webhook, nil
+}
+
+// Update 更新 webhook
+func (s *WebhookService) Update(ctx context.Context, userID, id uint, req *dto.UpdateWebhookRequest) (*model.Webhook, error) {
+	webhook, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != "" {
+		if err := validateWebhookURL(req.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = req.URL
+	}
+	if req.Description != nil {
+		webhook.Description = *req.Description
+	}
+	if len(req.Events) > 0 {
+		webhook.Events = strings.Join(uniqueStrings(req.Events), ",")
+	}
+	if req.Active != nil {
+		if *req.Active && !webhook.Active {
+			webhook.FailureCount = 0
+			webhook.DisabledAt = nil
+		}
+		webhook.Active = *req.Active
+	}
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, util.ErrDatabase
+	}
+	return webhook, nil
+}
+
+// Delete 删除 webhook 及其投递记录
+func (s *WebhookService) Delete(ctx context.Context, userID, id uint) error {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := s.webhookRepo.Delete(ctx, id); err != nil {
+		return util.ErrDatabase
+	}
+	return nil
+}
+
+// ListDeliveries 获取 webhook 的投递记录
+func (s *WebhookService) ListDeliveries(ctx context.Context, userID, id uint, req *dto.ListWebhookDeliveriesRequest) (*dto.PageResponse[model.WebhookDelivery], error) {
+	req.SetDefaults()
+
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	deliveries, total, err := s.webhookRepo.ListDeliveries(ctx, id, req.Offset(), req.PageSize)
+	if err != nil {
+		return nil, util.ErrDatabase
+	}
+	return dto.NewPageResponse(deliveries, total, req.Page, req.PageSize), nil
+}
+
+// Redeliver 手动重新投递：以相同的事件 ID 和内容创建一次新的投递
+func (s *WebhookService) Redeliver(ctx context.Context, userID, id, deliveryID uint) (*model.WebhookDelivery, error) {
+	webhook, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !webhook.Active {
+		return nil, util.ErrInvalidParam.WithMsg("webhook 已停用，请先重新启用")
+	}
+	original, err := s.webhookRepo.GetDelivery(ctx, deliveryID)
+	if err != nil || original.WebhookID != webhook.ID {
+		return nil, util.ErrNotFound.WithMsg("投递记录不存在")
+	}
+
+	now := time.Now()
+	deliveries := []model.WebhookDelivery{{
+		WebhookID:     webhook.ID,
+		EventID:       original.EventID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        model.DeliveryStatusPending,
+		NextAttemptAt: &now,
+	}}
+	if err := s.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		return nil, util.ErrDatabase
+	}
+	wakeWebhookWorkers()
+	return &deliveries[0], nil
+}
+
+// validateWebhookURL 只允许 http/https 地址；内网地址在发起连接时校验（见 webhookDialControl）
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return util.ErrInvalidParam.WithMsg("webhook 地址必须是 http 或 https URL")
+	}
+	if u.User != nil {
+		return util.ErrInvalidParam.WithMsg("webhook 地址不能包含用户名密码")
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRU 并发安全的内存缓存，容量满时淘汰最久未使用的条目，条目到期后视为不存在
+type LRU[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	ll       *list.List
+	items    map[K]*list.Element
+}
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// NewLRU 创建缓存，capacity <= 0 表示不限制容量，ttl <= 0 表示不过期
+func NewLRU[K comparable, V any](capacity int, ttl time.Duration) *LRU[K, V] {
+	return &LRU[K, V]{
+		capacity: capacity,
+		ttl:      ttl,
+		ll:       list.New(),
+		items:    make(map[K]*list.Element),
+	}
+}
+
+// Get 读取缓存，命中时将条目移到队首
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	el, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+	e := el.Value.(*entry[K, V])
+	if !e.expiresAt.IsZero() && time.Now().After(e.expiresAt) {
+		c.removeElement(el)
+		return zero, false
+	}
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+// Set 写入缓存，使用默认 TTL
+func (c *LRU[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL 写入缓存并指定该条目的 TTL
+func (c *LRU[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry[K, V])
+		e.value, e.expiresAt = value, expiresAt
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt})
+	if c.capacity > 0 && c.ll.Len() > c.capacity {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+// Delete 删除缓存条目
+func (c *LRU[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.removeElement(el)
+	}
+}
+
+// Len 当前条目数（包含尚未被清理的过期条目）
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+func (c *LRU[K, V]) removeElement(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*entry[K, V]).key)
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU[string, int](2, 0)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	if _, ok := c.Get("a"); !ok { // a 变为最近使用
+		t.Fatal("a missing")
+	}
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("b should have been evicted")
+	}
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("a = %v, %v", v, ok)
+	}
+	if v, ok := c.Get("c"); !ok || v != 3 {
+		t.Fatalf("c = %v, %v", v, ok)
+	}
+	if c.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", c.Len())
+	}
+}
+
+func TestLRUOverwriteRefreshesEntry(t *testing.T) {
+	c := NewLRU[string, int](2, 0)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("a", 10) // 覆盖同样算作使用
+	c.Set("c", 3)
+
+	if v, ok := c.Get("a"); !ok || v != 10 {
+		t.Fatalf("a = %v, %v, want 10", v, ok)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("b should have been evicted")
+	}
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	c := NewLRU[string, int](0, 20*time.Millisecond)
+	c.Set("default", 1)
+	c.SetWithTTL("forever", 2, 0)
+	c.SetWithTTL("long", 3, time.Hour)
+
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := c.Get("default"); ok {
+		t.Fatal("entry with default TTL should have expired")
+	}
+	if _, ok := c.Get("forever"); !ok {
+		t.Fatal("entry without TTL expired")
+	}
+	if _, ok := c.Get("long"); !ok {
+		t.Fatal("entry with explicit TTL expired early")
+	}
+	if c.Len() != 2 {
+		t.Fatalf("expired entry not removed on read, Len = %d", c.Len())
+	}
+}
+
+func TestLRUDelete(t *testing.T) {
+	c := NewLRU[string, int](0, 0)
+	c.Set("a", 1)
+	c.Delete("a")
+	c.Delete("missing")
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("a should have been deleted")
+	}
+}
+
+func TestMemoryStoreCopiesValues(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(0)
+
+	value := []byte("hello")
+	if err := s.Set(ctx, "k", value, 0); err != nil {
+		t.Fatal(err)
+	}
+	value[0] = 'j'
+
+	got, ok, err := s.Get(ctx, "k")
+	if err != nil || !ok || string(got) != "hello" {
+		t.Fatalf("Get = %q, %v, %v; want hello", got, ok, err)
+	}
+	got[0] = 'y'
+	again, _, _ := s.Get(ctx, "k")
+	if string(again) != "hello" {
+		t.Fatalf("cached value modified through returned slice: %q", again)
+	}
+
+	if err := s.Delete(ctx, "k"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := s.Get(ctx, "k"); ok {
+		t.Fatal("k should have been deleted")
+	}
+}
@@ -0,0 +1,220 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"go-blog-api/pkg/config"
+)
+
+const (
+	defaultRedisPoolSize = 10
+	defaultRedisTimeout  = 500 * time.Millisecond
+	maxRedisBulkSize     = 64 << 20 // 单个值的上限，防止异常响应导致分配过大内存
+)
+
+// RedisStore Redis 兼容服务（Redis、KeyDB、Valkey 等）的缓存后端，使用 RESP 协议，只用到 GET/SET/DEL
+type RedisStore struct {
+	cfg     config.RedisConfig
+	timeout time.Duration
+	idle    chan *redisConn
+}
+
+// 确保 RedisStore 实现了接口
+var _ Store = (*RedisStore)(nil)
+
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+}
+
+// redisError 服务端返回的错误（RESP 中以 - 开头），连接仍然可用
+type redisError string
+
+func (e redisError) Error() string { return "cache: redis: " + string(e) }
+
+func NewRedisStore(cfg config.RedisConfig) *RedisStore {
+	poolSize := cfg.PoolSize
+	if poolSize <= 0 {
+		poolSize = defaultRedisPoolSize
+	}
+	timeout := time.Duration(cfg.TimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = defaultRedisTimeout
+	}
+	return &RedisStore{cfg: cfg, timeout: timeout, idle: make(chan *redisConn, poolSize)}
+}
+
+// Get 读取缓存
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := s.do(ctx, "GET", s.cfg.KeyPrefix+key)
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("cache: redis: unexpected GET reply %T", reply)
+	}
+	return value, true, nil
+}
+
+// Set 写入缓存
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []any{"SET", s.cfg.KeyPrefix + key, value}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	_, err := s.do(ctx, args...)
+	return err
+}
+
+// Delete 删除缓存
+func (s *RedisStore) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	args := make([]any, 0, len(keys)+1)
+	args = append(args, "DEL")
+	for _, key := range keys {
+		args = append(args, s.cfg.KeyPrefix+key)
+	}
+	_, err := s.do(ctx, args...)
+	return err
+}
+
+// do 执行一条命令；网络或协议错误时丢弃连接，服务端错误时连接放回连接池
+func (s *RedisStore) do(ctx context.Context, args ...any) (any, error) {
+	c, err := s.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(s.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		c.conn.Close()
+		return nil, err
+	}
+
+	reply, err := c.roundTrip(args)
+	var serverErr redisError
+	if err != nil && !errors.As(err, &serverErr) {
+		c.conn.Close()
+		return nil, err
+	}
+	s.put(c)
+	return reply, err
+}
+
+// get 从连接池取连接，没有空闲连接时新建（认证并选择数据库）
+func (s *RedisStore) get(ctx context.Context) (*redisConn, error) {
+	select {
+	case c := <-s.idle:
+		return c, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.cfg.Addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn), w: bufio.NewWriter(conn)}
+	if err := conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if s.cfg.Password != "" {
+		if _, err := c.roundTrip([]any{"AUTH", s.cfg.Password}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if s.cfg.DB != 0 {
+		if _, err := c.roundTrip([]any{"SELECT", strconv.Itoa(s.cfg.DB)}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// put 归还连接，连接池已满时关闭
+func (s *RedisStore) put(c *redisConn) {
+	select {
+	case s.idle <- c:
+	default:
+		c.conn.Close()
+	}
+}
+
+// roundTrip 以 RESP 数组发送命令并读取一个响应
+func (c *redisConn) roundTrip(args []any) (any, error) {
+	fmt.Fprintf(c.w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		var b []byte
+		switch v := arg.(type) {
+		case string:
+			b = []byte(v)
+		case []byte:
+			b = v
+		default:
+			return nil, fmt.Errorf("cache: redis: unsupported argument %T", arg)
+		}
+		fmt.Fprintf(c.w, "$%d\r\n", len(b))
+		c.w.Write(b)
+		c.w.WriteString("\r\n")
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+// readReply 读取一个 RESP 响应：简单字符串返回 string，整数返回 int64，批量字符串返回 []byte（nil 表示不存在）
+func (c *redisConn) readReply() (any, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.New("cache: redis: malformed reply")
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil || n > maxRedisBulkSize {
+			return nil, errors.New("cache: redis: malformed bulk length")
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	default:
+		return nil, fmt.Errorf("cache: redis: unsupported reply type %q", kind)
+	}
+}
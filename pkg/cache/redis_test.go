@@ -0,0 +1,290 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-blog-api/pkg/config"
+)
+
+// fakeRedis 只实现 RedisStore 用到的命令（AUTH/SELECT/GET/SET/DEL）的 RESP 服务端
+type fakeRedis struct {
+	ln       net.Listener
+	password string
+
+	mu       sync.Mutex
+	data     map[string][]byte
+	commands [][]string
+	conns    atomic.Int32
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeRedis{ln: ln, password: password, data: make(map[string][]byte)}
+	go f.serve()
+	t.Cleanup(func() { ln.Close() })
+	return f
+}
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.ln.Accept()
+		if err != nil {
+			return
+		}
+		f.conns.Add(1)
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authed := f.password == ""
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		f.commands = append(f.commands, args)
+		f.mu.Unlock()
+
+		var reply string
+		switch cmd := strings.ToUpper(args[0]); {
+		case cmd == "AUTH":
+			authed = len(args) == 2 && args[1] == f.password
+			reply = "+OK\r\n"
+			if !authed {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case !authed:
+			reply = "-NOAUTH Authentication required\r\n"
+		case cmd == "SELECT":
+			reply = "+OK\r\n"
+		case cmd == "GET":
+			f.mu.Lock()
+			v, ok := f.data[args[1]]
+			f.mu.Unlock()
+			reply = "$-1\r\n"
+			if ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+			}
+		case cmd == "SET":
+			f.mu.Lock()
+			f.data[args[1]] = []byte(args[2])
+			f.mu.Unlock()
+			reply = "+OK\r\n"
+		case cmd == "DEL":
+			f.mu.Lock()
+			n := 0
+			for _, key := range args[1:] {
+				if _, ok := f.data[key]; ok {
+					delete(f.data, key)
+					n++
+				}
+			}
+			f.mu.Unlock()
+			reply = ":" + strconv.Itoa(n) + "\r\n"
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// readCommand 读取一条 RESP 数组形式的命令
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func (f *fakeRedis) lastCommand(name string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := len(f.commands) - 1; i >= 0; i-- {
+		if strings.EqualFold(f.commands[i][0], name) {
+			return f.commands[i]
+		}
+	}
+	return nil
+}
+
+func TestRedisStoreRoundTrip(t *testing.T) {
+	srv := newFakeRedis(t, "secret")
+	s := NewRedisStore(config.RedisConfig{Addr: srv.ln.Addr().String(), Password: "secret", DB: 2, KeyPrefix: "blog:"})
+	ctx := context.Background()
+
+	value := []byte("line1\r\nline2\x00binary")
+	if err := s.Set(ctx, "k", value, 1500*time.Millisecond); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if cmd := srv.lastCommand("SET"); len(cmd) != 5 || cmd[1] != "blog:k" || cmd[3] != "PX" || cmd[4] != "1500" {
+		t.Fatalf("SET command = %q", cmd)
+	}
+	if cmd := srv.lastCommand("SELECT"); len(cmd) != 2 || cmd[1] != "2" {
+		t.Fatalf("SELECT command = %q", cmd)
+	}
+
+	got, ok, err := s.Get(ctx, "k")
+	if err != nil || !ok || string(got) != string(value) {
+		t.Fatalf("Get = %q, %v, %v", got, ok, err)
+	}
+	if _, ok, err := s.Get(ctx, "missing"); err != nil || ok {
+		t.Fatalf("Get missing = %v, %v; want not found", ok, err)
+	}
+
+	if err := s.Set(ctx, "forever", []byte("v"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if cmd := srv.lastCommand("SET"); len(cmd) != 3 {
+		t.Fatalf("SET without TTL = %q, want no PX", cmd)
+	}
+
+	if err := s.Delete(ctx, "k", "forever"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if cmd := srv.lastCommand("DEL"); len(cmd) != 3 || cmd[1] != "blog:k" || cmd[2] != "blog:forever" {
+		t.Fatalf("DEL command = %q", cmd)
+	}
+	if _, ok, _ := s.Get(ctx, "k"); ok {
+		t.Fatal("k should have been deleted")
+	}
+
+	// 连续的命令复用同一个连接
+	if n := srv.conns.Load(); n != 1 {
+		t.Fatalf("opened %d connections, want 1", n)
+	}
+}
+
+func TestRedisStoreServerErrorKeepsConnection(t *testing.T) {
+	srv := newFakeRedis(t, "secret")
+	s := NewRedisStore(config.RedisConfig{Addr: srv.ln.Addr().String()}) // 未配置密码
+	ctx := context.Background()
+
+	_, _, err := s.Get(ctx, "k")
+	var serverErr redisError
+	if !errors.As(err, &serverErr) || !strings.HasPrefix(string(serverErr), "NOAUTH") {
+		t.Fatalf("err = %v, want NOAUTH server error", err)
+	}
+	if _, _, err := s.Get(ctx, "k"); !errors.As(err, &serverErr) {
+		t.Fatalf("second err = %v", err)
+	}
+	if n := srv.conns.Load(); n != 1 {
+		t.Fatalf("opened %d connections, want 1 (server errors must not drop the connection)", n)
+	}
+}
+
+func TestRedisStoreRejectsWrongPassword(t *testing.T) {
+	srv := newFakeRedis(t, "secret")
+	s := NewRedisStore(config.RedisConfig{Addr: srv.ln.Addr().String(), Password: "wrong"})
+
+	if err := s.Set(context.Background(), "k", []byte("v"), 0); err == nil {
+		t.Fatal("Set succeeded with wrong password")
+	}
+	if cmd := srv.lastCommand("SET"); cmd != nil {
+		t.Fatalf("SET sent after failed AUTH: %q", cmd)
+	}
+}
+
+func TestRedisStoreDropsBrokenConnection(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	var accepted atomic.Int32
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+			// 返回不合法的响应后关闭连接
+			r := bufio.NewReader(conn)
+			if _, err := readCommand(r); err == nil {
+				io.WriteString(conn, "?garbage\r\n")
+			}
+			conn.Close()
+		}
+	}()
+
+	s := NewRedisStore(config.RedisConfig{Addr: ln.Addr().String(), TimeoutMs: 200})
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, _, err := s.Get(ctx, "k"); err == nil {
+			t.Fatalf("attempt %d: expected protocol error", i)
+		}
+	}
+	if n := accepted.Load(); n != 2 {
+		t.Fatalf("accepted %d connections, want 2 (broken connections must not be reused)", n)
+	}
+}
+
+func TestRedisStoreHonoursTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close() // 只接受连接，从不响应
+		}
+	}()
+
+	s := NewRedisStore(config.RedisConfig{Addr: ln.Addr().String(), TimeoutMs: 50})
+	start := time.Now()
+	_, _, err = s.Get(context.Background(), "k")
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("err = %v, want timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Get took %v, timeout not applied", elapsed)
+	}
+}
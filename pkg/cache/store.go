@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"go-blog-api/pkg/config"
+)
+
+// Store 字节缓存后端的抽象：进程内 LRU 或 Redis 兼容服务
+// 值以序列化后的字节保存，读取方每次拿到独立的副本，可以放心修改
+type Store interface {
+	// Get 读取缓存，不存在或已过期时返回 false
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set 写入缓存，ttl <= 0 表示不过期
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Default 全局共享的缓存后端，由 InitCache 初始化；为 nil 表示未启用缓存
+var Default Store
+
+// InitCache 根据配置初始化全局缓存后端
+func InitCache() error {
+	store, err := New(config.AppConfig.Cache)
+	if err != nil {
+		return err
+	}
+	Default = store
+	return nil
+}
+
+// New 根据配置创建缓存后端：memory（进程内 LRU）、redis（Redis 兼容服务）或 none（不缓存，返回 nil）
+func New(cfg config.CacheConfig) (Store, error) {
+	switch cfg.Driver {
+	case "", "memory":
+		return NewMemoryStore(cfg.MaxEntries), nil
+	case "redis":
+		return NewRedisStore(cfg.Redis), nil
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("cache: unknown driver %q", cfg.Driver)
+	}
+}
+
+// MemoryStore 进程内缓存，多实例部署时各实例独立失效，依赖 TTL 兜底
+type MemoryStore struct {
+	lru *LRU[string, []byte]
+}
+
+// 确保 MemoryStore 实现了接口
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore 创建进程内缓存，maxEntries <= 0 表示不限制容量
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	return &MemoryStore{lru: NewLRU[string, []byte](maxEntries, 0)}
+}
+
+// Get 读取缓存，返回副本
+func (s *MemoryStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	value, ok := s.lru.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+	return bytes.Clone(value), true, nil
+}
+
+// Set 写入缓存，保存副本，调用方之后修改 value 不影响缓存
+func (s *MemoryStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.lru.SetWithTTL(key, bytes.Clone(value), ttl)
+	return nil
+}
+
+// Delete 删除缓存
+func (s *MemoryStore) Delete(_ context.Context, keys ...string) error {
+	for _, key := range keys {
+		s.lru.Delete(key)
+	}
+	return nil
+}
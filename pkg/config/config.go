@@ -7,9 +7,19 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	JWT         JWTConfig
+	Security    SecurityConfig
+	TwoFactor   TwoFactorConfig   `mapstructure:"two_factor"`
+	OIDC        OIDCConfig        `mapstructure:"oidc"`
+	Storage     StorageConfig     `mapstructure:"storage"`
+	Site        SiteConfig        `mapstructure:"site"`
+	Feed        FeedConfig        `mapstructure:"feed"`
+	Reaction    ReactionConfig    `mapstructure:"reaction"`
+	Webhook     WebhookConfig     `mapstructure:"webhook"`
+	Cache       CacheConfig       `mapstructure:"cache"`
+	Idempotency IdempotencyConfig `mapstructure:"idempotency"`
 }
 
 type ServerConfig struct {
@@ -18,13 +28,81 @@ type ServerConfig struct {
 }
 
 type DatabaseConfig struct {
-	DSN string
+	DSN                 string
+	QueryTimeoutSeconds int            `mapstructure:"query_timeout_seconds"` // 请求超时，超时后该请求的数据库查询随之取消，0 表示不限制
+	RouteTimeouts       []RouteTimeout `mapstructure:"route_timeouts"`        // 按路由覆盖请求超时
+}
+
+// RouteTimeout 单个路由的请求超时
+type RouteTimeout struct {
+	Route   string `mapstructure:"route"`   // "METHOD 路由模板"，如 "POST /api/v1/media"
+	Seconds int    `mapstructure:"seconds"` // 0 表示不限制
 }
 
 type JWTConfig struct {
-	Secret      string `mapstructure:"secret"`
-	ExpireHours int    `mapstructure:"expire_hours"`
-	Issuer      string `mapstructure:"issuer"`
+	Algorithm     string `mapstructure:"algorithm"` // HS256 / RS256 / ES256 / EdDSA，为空时使用 HS256
+	Secret        string `mapstructure:"secret"`    // HS256 签名密钥；切换到非对称算法后在过渡期内只用于验证
+	KeyDir        string `mapstructure:"key_dir"`   // 非对称私钥（PEM）存放目录，多实例部署时需共享
+	RotationHours int    `mapstructure:"rotation_hours"`
+	ExpireHours   int    `mapstructure:"expire_hours"`
+	Issuer        string `mapstructure:"issuer"`
+	Audience      string `mapstructure:"audience"`
+}
+
+type SecurityConfig struct {
+	EncryptionKey        string `mapstructure:"encryption_key"`        // 敏感字段加密密钥
+	AdminUserIDs         []uint `mapstructure:"admin_user_ids"`        // 管理员用户 ID（无论角色如何），用于初始化第一个管理员
+	ImpersonationMinutes int    `mapstructure:"impersonation_minutes"` // 管理员代登录 token 的有效期
+}
+
+type TwoFactorConfig struct {
+	Issuer                 string `mapstructure:"issuer"`                   // 验证器 App 中显示的名称
+	ChallengeExpireMinutes int    `mapstructure:"challenge_expire_minutes"` // 二次验证挑战 token 有效期
+	RecoveryCodeCount      int    `mapstructure:"recovery_code_count"`      // 恢复码数量
+	MaxFailures            int    `mapstructure:"max_failures"`             // 验证码连续错误多少次后锁定，默认 5
+	LockMinutes            int    `mapstructure:"lock_minutes"`             // 锁定时长，默认 15 分钟
+}
+
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig `mapstructure:"providers"`
+}
+
+// OIDCProviderConfig 第三方登录提供方配置
+// 配置 issuer 时通过 discovery 文档获取端点；显式配置的端点优先（适用于 GitHub 等非 OIDC 提供方）
+type OIDCProviderConfig struct {
+	Name                  string   `mapstructure:"name"`
+	Issuer                string   `mapstructure:"issuer"`
+	ClientID              string   `mapstructure:"client_id"`
+	ClientSecret          string   `mapstructure:"client_secret"`
+	RedirectURL           string   `mapstructure:"redirect_url"`
+	Scopes                []string `mapstructure:"scopes"`
+	AuthorizationEndpoint string   `mapstructure:"authorization_endpoint"`
+	TokenEndpoint         string   `mapstructure:"token_endpoint"`
+	UserinfoEndpoint      string   `mapstructure:"userinfo_endpoint"`
+	JWKSURI               string   `mapstructure:"jwks_uri"`
+}
+
+// StorageConfig 上传文件存储配置
+type StorageConfig struct {
+	Driver           string   `mapstructure:"driver"`             // local / s3
+	LocalDir         string   `mapstructure:"local_dir"`          // local 驱动的存储目录
+	S3               S3Config `mapstructure:"s3"`                 // s3 驱动配置
+	PublicBaseURL    string   `mapstructure:"public_base_url"`    // 生成下载地址用的外部访问地址
+	SignedURLMinutes int      `mapstructure:"signed_url_minutes"` // 签名下载地址的有效期
+	MaxAvatarMB      int      `mapstructure:"max_avatar_mb"`
+	MaxAttachmentMB  int      `mapstructure:"max_attachment_mb"`
+	DefaultAvatar    string   `mapstructure:"default_avatar"`
+	ImageWorkers     int      `mapstructure:"image_workers"`    // 后台图片处理协程数
+	MaxImagePixels   int      `mapstructure:"max_image_pixels"` // 允许处理的最大像素数，防止解压炸弹
+}
+
+type S3Config struct {
+	Endpoint     string `mapstructure:"endpoint"`
+	Region       string `mapstructure:"region"`
+	Bucket       string `mapstructure:"bucket"`
+	AccessKey    string `mapstructure:"access_key"`
+	SecretKey    string `mapstructure:"secret_key"`
+	UsePathStyle bool   `mapstructure:"use_path_style"` // MinIO 等需要开启
 }
 
 var AppConfig *Config
@@ -42,3 +120,58 @@ func InitConfig() {
 		log.Fatalf("Unable to decode into struct, %v", err)
 	}
 }
+
+// SiteConfig 前端站点信息，用于订阅源、站点地图中的链接和标题
+// 订阅源的自身地址同样为 {url}{请求路径}，前端站点需要把订阅源路径转发到本服务
+type SiteConfig struct {
+	URL         string `mapstructure:"url"` // 前端站点地址，文章页为 {url}/articles/{slug}，作者页为 {url}/users/{id}，标签页为 {url}/tags/{slug}
+	Title       string `mapstructure:"title"`
+	Description string `mapstructure:"description"`
+	Language    string `mapstructure:"language"` // 如 zh-CN
+}
+
+// FeedConfig RSS / Atom / JSON Feed 订阅配置
+type FeedConfig struct {
+	ItemCount   int  `mapstructure:"item_count"`   // 每个订阅源的条目数
+	FullContent bool `mapstructure:"full_content"` // true 输出全文，false 只输出摘要
+	SummaryLen  int  `mapstructure:"summary_len"`  // 摘要长度（字符数）
+}
+
+// ReactionConfig 文章和评论的回应配置
+type ReactionConfig struct {
+	Types []string `mapstructure:"types"` // 点赞（like）之外允许的表情回应，如 heart、laugh
+}
+
+// WebhookConfig 出站 webhook 投递配置
+type WebhookConfig struct {
+	Workers              int  `mapstructure:"workers"`                // 并发投递协程数
+	TimeoutSeconds       int  `mapstructure:"timeout_seconds"`        // 单次请求超时
+	MaxAttempts          int  `mapstructure:"max_attempts"`           // 每次投递的最大尝试次数（含首次）
+	DisableAfterFailures int  `mapstructure:"disable_after_failures"` // 连续失败达到该次数后自动停用
+	MaxPerUser           int  `mapstructure:"max_per_user"`
+	AllowPrivateNetworks bool `mapstructure:"allow_private_networks"` // 是否允许回调内网地址，仅用于本地开发
+}
+
+// CacheConfig 热点数据缓存配置
+type CacheConfig struct {
+	Driver     string      `mapstructure:"driver"`      // memory / redis / none
+	TTLSeconds int         `mapstructure:"ttl_seconds"` // 缓存有效期，数据变更时会主动失效
+	MaxEntries int         `mapstructure:"max_entries"` // memory 驱动的最大条目数
+	Redis      RedisConfig `mapstructure:"redis"`
+}
+
+// IdempotencyConfig Idempotency-Key 请求头的幂等配置
+type IdempotencyConfig struct {
+	TTLSeconds  int `mapstructure:"ttl_seconds"`  // 保存首次响应的时长，期间使用相同 key 的重试直接返回该响应
+	LockSeconds int `mapstructure:"lock_seconds"` // 请求处理中的锁定时长，超过后视为处理中断，允许重试重新执行
+}
+
+// RedisConfig Redis 兼容服务的连接配置
+type RedisConfig struct {
+	Addr      string `mapstructure:"addr"`
+	Password  string `mapstructure:"password"`
+	DB        int    `mapstructure:"db"`
+	PoolSize  int    `mapstructure:"pool_size"`  // 最大空闲连接数
+	TimeoutMs int    `mapstructure:"timeout_ms"` // 单条命令（含建立连接）的超时
+	KeyPrefix string `mapstructure:"key_prefix"` // 多个应用共用一个实例时区分 key
+}
@@ -0,0 +1,181 @@
+// Package events 进程内的事件中心：发布领域事件，按主题推送给订阅者，并保留最近的事件用于断线续传
+package events
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Event 一条推送给客户端的事件
+type Event struct {
+	ID     uint64          `json:"id"`
+	Type   string          `json:"type"`
+	Topics []string        `json:"topics"`
+	Data   json.RawMessage `json:"data"`
+	Time   time.Time       `json:"time"`
+}
+
+// Hub 事件中心，并发安全
+// 事件 ID 单调递增，且以启动时间（微秒）为起点，重启后的新 ID 总大于旧 ID，旧 ID 续传时会被识别为缺失
+type Hub struct {
+	mu           sync.Mutex
+	ring         []Event // 最近的事件，按 ID 递增，容量满时覆盖最旧的
+	head         int     // ring 中最旧事件的位置
+	nextID       uint64
+	evicted      uint64 // 已被覆盖（或启动前）的最大事件 ID
+	clientBuffer int
+	subs         map[*Subscription]struct{}
+}
+
+// NewHub 创建事件中心：replaySize 为保留的最近事件数，clientBuffer 为每个订阅者的待发送队列长度
+func NewHub(replaySize, clientBuffer int) *Hub {
+	start := uint64(time.Now().UnixMicro())
+	return &Hub{
+		ring:         make([]Event, 0, replaySize),
+		nextID:       start,
+		evicted:      start - 1,
+		clientBuffer: clientBuffer,
+		subs:         make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish 发布事件，data 会序列化为 JSON
+// 订阅者的队列已满时不会阻塞发布方，而是断开该订阅者，由客户端携带最后的事件 ID 重连补齐
+func (h *Hub) Publish(eventType string, topics []string, data any) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ev := Event{ID: h.nextID, Type: eventType, Topics: topics, Data: raw, Time: time.Now()}
+	h.nextID++
+	if cap(h.ring) > 0 {
+		if len(h.ring) < cap(h.ring) {
+			h.ring = append(h.ring, ev)
+		} else {
+			h.evicted = h.ring[h.head].ID
+			h.ring[h.head] = ev
+			h.head = (h.head + 1) % len(h.ring)
+		}
+	} else {
+		h.evicted = ev.ID
+	}
+
+	for sub := range h.subs {
+		if !sub.matches(ev) {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			h.drop(sub)
+		}
+	}
+	return ev, nil
+}
+
+// Subscribe 订阅主题；lastID > 0 时同时返回 lastID 之后的缓存事件
+// complete 为 false 表示 lastID 之后有事件已不在缓存中（或 ID 无效），客户端需要重新拉取完整数据
+func (h *Hub) Subscribe(topics []string, lastID uint64) (sub *Subscription, replay []Event, complete bool) {
+	sub = &Subscription{
+		ch:     make(chan Event, h.clientBuffer),
+		done:   make(chan struct{}),
+		topics: make(map[string]bool),
+	}
+	sub.Add(topics...)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	complete = true
+	if lastID > 0 {
+		complete = lastID >= h.evicted && lastID < h.nextID
+		for i := range h.ring {
+			ev := h.ring[(h.head+i)%len(h.ring)]
+			if ev.ID > lastID && sub.matches(ev) {
+				replay = append(replay, ev)
+			}
+		}
+	}
+	h.subs[sub] = struct{}{}
+	return sub, replay, complete
+}
+
+// Unsubscribe 取消订阅，可重复调用
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.drop(sub)
+}
+
+// drop 移除订阅者并通知其连接关闭，调用方需持有锁
+func (h *Hub) drop(sub *Subscription) {
+	if _, ok := h.subs[sub]; !ok {
+		return
+	}
+	delete(h.subs, sub)
+	close(sub.done)
+}
+
+// Subscription 一个订阅者（一条 SSE 或 WebSocket 连接）
+type Subscription struct {
+	ch   chan Event
+	done chan struct{}
+
+	mu     sync.RWMutex
+	topics map[string]bool
+}
+
+// Events 待推送的事件
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+// Done 订阅被取消或因处理过慢被断开时关闭；关闭后 Events 中可能仍有未读的事件
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Add 增加订阅的主题
+func (s *Subscription) Add(topics ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range topics {
+		s.topics[t] = true
+	}
+}
+
+// Remove 取消订阅的主题
+func (s *Subscription) Remove(topics ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range topics {
+		delete(s.topics, t)
+	}
+}
+
+// Topics 当前订阅的主题
+func (s *Subscription) Topics() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	topics := make([]string, 0, len(s.topics))
+	for t := range s.topics {
+		topics = append(topics, t)
+	}
+	return topics
+}
+
+func (s *Subscription) matches(ev Event) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, t := range ev.Topics {
+		if s.topics[t] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,237 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"time"
+)
+
+// Feed 与输出格式无关的订阅源数据，分别编码为 RSS 2.0、Atom 1.0 和 JSON Feed 1.1
+type Feed struct {
+	Title       string
+	Description string
+	Link        string // 站点（或作者主页）地址
+	FeedURL     string // 订阅源自身地址
+	Language    string
+	Updated     time.Time
+	Items       []Item
+}
+
+// Item 订阅源条目；Content 为空时只输出 Summary
+type Item struct {
+	ID        string
+	Title     string
+	Link      string
+	Summary   string
+	Content   string // HTML
+	Author    Person
+	Published time.Time
+	Updated   time.Time
+}
+
+// Person 作者信息
+type Person struct {
+	Name string
+	URL  string
+}
+
+// ========== RSS 2.0 ==========
+
+type rss struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	ContentNS string     `xml:"xmlns:content,attr"`
+	AtomNS    string     `xml:"xmlns:atom,attr"`
+	DCNS      string     `xml:"xmlns:dc,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	Author      string  `xml:"dc:creator,omitempty"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+	Content     *cdata  `xml:"content:encoded,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+// RSS 编码为 RSS 2.0，全文放在 content:encoded 中
+func RSS(f *Feed) ([]byte, error) {
+	doc := rss{
+		Version:   "2.0",
+		ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		AtomNS:    "http://www.w3.org/2005/Atom",
+		DCNS:      "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        f.Link,
+			Description: f.Description,
+			Language:    f.Language,
+			AtomLink:    atomLink{Href: f.FeedURL, Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+	if !f.Updated.IsZero() {
+		doc.Channel.LastBuildDate = f.Updated.UTC().Format(time.RFC1123Z)
+	}
+	for _, it := range f.Items {
+		item := rssItem{
+			Title:       it.Title,
+			Link:        it.Link,
+			GUID:        rssGUID{Value: it.ID},
+			Author:      it.Author.Name,
+			PubDate:     it.Published.UTC().Format(time.RFC1123Z),
+			Description: it.Summary,
+		}
+		if it.Content != "" {
+			item.Content = &cdata{Value: it.Content}
+		}
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+	return marshalXML(doc)
+}
+
+// ========== Atom 1.0 ==========
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Link      atomLink    `xml:"link"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Author    atomPerson  `xml:"author"`
+	Summary   string      `xml:"summary,omitempty"`
+	Content   *atomString `xml:"content,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomString struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Atom 编码为 Atom 1.0
+func Atom(f *Feed) ([]byte, error) {
+	doc := atomFeed{
+		Title:   f.Title,
+		ID:      f.FeedURL,
+		Updated: f.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.Link, Rel: "alternate", Type: "text/html"},
+			{Href: f.FeedURL, Rel: "self", Type: "application/atom+xml"},
+		},
+	}
+	for _, it := range f.Items {
+		entry := atomEntry{
+			Title:     it.Title,
+			ID:        it.ID,
+			Link:      atomLink{Href: it.Link, Rel: "alternate", Type: "text/html"},
+			Published: it.Published.UTC().Format(time.RFC3339),
+			Updated:   it.Updated.UTC().Format(time.RFC3339),
+			Author:    atomPerson{Name: it.Author.Name, URI: it.Author.URL},
+			Summary:   it.Summary,
+		}
+		if it.Content != "" {
+			entry.Content = &atomString{Type: "html", Value: it.Content}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return marshalXML(doc)
+}
+
+// ========== JSON Feed 1.1 ==========
+
+type jsonFeed struct {
+	Version     string       `json:"version"`
+	Title       string       `json:"title"`
+	HomePageURL string       `json:"home_page_url,omitempty"`
+	FeedURL     string       `json:"feed_url,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Language    string       `json:"language,omitempty"`
+	Items       []jsonItem   `json:"items"`
+	Authors     []jsonAuthor `json:"authors,omitempty"`
+}
+
+type jsonItem struct {
+	ID            string       `json:"id"`
+	URL           string       `json:"url,omitempty"`
+	Title         string       `json:"title,omitempty"`
+	ContentHTML   string       `json:"content_html,omitempty"`
+	Summary       string       `json:"summary,omitempty"`
+	DatePublished string       `json:"date_published,omitempty"`
+	DateModified  string       `json:"date_modified,omitempty"`
+	Authors       []jsonAuthor `json:"authors,omitempty"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// JSON 编码为 JSON Feed 1.1；条目必须带 content_html 或 content_text，摘要模式下用摘要代替
+func JSON(f *Feed) ([]byte, error) {
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     f.FeedURL,
+		Description: f.Description,
+		Language:    f.Language,
+		Items:       make([]jsonItem, 0, len(f.Items)),
+	}
+	for _, it := range f.Items {
+		content := it.Content
+		if content == "" {
+			content = "<p>" + xmlEscape(it.Summary) + "</p>"
+		}
+		doc.Items = append(doc.Items, jsonItem{
+			ID:            it.ID,
+			URL:           it.Link,
+			Title:         it.Title,
+			ContentHTML:   content,
+			Summary:       it.Summary,
+			DatePublished: it.Published.UTC().Format(time.RFC3339),
+			DateModified:  it.Updated.UTC().Format(time.RFC3339),
+			Authors:       []jsonAuthor{{Name: it.Author.Name, URL: it.Author.URL}},
+		})
+	}
+	return json.Marshal(doc)
+}
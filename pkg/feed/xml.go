@@ -0,0 +1,21 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+)
+
+// marshalXML 输出带 XML 声明的文档
+func marshalXML(v any) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,118 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+
+	_ "image/gif" // 注册 GIF 解码器
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // 注册 WebP 解码器
+)
+
+// jpegQuality 输出 JPEG 的质量，兼顾清晰度和体积
+const jpegQuality = 85
+
+// ErrTooLarge 图片像素数超过限制（防止解压炸弹）
+var ErrTooLarge = errors.New("imaging: image too large")
+
+// Decode 解码图片并按 EXIF 方向旋转，maxPixels <= 0 表示不限制
+func Decode(data []byte, orientation, maxPixels int) (*image.NRGBA, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if maxPixels > 0 && cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, src.Bounds().Dx(), src.Bounds().Dy()))
+	draw.Draw(img, img.Bounds(), src, src.Bounds().Min, draw.Src)
+	return orient(img, orientation), nil
+}
+
+// Fit 等比缩放到 maxW x maxH 以内，不放大
+func Fit(img *image.NRGBA, maxW, maxH int) *image.NRGBA {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	if w <= maxW && h <= maxH {
+		return img
+	}
+	scale := min(float64(maxW)/float64(w), float64(maxH)/float64(h))
+	return resize(img, img.Bounds(), max(1, int(float64(w)*scale)), max(1, int(float64(h)*scale)))
+}
+
+// Square 居中裁剪为正方形并缩放到 size（原图不足时不放大）
+func Square(img *image.NRGBA, size int) *image.NRGBA {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	side := min(w, h)
+	crop := image.Rect((w-side)/2, (h-side)/2, (w-side)/2+side, (h-side)/2+side)
+	return resize(img, crop, min(size, side), min(size, side))
+}
+
+// Encode 编码为浏览器通用的格式：有透明像素用 PNG，否则用 JPEG；编码结果不含任何元数据
+func Encode(img *image.NRGBA) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if !img.Opaque() {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/png", nil
+	}
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/jpeg", nil
+}
+
+func resize(img *image.NRGBA, src image.Rectangle, w, h int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, src, xdraw.Src, nil)
+	return dst
+}
+
+// orient 按 EXIF Orientation（1-8）翻转/旋转图片
+func orient(img *image.NRGBA, orientation int) *image.NRGBA {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 { // 5-8 需要交换宽高
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // 水平翻转
+				dx, dy = w-1-x, y
+			case 3: // 旋转 180°
+				dx, dy = w-1-x, h-1-y
+			case 4: // 垂直翻转
+				dx, dy = x, h-1-y
+			case 5: // 沿主对角线翻转
+				dx, dy = y, x
+			case 6: // 顺时针旋转 90°
+				dx, dy = h-1-y, x
+			case 7: // 沿副对角线翻转
+				dx, dy = h-1-y, w-1-x
+			case 8: // 逆时针旋转 90°
+				dx, dy = y, w-1-x
+			}
+			si := img.PixOffset(x, y)
+			di := dst.PixOffset(dx, dy)
+			copy(dst.Pix[di:di+4], img.Pix[si:si+4])
+		}
+	}
+	return dst
+}
@@ -0,0 +1,275 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// 图片元数据处理：上传时去掉 EXIF（可能包含 GPS 位置、设备信息等隐私数据），
+// 但需要先读出方向标记，由上传流程把旋转写入像素后再保存
+
+var (
+	pngSignature  = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+	gifSignatures = [][]byte{[]byte("GIF87a"), []byte("GIF89a")}
+)
+
+// ErrMalformed 图片结构无法解析（截断或伪造的文件），不能确定元数据已去除
+var ErrMalformed = errors.New("imaging: malformed image")
+
+// StripMetadata 移除 JPEG/PNG/WebP/GIF 中的 EXIF、XMP、IPTC、注释等元数据；
+// 结构无法解析或不是这四种格式时返回 ErrMalformed，不会把原始内容原样放行
+func StripMetadata(data []byte) ([]byte, error) {
+	switch {
+	case len(data) > 4 && data[0] == 0xFF && data[1] == 0xD8:
+		return stripJPEG(data)
+	case bytes.HasPrefix(data, pngSignature):
+		return stripPNG(data)
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return stripWebP(data)
+	case len(data) >= 6 && (bytes.Equal(data[:6], gifSignatures[0]) || bytes.Equal(data[:6], gifSignatures[1])):
+		return stripGIF(data)
+	default:
+		return nil, ErrMalformed
+	}
+}
+
+// stripJPEG 逐段复制 SOS（图像数据开始）之前的标记段，丢弃 APP1(EXIF/XMP)、APP13(IPTC) 和 COM
+func stripJPEG(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xFF {
+			return nil, ErrMalformed
+		}
+		marker := data[i+1]
+		if marker == 0xDA { // SOS 之后是压缩数据，整体保留
+			return append(out, data[i:]...), nil
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, ErrMalformed
+		}
+		if marker != 0xE1 && marker != 0xED && marker != 0xFE {
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+	return nil, ErrMalformed
+}
+
+// stripPNG 丢弃 tEXt / zTXt / iTXt / eXIf / tIME 块，文件必须以 IEND 结尾
+func stripPNG(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+	i := len(pngSignature)
+	for i+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[i : i+4]))
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, ErrMalformed
+		}
+		typ := string(data[i+4 : i+8])
+		switch typ {
+		case "tEXt", "zTXt", "iTXt", "eXIf", "tIME":
+		default:
+			out = append(out, data[i:end]...)
+		}
+		i = end
+		if typ == "IEND" {
+			return out, nil
+		}
+	}
+	return nil, ErrMalformed
+}
+
+// WebP 扩展头（VP8X）中表示含有 EXIF / XMP 块的标志位
+const (
+	webpFlagEXIF = 0x08
+	webpFlagXMP  = 0x04
+)
+
+// stripWebP 丢弃 RIFF 容器中的 EXIF 和 "XMP " 块，同时清除 VP8X 中对应的标志位并重写 RIFF 长度
+func stripWebP(data []byte) ([]byte, error) {
+	size := int(binary.LittleEndian.Uint32(data[4:8]))
+	if size < 4 || 8+size > len(data) {
+		return nil, ErrMalformed
+	}
+	body := data[12 : 8+size]
+
+	out := make([]byte, 12, len(data))
+	copy(out, data[:12])
+	hasImage := false
+	for i := 0; i < len(body); {
+		if i+8 > len(body) {
+			return nil, ErrMalformed
+		}
+		fourcc := string(body[i : i+4])
+		length := int(binary.LittleEndian.Uint32(body[i+4 : i+8]))
+		end := i + 8 + length + length&1 // 块数据按偶数字节对齐
+		if length < 0 || end > len(body) {
+			return nil, ErrMalformed
+		}
+		switch fourcc {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			if length < 1 {
+				return nil, ErrMalformed
+			}
+			start := len(out)
+			out = append(out, body[i:end]...)
+			out[start+8] &^= webpFlagEXIF | webpFlagXMP
+		default:
+			if fourcc == "VP8 " || fourcc == "VP8L" || fourcc == "ANMF" {
+				hasImage = true
+			}
+			out = append(out, body[i:end]...)
+		}
+		i = end
+	}
+	if !hasImage {
+		return nil, ErrMalformed
+	}
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(out)-8))
+	return out, nil
+}
+
+// stripGIF 丢弃注释扩展和除循环播放（NETSCAPE2.0 / ANIMEXTS1.0）以外的应用扩展（如 XMP）
+func stripGIF(data []byte) ([]byte, error) {
+	// 文件头 6 字节 + 逻辑屏幕描述符 7 字节，之后可能跟全局调色板
+	if len(data) < 13 {
+		return nil, ErrMalformed
+	}
+	i := 13
+	if flags := data[10]; flags&0x80 != 0 {
+		i += 3 << (flags&0x07 + 1)
+	}
+	if i > len(data) {
+		return nil, ErrMalformed
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:i]...)
+	for i < len(data) {
+		switch data[i] {
+		case 0x3B: // 文件结束
+			return append(out, 0x3B), nil
+		case 0x2C: // 图像描述符 10 字节，可能跟局部调色板，之后是 LZW 最小码长和数据子块
+			start := i
+			if i+10 > len(data) {
+				return nil, ErrMalformed
+			}
+			flags := data[i+9]
+			i += 10
+			if flags&0x80 != 0 {
+				i += 3 << (flags&0x07 + 1)
+			}
+			i++ // LZW 最小码长
+			end, ok := skipGIFSubBlocks(data, i)
+			if !ok {
+				return nil, ErrMalformed
+			}
+			out = append(out, data[start:end]...)
+			i = end
+		case 0x21: // 扩展块：标签 + 数据子块
+			if i+2 > len(data) {
+				return nil, ErrMalformed
+			}
+			label := data[i+1]
+			end, ok := skipGIFSubBlocks(data, i+2)
+			if !ok {
+				return nil, ErrMalformed
+			}
+			keep := true
+			switch label {
+			case 0xFE: // 注释
+				keep = false
+			case 0xFF: // 应用扩展，第一个子块为 11 字节的应用标识
+				app := data[i+2 : end]
+				keep = len(app) >= 12 && app[0] == 11 &&
+					(string(app[1:12]) == "NETSCAPE2.0" || string(app[1:12]) == "ANIMEXTS1.0")
+			}
+			if keep {
+				out = append(out, data[i:end]...)
+			}
+			i = end
+		default:
+			return nil, ErrMalformed
+		}
+	}
+	return nil, ErrMalformed
+}
+
+// skipGIFSubBlocks 跳过以 0 长度块结尾的数据子块序列，返回其后的位置
+func skipGIFSubBlocks(data []byte, i int) (int, bool) {
+	for i < len(data) {
+		n := int(data[i])
+		i++
+		if n == 0 {
+			return i, true
+		}
+		i += n
+	}
+	return 0, false
+}
+
+// JPEGOrientation 读取 EXIF 方向标记（1-8），没有时返回 1
+func JPEGOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xFF || data[i+1] == 0xDA {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return 1
+		}
+		if data[i+1] == 0xE1 && bytes.HasPrefix(data[i+4:end], []byte("Exif\x00\x00")) {
+			return exifOrientation(data[i+10 : end])
+		}
+		i = end
+	}
+	return 1
+}
+
+// exifOrientation 在 TIFF 结构的 IFD0 中查找 0x0112（Orientation）标签
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset : offset+2]))
+	for n := 0; n < count; n++ {
+		entry := offset + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			value := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
@@ -0,0 +1,147 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func sampleImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+	return img
+}
+
+func TestStripJPEGRemovesEXIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, sampleImage(), nil); err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte("Exif\x00\x00GPS-SECRET")
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(payload)+2))
+	data := append(append(append([]byte{}, buf.Bytes()[:2]...), append(app1, payload...)...), buf.Bytes()[2:]...)
+
+	out, err := StripMetadata(data)
+	if err != nil {
+		t.Fatalf("StripMetadata: %v", err)
+	}
+	if bytes.Contains(out, []byte("GPS-SECRET")) {
+		t.Fatal("EXIF segment not removed")
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(out)); err != nil {
+		t.Fatalf("stripped JPEG does not decode: %v", err)
+	}
+}
+
+func TestStripPNGRemovesText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, sampleImage()); err != nil {
+		t.Fatal(err)
+	}
+	text := []byte("Comment\x00GPS-SECRET")
+	chunk := make([]byte, 8, 12+len(text))
+	binary.BigEndian.PutUint32(chunk, uint32(len(text)))
+	copy(chunk[4:], "tEXt")
+	chunk = append(append(chunk, text...), 0, 0, 0, 0)
+	sig := len(pngSignature) + 25 // 签名之后紧跟 IHDR 块（13 字节数据 + 12 字节头尾）
+	data := append(append(append([]byte{}, buf.Bytes()[:sig]...), chunk...), buf.Bytes()[sig:]...)
+
+	out, err := StripMetadata(data)
+	if err != nil {
+		t.Fatalf("StripMetadata: %v", err)
+	}
+	if bytes.Contains(out, []byte("GPS-SECRET")) {
+		t.Fatal("tEXt chunk not removed")
+	}
+	if _, err := png.Decode(bytes.NewReader(out)); err != nil {
+		t.Fatalf("stripped PNG does not decode: %v", err)
+	}
+}
+
+func webpChunk(fourcc string, payload []byte) []byte {
+	chunk := make([]byte, 8, 8+len(payload)+1)
+	copy(chunk, fourcc)
+	binary.LittleEndian.PutUint32(chunk[4:], uint32(len(payload)))
+	chunk = append(chunk, payload...)
+	if len(payload)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+	return chunk
+}
+
+func TestStripWebPRemovesEXIFAndXMP(t *testing.T) {
+	vp8x := make([]byte, 10)
+	vp8x[0] = webpFlagEXIF | webpFlagXMP
+	body := []byte("WEBP")
+	body = append(body, webpChunk("VP8X", vp8x)...)
+	body = append(body, webpChunk("VP8L", []byte{0x2F, 0, 0, 0, 0})...)
+	body = append(body, webpChunk("EXIF", []byte("GPS-SECRET"))...)
+	body = append(body, webpChunk("XMP ", []byte("<x:xmpmeta/>"))...)
+	data := binary.LittleEndian.AppendUint32([]byte("RIFF"), uint32(len(body)))
+	data = append(data, body...)
+
+	out, err := StripMetadata(data)
+	if err != nil {
+		t.Fatalf("StripMetadata: %v", err)
+	}
+	if bytes.Contains(out, []byte("GPS-SECRET")) || bytes.Contains(out, []byte("xmpmeta")) {
+		t.Fatal("EXIF/XMP chunk not removed")
+	}
+	if got := int(binary.LittleEndian.Uint32(out[4:8])); got != len(out)-8 {
+		t.Fatalf("RIFF size = %d, want %d", got, len(out)-8)
+	}
+	if flags := out[20]; flags&(webpFlagEXIF|webpFlagXMP) != 0 {
+		t.Fatalf("VP8X flags = %#x, metadata bits still set", flags)
+	}
+}
+
+func TestStripGIFRemovesComments(t *testing.T) {
+	var buf bytes.Buffer
+	if err := gif.Encode(&buf, sampleImage(), nil); err != nil {
+		t.Fatal(err)
+	}
+	raw := buf.Bytes()
+	header := 13
+	if flags := raw[10]; flags&0x80 != 0 {
+		header += 3 << (flags&0x07 + 1)
+	}
+	comment := append([]byte{0x21, 0xFE, 10}, []byte("GPS-SECRET")...)
+	comment = append(comment, 0)
+	data := append(append(append([]byte{}, raw[:header]...), comment...), raw[header:]...)
+
+	out, err := StripMetadata(data)
+	if err != nil {
+		t.Fatalf("StripMetadata: %v", err)
+	}
+	if bytes.Contains(out, []byte("GPS-SECRET")) {
+		t.Fatal("comment extension not removed")
+	}
+	if _, err := gif.Decode(bytes.NewReader(out)); err != nil {
+		t.Fatalf("stripped GIF does not decode: %v", err)
+	}
+}
+
+func TestStripMetadataRejectsMalformed(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, sampleImage(), nil); err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string][]byte{
+		"truncated jpeg": buf.Bytes()[:20],
+		"unknown format": []byte("not an image at all"),
+		"truncated webp": []byte("RIFF\xff\x00\x00\x00WEBPVP8X"),
+	}
+	for name, data := range cases {
+		if _, err := StripMetadata(data); !errors.Is(err, ErrMalformed) {
+			t.Errorf("%s: err = %v, want ErrMalformed", name, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package markup
+
+import (
+	"bytes"
+	"errors"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// 文章正文格式
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatPlain    = "plain"
+)
+
+// ErrUnknownFormat 不支持的正文格式
+var ErrUnknownFormat = errors.New("markup: unknown content format")
+
+var (
+	// md 支持 GFM（表格、删除线、任务列表、自动链接）；原始 HTML 交给 sanitizer 统一过滤
+	md = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(gmhtml.WithUnsafe()),
+	)
+
+	// policy 在 UGC 白名单基础上保留代码块的语言 class，供前端语法高亮使用
+	policy = newPolicy()
+)
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#.-]+$`)).OnElements("code")
+	p.AllowAttrs("type", "checked", "disabled").OnElements("input") // GFM 任务列表
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
+
+// Render 将正文渲染为安全的 HTML，并为标题生成锚点 ID
+func Render(format, content string) (string, error) {
+	var raw string
+	switch format {
+	case FormatMarkdown, "":
+		var buf bytes.Buffer
+		if err := md.Convert([]byte(content), &buf); err != nil {
+			return "", err
+		}
+		raw = buf.String()
+	case FormatHTML:
+		raw = content
+	case FormatPlain:
+		raw = renderPlain(content)
+	default:
+		return "", ErrUnknownFormat
+	}
+
+	return anchorHeadings(policy.Sanitize(raw)), nil
+}
+
+// renderPlain 纯文本按空行分段，段内换行保留为 <br>
+func renderPlain(content string) string {
+	var b strings.Builder
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	for _, para := range strings.Split(content, "\n\n") {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(para), "\n", "<br>"))
+		b.WriteString("</p>\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,178 @@
+package markup
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// TOCItem 目录项，按标题在正文中出现的顺序平铺，层级由 Level 表示
+type TOCItem struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+}
+
+// ExtractTOC 从渲染后的 HTML 中提取带锚点的标题
+func ExtractTOC(content string) []TOCItem {
+	var toc []TOCItem
+	walkHeadings(content, func(level int, id, text string) string {
+		if id != "" {
+			toc = append(toc, TOCItem{Level: level, ID: id, Text: text})
+		}
+		return id
+	})
+	return toc
+}
+
+// anchorHeadings 根据标题文本为 h1-h6 重新生成唯一 ID（覆盖用户自带的 id，避免 DOM clobbering）
+func anchorHeadings(content string) string {
+	used := make(map[string]int)
+	return walkHeadings(content, func(_ int, _, text string) string {
+		id := slugify(text)
+		if id == "" {
+			id = "section"
+		}
+		if n := used[id]; n > 0 {
+			used[id] = n + 1
+			id += "-" + strconv.Itoa(n)
+		} else {
+			used[id] = 1
+		}
+		return id
+	})
+}
+
+// walkHeadings 逐个 token 复制 HTML，遇到标题时调用 fn 决定其 id；非标题部分原样输出
+func walkHeadings(content string, fn func(level int, id, text string) string) string {
+	var out, inner bytes.Buffer
+	var text strings.Builder
+	var heading *html.Token
+	level := 0
+
+	z := html.NewTokenizer(strings.NewReader(content))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		raw := z.Raw()
+
+		if heading == nil {
+			if tt == html.StartTagToken {
+				if tok := z.Token(); headingLevel(tok.Data) > 0 {
+					heading, level = &tok, headingLevel(tok.Data)
+					inner.Reset()
+					text.Reset()
+					continue
+				}
+			}
+			out.Write(raw)
+			continue
+		}
+
+		if tt == html.EndTagToken {
+			if tok := z.Token(); headingLevel(tok.Data) == level {
+				id := fn(level, attr(heading, "id"), strings.TrimSpace(text.String()))
+				setAttr(heading, "id", id)
+				out.WriteString(heading.String())
+				out.Write(inner.Bytes())
+				out.Write(raw)
+				heading = nil
+				continue
+			}
+		}
+		if tt == html.TextToken {
+			text.WriteString(html.UnescapeString(string(raw)))
+		}
+		inner.Write(raw)
+	}
+
+	// 未闭合的标题原样输出
+	if heading != nil {
+		out.WriteString(heading.String())
+		out.Write(inner.Bytes())
+	}
+	return out.String()
+}
+
+func headingLevel(tag string) int {
+	if len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6' {
+		return int(tag[1] - '0')
+	}
+	return 0
+}
+
+func attr(tok *html.Token, key string) string {
+	for _, a := range tok.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func setAttr(tok *html.Token, key, val string) {
+	for i := range tok.Attr {
+		if tok.Attr[i].Key == key {
+			tok.Attr[i].Val = val
+			return
+		}
+	}
+	tok.Attr = append(tok.Attr, html.Attribute{Key: key, Val: val})
+}
+
+// slugify 保留各语言的字母和数字（中文标题保持原文），其余字符折叠为连字符
+func slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// Summary 提取 HTML 的纯文本摘要，最多 maxRunes 个字符，超出时以省略号结尾
+func Summary(content string, maxRunes int) string {
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(content))
+	skip := 0 // 摘要中不包含代码块
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		switch tt {
+		case html.StartTagToken:
+			if name, _ := z.TagName(); string(name) == "pre" {
+				skip++
+			}
+		case html.EndTagToken:
+			if name, _ := z.TagName(); string(name) == "pre" && skip > 0 {
+				skip--
+			}
+		case html.TextToken:
+			if skip == 0 {
+				b.WriteString(html.UnescapeString(string(z.Raw())))
+				b.WriteByte(' ')
+			}
+		}
+	}
+
+	text := []rune(strings.Join(strings.Fields(b.String()), " "))
+	if len(text) <= maxRunes {
+		return string(text)
+	}
+	return strings.TrimSpace(string(text[:maxRunes])) + "…"
+}
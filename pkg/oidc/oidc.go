@@ -0,0 +1,377 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-blog-api/pkg/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// oidc 包实现通用的 OAuth2 授权码 + PKCE 登录流程：
+// 配置了 issuer 的提供方通过 discovery 文档获取端点并校验 id_token（OIDC），
+// 没有 id_token 的提供方（如 GitHub）退化为调用 userinfo 接口获取用户信息。
+
+// jwksRefreshInterval 遇到未知 kid 时重新拉取 JWKS 的最小间隔
+const jwksRefreshInterval = time.Minute
+
+// Metadata discovery 文档中用到的字段
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// TokenResponse 令牌端点的响应
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	Error       string `json:"error"`
+	ErrorDesc   string `json:"error_description"`
+}
+
+// Identity 从 id_token / userinfo 中提取的外部身份
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+	Name          string
+	Picture       string
+}
+
+// Provider 一个已配置的身份提供方
+type Provider struct {
+	cfg    config.OIDCProviderConfig
+	client *http.Client
+
+	mu          sync.Mutex
+	meta        *Metadata
+	keys        map[string]any
+	keysFetched time.Time
+}
+
+// NewProvider 创建身份提供方，discovery 文档在首次使用时才拉取
+func NewProvider(cfg config.OIDCProviderConfig, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Provider{cfg: cfg, client: client}
+}
+
+// NewProviders 根据配置创建所有启用的身份提供方（未配置 client_id 的会被跳过）
+func NewProviders(cfgs []config.OIDCProviderConfig, client *http.Client) map[string]*Provider {
+	providers := make(map[string]*Provider, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.Name == "" || cfg.ClientID == "" {
+			continue
+		}
+		providers[cfg.Name] = NewProvider(cfg, client)
+	}
+	return providers
+}
+
+// Name 提供方名称
+func (p *Provider) Name() string {
+	return p.cfg.Name
+}
+
+// metadata 获取端点信息：配置中显式写明的端点优先，其余从 discovery 文档补全
+func (p *Provider) metadata(ctx context.Context) (*Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.meta != nil {
+		return p.meta, nil
+	}
+
+	meta := &Metadata{Issuer: p.cfg.Issuer}
+	if p.cfg.Issuer != "" {
+		discoveryURL := strings.TrimSuffix(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+		if err := p.getJSON(ctx, discoveryURL, "", meta); err != nil {
+			return nil, fmt.Errorf("oidc discovery: %w", err)
+		}
+		if meta.Issuer != p.cfg.Issuer {
+			return nil, fmt.Errorf("oidc discovery: issuer mismatch %q", meta.Issuer)
+		}
+	}
+	if p.cfg.AuthorizationEndpoint != "" {
+		meta.AuthorizationEndpoint = p.cfg.AuthorizationEndpoint
+	}
+	if p.cfg.TokenEndpoint != "" {
+		meta.TokenEndpoint = p.cfg.TokenEndpoint
+	}
+	if p.cfg.UserinfoEndpoint != "" {
+		meta.UserinfoEndpoint = p.cfg.UserinfoEndpoint
+	}
+	if p.cfg.JWKSURI != "" {
+		meta.JWKSURI = p.cfg.JWKSURI
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" {
+		return nil, errors.New("oidc: missing authorization or token endpoint")
+	}
+
+	p.meta = meta
+	return meta, nil
+}
+
+// AuthCodeURL 生成跳转到提供方的授权地址
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	meta, err := p.metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", p.cfg.ClientID)
+	v.Set("redirect_uri", p.cfg.RedirectURL)
+	v.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	v.Set("state", state)
+	v.Set("code_challenge", S256Challenge(codeVerifier))
+	v.Set("code_challenge_method", "S256")
+	if p.isOIDC() {
+		v.Set("nonce", nonce)
+	}
+
+	sep := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return meta.AuthorizationEndpoint + sep + v.Encode(), nil
+}
+
+// Exchange 使用授权码和 code_verifier 换取令牌
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (*TokenResponse, error) {
+	meta, err := p.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("client_secret", p.cfg.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token TokenResponse
+	if err := p.do(req, &token); err != nil {
+		return nil, fmt.Errorf("oidc token exchange: %w", err)
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("oidc token exchange: %s %s", token.Error, token.ErrorDesc)
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("oidc token exchange: empty access_token")
+	}
+	return &token, nil
+}
+
+// Identify 校验令牌并提取外部身份：OIDC 提供方要求合法的 id_token，并用 userinfo 补全资料
+func (p *Provider) Identify(ctx context.Context, token *TokenResponse, nonce string) (*Identity, error) {
+	meta, err := p.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := map[string]any{}
+	if p.isOIDC() {
+		if token.IDToken == "" {
+			return nil, errors.New("oidc: missing id_token")
+		}
+		if claims, err = p.verifyIDToken(ctx, token.IDToken, nonce); err != nil {
+			return nil, err
+		}
+	}
+
+	if meta.UserinfoEndpoint != "" {
+		info := map[string]any{}
+		if err := p.getJSON(ctx, meta.UserinfoEndpoint, token.AccessToken, &info); err != nil {
+			return nil, fmt.Errorf("oidc userinfo: %w", err)
+		}
+		// userinfo 的 sub 必须与 id_token 一致，防止令牌被替换
+		if sub, ok := claims["sub"]; ok && info["sub"] != nil && info["sub"] != sub {
+			return nil, errors.New("oidc userinfo: subject mismatch")
+		}
+		for k, v := range info {
+			if _, exists := claims[k]; !exists {
+				claims[k] = v
+			}
+		}
+	}
+
+	identity := &Identity{
+		Subject:       firstString(claims, "sub", "id"),
+		Email:         firstString(claims, "email"),
+		EmailVerified: claimBool(claims["email_verified"]),
+		Username:      firstString(claims, "preferred_username", "login", "nickname"),
+		Name:          firstString(claims, "name"),
+		Picture:       firstString(claims, "picture", "avatar_url"),
+	}
+	if identity.Subject == "" {
+		return nil, errors.New("oidc: missing subject")
+	}
+	return identity, nil
+}
+
+// verifyIDToken 校验 id_token 的签名、签名算法、iss、aud、exp 和 nonce
+func (p *Provider) verifyIDToken(ctx context.Context, raw, nonce string) (jwt.MapClaims, error) {
+	meta, err := p.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, meta.JWKSURI, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithIssuer(meta.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("oidc id_token: %w", err)
+	}
+	if got, _ := claims["nonce"].(string); got != nonce {
+		return nil, errors.New("oidc id_token: nonce mismatch")
+	}
+	return claims, nil
+}
+
+// key 按 kid 查找公钥，未命中时（提供方轮换密钥）限频刷新 JWKS
+func (p *Provider) key(ctx context.Context, jwksURI, kid string) (any, error) {
+	if jwksURI == "" {
+		return nil, errors.New("oidc: missing jwks_uri")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	if time.Since(p.keysFetched) < jwksRefreshInterval {
+		return nil, fmt.Errorf("oidc: unknown key id %q", kid)
+	}
+
+	var set JSONWebKeySet
+	if err := p.getJSON(ctx, jwksURI, "", &set); err != nil {
+		return nil, fmt.Errorf("oidc jwks: %w", err)
+	}
+	keys := make(map[string]any, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		pub, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = pub
+	}
+	p.keys = keys
+	p.keysFetched = time.Now()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("oidc: unknown key id %q", kid)
+}
+
+// lookupKey 调用方需持有 p.mu；未携带 kid 时仅在只有一把密钥的情况下使用它
+func (p *Provider) lookupKey(kid string) (any, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *Provider) isOIDC() bool {
+	for _, scope := range p.cfg.Scopes {
+		if scope == "openid" {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Provider) getJSON(ctx context.Context, rawURL, accessToken string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	return p.do(req, out)
+}
+
+func (p *Provider) do(req *http.Request, out any) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return json.Unmarshal(body, out)
+}
+
+// firstString 依次读取多个候选字段（兼容不同提供方的命名），数字 ID 转为字符串
+func firstString(claims map[string]any, keys ...string) string {
+	for _, key := range keys {
+		switch v := claims[key].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+	return ""
+}
+
+// claimBool 部分提供方把 email_verified 返回为字符串
+func claimBool(v any) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		return b == "true"
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package oidc_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go-blog-api/pkg/oidc"
+	"go-blog-api/pkg/oidc/oidctest"
+)
+
+// login 走完授权跳转和授权码换取令牌，返回令牌和授权时使用的 nonce
+func login(t *testing.T, p *oidc.Provider, srv *oidctest.Server) (*oidc.TokenResponse, string) {
+	t.Helper()
+	ctx := context.Background()
+
+	nonce, _ := oidc.RandomString(32)
+	verifier, _ := oidc.RandomString(32)
+	authURL, err := p.AuthCodeURL(ctx, "state-1", nonce, verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	code, _, err := srv.Login(authURL)
+	if err != nil {
+		t.Fatalf("login at provider: %v", err)
+	}
+	token, err := p.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	return token, nonce
+}
+
+func TestPKCERoundTrip(t *testing.T) {
+	srv := oidctest.NewServer()
+	defer srv.Close()
+	p := oidc.NewProvider(srv.ProviderConfig("mock"), nil)
+	ctx := context.Background()
+
+	verifier, _ := oidc.RandomString(32)
+	authURL, err := p.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("code_challenge") != oidc.S256Challenge(verifier) || q.Get("code_challenge_method") != "S256" {
+		t.Fatalf("authorization url missing S256 challenge: %s", authURL)
+	}
+	if q.Get("state") != "state-1" || q.Get("nonce") != "nonce-1" {
+		t.Fatalf("authorization url missing state or nonce: %s", authURL)
+	}
+
+	code, state, err := srv.Login(authURL)
+	if err != nil {
+		t.Fatalf("login at provider: %v", err)
+	}
+	if state != "state-1" {
+		t.Fatalf("state = %q, want state-1", state)
+	}
+
+	// 错误的 code_verifier 无法换取令牌
+	if _, err := p.Exchange(ctx, code, verifier+"x"); err == nil {
+		t.Fatal("Exchange with wrong verifier succeeded")
+	}
+
+	// 授权码已被上一次请求消耗，重新授权后用正确的 verifier 换取
+	code, _, err = srv.Login(authURL)
+	if err != nil {
+		t.Fatalf("login at provider: %v", err)
+	}
+	token, err := p.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	identity, err := p.Identify(ctx, token, "nonce-1")
+	if err != nil {
+		t.Fatalf("Identify: %v", err)
+	}
+	if identity.Subject != "user-1" || identity.Email != "alice@example.com" || !identity.EmailVerified ||
+		identity.Username != "alice" || identity.Name != "Alice" {
+		t.Fatalf("unexpected identity %+v", identity)
+	}
+}
+
+func TestIdentifyRejectsInvalidIDToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(srv *oidctest.Server)
+		nonce   func(nonce string) string
+		wantErr string
+	}{
+		{name: "nonce mismatch", nonce: func(string) string { return "other-nonce" }, wantErr: "nonce mismatch"},
+		{name: "wrong issuer", setup: func(srv *oidctest.Server) { srv.Issuer = "https://evil.example.com" }, wantErr: "issuer"},
+		{name: "wrong audience", setup: func(srv *oidctest.Server) { srv.Audience = "other-client" }, wantErr: "audience"},
+		{name: "expired", setup: func(srv *oidctest.Server) { srv.TTL = -5 * time.Minute }, wantErr: "expired"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := oidctest.NewServer()
+			defer srv.Close()
+			if tt.setup != nil {
+				tt.setup(srv)
+			}
+			p := oidc.NewProvider(srv.ProviderConfig("mock"), nil)
+
+			token, nonce := login(t, p, srv)
+			if tt.nonce != nil {
+				nonce = tt.nonce(nonce)
+			}
+			_, err := p.Identify(context.Background(), token, nonce)
+			if err == nil {
+				t.Fatal("Identify succeeded, want error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
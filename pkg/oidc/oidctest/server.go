@@ -0,0 +1,250 @@
+// Package oidctest 提供本地 mock OIDC 提供方（discovery、授权、令牌、JWKS、userinfo），
+// 用测试密钥签发 id_token，用于在不依赖外部服务的情况下测试授权码 + PKCE 登录流程
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"go-blog-api/pkg/config"
+	"go-blog-api/pkg/oidc"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	ClientID     = "test-client"
+	ClientSecret = "test-secret"
+	RedirectURL  = "http://app.test/callback"
+	keyID        = "test-key"
+)
+
+// User 提供方上的用户，授权时写入授权码，换取令牌后体现在 id_token 和 userinfo 中
+type User struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+	Name          string
+}
+
+// Server mock 提供方；导出字段用于构造异常的 id_token，需在授权前设置
+type Server struct {
+	*httptest.Server
+
+	User     User
+	Issuer   string        // id_token 的 iss，为空时与 discovery 的 issuer 一致
+	Audience string        // id_token 的 aud，为空时为 ClientID
+	TTL      time.Duration // id_token 的有效期（相对签发时间），为 0 时为 5 分钟，负数表示已过期
+
+	key *rsa.PrivateKey
+
+	mu     sync.Mutex
+	codes  map[string]grant // 授权码，只能使用一次
+	tokens map[string]User  // access_token 对应的用户
+}
+
+type grant struct {
+	challenge string
+	nonce     string
+	user      User
+}
+
+// NewServer 启动 mock 提供方，测试结束时需调用 Close
+func NewServer() *Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	s := &Server{
+		User:   User{Subject: "user-1", Email: "alice@example.com", EmailVerified: true, Username: "alice", Name: "Alice"},
+		key:    key,
+		codes:  make(map[string]grant),
+		tokens: make(map[string]User),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("GET /authorize", s.authorize)
+	mux.HandleFunc("POST /token", s.token)
+	mux.HandleFunc("GET /jwks", s.jwks)
+	mux.HandleFunc("GET /userinfo", s.userinfo)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// ProviderConfig 指向该 mock 提供方的配置，端点全部通过 discovery 获取
+func (s *Server) ProviderConfig(name string) config.OIDCProviderConfig {
+	return config.OIDCProviderConfig{
+		Name:         name,
+		Issuer:       s.URL,
+		ClientID:     ClientID,
+		ClientSecret: ClientSecret,
+		RedirectURL:  RedirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+	}
+}
+
+// Login 模拟用户在提供方完成登录：访问授权地址，返回回调中的 code 和 state
+func (s *Server) Login(authURL string) (code, state string, err error) {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	location, err := resp.Location()
+	if err != nil {
+		return "", "", err
+	}
+	return location.Query().Get("code"), location.Query().Get("state"), nil
+}
+
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, oidc.Metadata{
+		Issuer:                s.URL,
+		AuthorizationEndpoint: s.URL + "/authorize",
+		TokenEndpoint:         s.URL + "/token",
+		UserinfoEndpoint:      s.URL + "/userinfo",
+		JWKSURI:               s.URL + "/jwks",
+	})
+}
+
+// authorize 不需要交互，直接以当前 User 同意授权并跳转回 redirect_uri
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != ClientID || q.Get("redirect_uri") != RedirectURL ||
+		q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString()
+	s.mu.Lock()
+	s.codes[code] = grant{challenge: q.Get("code_challenge"), nonce: q.Get("nonce"), user: s.User}
+	s.mu.Unlock()
+
+	v := url.Values{"code": {code}, "state": {q.Get("state")}}
+	http.Redirect(w, r, RedirectURL+"?"+v.Encode(), http.StatusFound)
+}
+
+// token 校验授权码、客户端凭据和 PKCE code_verifier，签发 access_token 和 id_token
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" ||
+		r.PostForm.Get("client_id") != ClientID || r.PostForm.Get("client_secret") != ClientSecret ||
+		r.PostForm.Get("redirect_uri") != RedirectURL {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	s.mu.Lock()
+	g, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+	if !ok || oidc.S256Challenge(r.PostForm.Get("code_verifier")) != g.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idToken, err := s.signIDToken(g)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	accessToken := randomString()
+	s.mu.Lock()
+	s.tokens[accessToken] = g.user
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, oidc.TokenResponse{AccessToken: accessToken, TokenType: "Bearer", IDToken: idToken})
+}
+
+func (s *Server) signIDToken(g grant) (string, error) {
+	issuer := s.Issuer
+	if issuer == "" {
+		issuer = s.URL
+	}
+	audience := s.Audience
+	if audience == "" {
+		audience = ClientID
+	}
+	ttl := s.TTL
+	if ttl == 0 {
+		ttl = 5 * time.Minute
+	}
+
+	now := time.Now()
+	issuedAt := now
+	if ttl < 0 {
+		issuedAt = now.Add(ttl).Add(-time.Minute)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":                issuer,
+		"aud":                audience,
+		"sub":                g.user.Subject,
+		"iat":                issuedAt.Unix(),
+		"exp":                now.Add(ttl).Unix(),
+		"nonce":              g.nonce,
+		"email":              g.user.Email,
+		"email_verified":     g.user.EmailVerified,
+		"preferred_username": g.user.Username,
+	})
+	token.Header["kid"] = keyID
+	return token.SignedString(s.key)
+}
+
+func (s *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	jwk, err := oidc.NewJSONWebKey(keyID, "RS256", &s.key.PublicKey)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, oidc.JSONWebKeySet{Keys: []oidc.JSONWebKey{jwk}})
+}
+
+func (s *Server) userinfo(w http.ResponseWriter, r *http.Request) {
+	const prefix = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if len(auth) <= len(prefix) || auth[:len(prefix)] != prefix {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	s.mu.Lock()
+	user, ok := s.tokens[auth[len(prefix):]]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"sub":            user.Subject,
+		"email":          user.Email,
+		"email_verified": user.EmailVerified,
+		"name":           user.Name,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
@@ -0,0 +1,74 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// MaxURLs 单个 sitemap 文件允许的最大 URL 数（sitemaps.org 协议限制）
+const MaxURLs = 50000
+
+const xmlns = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// URL sitemap 中的一条地址
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+// Index sitemap 索引中的一个子 sitemap
+type Index struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlset struct {
+	XMLName xml.Name `xml:"urlset"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	URLs    []entry  `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name `xml:"sitemapindex"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Sitemaps []entry  `xml:"sitemap"`
+}
+
+type entry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Encode 生成 urlset 文档
+func Encode(urls []URL) ([]byte, error) {
+	doc := urlset{Xmlns: xmlns, URLs: make([]entry, 0, len(urls))}
+	for _, u := range urls {
+		doc.URLs = append(doc.URLs, newEntry(u.Loc, u.LastMod))
+	}
+	return marshal(doc)
+}
+
+// EncodeIndex 生成 sitemapindex 文档
+func EncodeIndex(sitemaps []Index) ([]byte, error) {
+	doc := sitemapIndex{Xmlns: xmlns, Sitemaps: make([]entry, 0, len(sitemaps))}
+	for _, s := range sitemaps {
+		doc.Sitemaps = append(doc.Sitemaps, newEntry(s.Loc, s.LastMod))
+	}
+	return marshal(doc)
+}
+
+func newEntry(loc string, lastMod time.Time) entry {
+	e := entry{Loc: loc}
+	if !lastMod.IsZero() {
+		e.LastMod = lastMod.UTC().Format(time.RFC3339)
+	}
+	return e
+}
+
+func marshal(v any) ([]byte, error) {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
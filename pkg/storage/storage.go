@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"go-blog-api/pkg/config"
+)
+
+// ErrNotFound 对象不存在
+var ErrNotFound = errors.New("storage: object not found")
+
+// BlobStore 二进制对象存储的抽象，key 为 "/" 分隔的相对路径
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New 根据配置创建存储后端：local（本地文件系统）或 s3（S3 兼容的对象存储）
+func New(cfg config.StorageConfig) (BlobStore, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return NewLocalStore(cfg.LocalDir), nil
+	case "s3":
+		return NewS3Store(cfg.S3), nil
+	default:
+		return nil, fmt.Errorf("storage: unknown driver %q", cfg.Driver)
+	}
+}
+
+// validKey 拒绝绝对路径和 ".." 等可能越界的 key
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
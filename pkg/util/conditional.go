@@ -0,0 +1,48 @@
+package util
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CheckNotModified 设置 ETag / Last-Modified 响应头，并按条件请求头判断客户端缓存是否仍然有效；
+// 返回 true 时已写入 304，调用方直接返回即可
+func CheckNotModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	if etag != "" {
+		c.Header("ETag", etag)
+	}
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	// If-None-Match 优先于 If-Modified-Since（RFC 9110 13.2.2）
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		if etag != "" && etagMatch(inm, etag) {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+		return false
+	}
+	if ims := c.GetHeader("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		if t, err := http.ParseTime(ims); err == nil && !lastModified.Truncate(time.Second).After(t) {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// etagMatch 弱比较：忽略 W/ 前缀，支持逗号分隔的多个值和 *
+func etagMatch(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+
+	"go-blog-api/pkg/config"
+)
+
+// 敏感字段加密存储相关的工具函数（AES-256-GCM）
+
+// encryptionKey 由配置中的 security.encryption_key 派生出 32 字节密钥
+func encryptionKey() []byte {
+	sum := sha256.Sum256([]byte(config.AppConfig.Security.EncryptionKey))
+	return sum[:]
+}
+
+// EncryptString 加密明文，返回 base64 编码的 nonce+密文
+func EncryptString(plain string) (string, error) {
+	block, err := aes.NewCipher(encryptionKey())
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plain), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptString 解密 EncryptString 的输出
+func DecryptString(encoded string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	block, err := aes.NewCipher(encryptionKey())
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// HashToken 对高熵的随机凭证（恢复码等）做 SHA-256 摘要，便于按哈希查找
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// SignMessage 使用服务端密钥对消息做 HMAC-SHA256 签名，purpose 用于区分不同用途的签名
+func SignMessage(purpose, msg string) string {
+	mac := hmac.New(sha256.New, encryptionKey())
+	mac.Write([]byte(purpose + ":" + msg))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyMessage 常量时间比较签名
+func VerifyMessage(purpose, msg, signature string) bool {
+	return hmac.Equal([]byte(SignMessage(purpose, msg)), []byte(signature))
+}
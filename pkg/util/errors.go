@@ -29,19 +29,42 @@ var (
 	ErrBadRequest         = NewBizError(http.StatusBadRequest, 40000, "请求参数错误")
 	ErrInvalidParam       = NewBizError(http.StatusBadRequest, 40001, "参数校验失败")
 	ErrInvalidCredentials = NewBizError(http.StatusBadRequest, 40002, "用户名或密码错误")
+	ErrInvalidOTP         = NewBizError(http.StatusBadRequest, 40003, "验证码错误")
+	ErrTwoFactorDisabled  = NewBizError(http.StatusBadRequest, 40004, "未开启两步验证")
+	ErrOAuthState         = NewBizError(http.StatusBadRequest, 40005, "登录状态校验失败，请重新登录")
 	ErrUnauthorized       = NewBizError(http.StatusUnauthorized, 40100, "未授权，请先登录")
 	ErrTokenExpired       = NewBizError(http.StatusUnauthorized, 40101, "登录已过期")
+	ErrChallengeExpired   = NewBizError(http.StatusUnauthorized, 40102, "二次验证已失效，请重新登录")
+	ErrOAuthFailed        = NewBizError(http.StatusUnauthorized, 40103, "第三方登录失败")
 	ErrForbidden          = NewBizError(http.StatusForbidden, 40300, "无权限访问")
+	ErrInsufficientScope  = NewBizError(http.StatusForbidden, 40301, "API Key 权限不足")
+	ErrAccountSuspended   = NewBizError(http.StatusForbidden, 40302, "账号已被暂停使用")
+	ErrAccountBanned      = NewBizError(http.StatusForbidden, 40303, "账号已被封禁")
+	ErrPasswordReset      = NewBizError(http.StatusForbidden, 40304, "需要重置密码后才能继续使用")
 	ErrNotFound           = NewBizError(http.StatusNotFound, 40400, "资源不存在")
 	ErrUserNotFound       = NewBizError(http.StatusNotFound, 40401, "用户不存在")
 	ErrArticleNotFound    = NewBizError(http.StatusNotFound, 40402, "文章不存在")
+	ErrProviderNotFound   = NewBizError(http.StatusNotFound, 40403, "不支持的登录方式")
+	ErrMediaNotFound      = NewBizError(http.StatusNotFound, 40404, "文件不存在")
+	ErrTagNotFound        = NewBizError(http.StatusNotFound, 40405, "标签不存在")
+	ErrCommentNotFound    = NewBizError(http.StatusNotFound, 40406, "评论不存在")
+	ErrListNotFound       = NewBizError(http.StatusNotFound, 40407, "阅读清单不存在")
+	ErrWebhookNotFound    = NewBizError(http.StatusNotFound, 40408, "webhook 不存在")
 	ErrConflict           = NewBizError(http.StatusConflict, 40900, "资源冲突")
 	ErrUsernameExists     = NewBizError(http.StatusConflict, 40901, "用户名已存在")
 	ErrEmailExists        = NewBizError(http.StatusConflict, 40902, "邮箱已被注册")
+	ErrTwoFactorEnabled   = NewBizError(http.StatusConflict, 40903, "已开启两步验证")
+	ErrSlugExists         = NewBizError(http.StatusConflict, 40904, "slug 已被使用")
+	ErrIdempotencyBusy    = NewBizError(http.StatusConflict, 40905, "相同 Idempotency-Key 的请求正在处理中，请稍后重试")
+	ErrFileTooLarge       = NewBizError(http.StatusRequestEntityTooLarge, 41300, "文件过大")
+	ErrUnsupportedMedia   = NewBizError(http.StatusUnsupportedMediaType, 41500, "不支持的文件类型")
+	ErrIdempotencyReused  = NewBizError(http.StatusUnprocessableEntity, 42200, "Idempotency-Key 已用于其他请求")
+	ErrTwoFactorLocked    = NewBizError(http.StatusTooManyRequests, 42900, "验证码错误次数过多，请稍后再试")
 
 	// 服务端错误 5xx
 	ErrInternal = NewBizError(http.StatusInternalServerError, 50000, "服务器内部错误")
 	ErrDatabase = NewBizError(http.StatusInternalServerError, 50001, "数据库错误")
+	ErrTimeout  = NewBizError(http.StatusGatewayTimeout, 50400, "请求超时，请稍后重试")
 )
 
 // WithMsg 复制错误并替换消息（用于动态消息场景）
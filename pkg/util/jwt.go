@@ -1,6 +1,8 @@
 package util
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"go-blog-api/pkg/config"
@@ -10,41 +12,132 @@ import (
 
 // 封装 JWT 相关的工具函数和常量
 
+// 特殊用途 token 的用途标记，不能用于访问业务接口
+const (
+	PurposeTwoFactor     = "2fa_challenge"  // 二次验证挑战
+	PurposePasswordReset = "password_reset" // 管理员要求重置密码后，登录只拿到该 token，用于设置新密码
+)
+
+var errTokenPurpose = errors.New("token purpose mismatch")
+
 type Claims struct {
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
+	UserID    uint   `json:"user_id"`
+	Username  string `json:"username"`
+	Purpose   string `json:"purpose,omitempty"` // 为空表示普通访问 token
+	SessionID string `json:"sid,omitempty"`     // 登录会话 ID，会话被吊销后 token 立即失效
+	// ImpersonatorID 管理员代登录时为管理员 ID，携带该声明的请求都会被标记
+	ImpersonatorID uint `json:"imp,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken 生成 Token
-func GenerateToken(userID uint, username string) (string, error) {
+// GenerateToken 生成 Token，sessionID 为本次登录创建的会话
+func GenerateToken(userID uint, username, sessionID string) (string, error) {
+	cfg := config.AppConfig.JWT
+	return signToken(userID, username, "", sessionID, 0, time.Duration(cfg.ExpireHours)*time.Hour)
+}
+
+// GenerateImpersonationToken 生成管理员代登录的 token，sessionID 为为此创建的会话
+func GenerateImpersonationToken(userID uint, username, sessionID string, impersonatorID uint, ttl time.Duration) (string, error) {
+	return signToken(userID, username, "", sessionID, impersonatorID, ttl)
+}
+
+// GenerateChallengeToken 生成短期有效的二次验证挑战 token
+func GenerateChallengeToken(userID uint, username string) (string, error) {
+	cfg := config.AppConfig.TwoFactor
+	return signToken(userID, username, PurposeTwoFactor, "", 0, time.Duration(cfg.ChallengeExpireMinutes)*time.Minute)
+}
+
+// GeneratePasswordResetToken 生成短期有效的重置密码 token，有效期与二次验证挑战相同
+func GeneratePasswordResetToken(userID uint, username string) (string, error) {
+	cfg := config.AppConfig.TwoFactor
+	return signToken(userID, username, PurposePasswordReset, "", 0, time.Duration(cfg.ChallengeExpireMinutes)*time.Minute)
+}
+
+func signToken(userID uint, username, purpose, sessionID string, impersonatorID uint, ttl time.Duration) (string, error) {
+	if jwtKeys == nil {
+		return "", errKeysNotReady
+	}
+	key, err := jwtKeys.current()
+	if err != nil {
+		return "", err
+	}
+
 	cfg := config.AppConfig.JWT
 	nowTime := time.Now()
-	expireTime := nowTime.Add(time.Duration(cfg.ExpireHours) * time.Hour)
+	expireTime := nowTime.Add(ttl)
 
 	claims := Claims{
-		UserID:   userID,
-		Username: username,
+		UserID:         userID,
+		Username:       username,
+		Purpose:        purpose,
+		SessionID:      sessionID,
+		ImpersonatorID: impersonatorID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expireTime),
+			IssuedAt:  jwt.NewNumericDate(nowTime),
+			NotBefore: jwt.NewNumericDate(nowTime),
 			Issuer:    cfg.Issuer,
 		},
 	}
+	if cfg.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{cfg.Audience}
+	}
 
-	tokenClaims := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	token, err := tokenClaims.SignedString([]byte(cfg.Secret))
+	tokenClaims := jwt.NewWithClaims(key.method, claims)
+	tokenClaims.Header["kid"] = key.kid
+	token, err := tokenClaims.SignedString(key.private)
 	return token, err
 }
 
-// ParseToken 解析 Token
+// ParseToken 解析访问 Token，拒绝挑战 token 等特殊用途的 token
 func ParseToken(token string) (*Claims, error) {
-	secret := []byte(config.AppConfig.JWT.Secret)
+	return parseToken(token, "")
+}
+
+// ParseChallengeToken 解析二次验证挑战 token
+func ParseChallengeToken(token string) (*Claims, error) {
+	return parseToken(token, PurposeTwoFactor)
+}
+
+// ParsePasswordResetToken 解析重置密码 token
+func ParsePasswordResetToken(token string) (*Claims, error) {
+	return parseToken(token, PurposePasswordReset)
+}
+
+func parseToken(token, purpose string) (*Claims, error) {
+	if jwtKeys == nil {
+		return nil, errKeysNotReady
+	}
+
+	cfg := config.AppConfig.JWT
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(jwtKeys.validMethods()),
+		jwt.WithIssuer(cfg.Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
 	tokenClaims, err := jwt.ParseWithClaims(token, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return secret, nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		key, ok := jwtKeys.lookup(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		// 算法必须与该密钥一致，防止算法混淆攻击
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
+		}
+		return key.public, nil
+	}, opts...)
 
 	if tokenClaims != nil {
 		if claims, ok := tokenClaims.Claims.(*Claims); ok && tokenClaims.Valid {
+			if claims.Purpose != purpose {
+				return nil, errTokenPurpose
+			}
 			return claims, nil
 		}
 	}
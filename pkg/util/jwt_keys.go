@@ -0,0 +1,410 @@
+package util
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-blog-api/pkg/config"
+	"go-blog-api/pkg/oidc"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWT 签名密钥管理：
+// - HS256 使用配置中的单一密钥（兼容旧部署），不对外发布
+// - RS256 / ES256 / EdDSA 使用 key_dir 下的私钥集合，每把密钥有独立 kid
+// - 按 rotation_hours 定期生成新密钥用于签名，旧密钥继续用于验证，直到它签发的 token 全部过期
+// - 从 HS256 切换到非对称算法时，配置中的 secret 在第一把非对称密钥生成后的一个 token 有效期内
+//   仍用于验证切换前签发的 token（只验证不签发），避免切换时所有用户被强制登出
+
+const (
+	hs256KeyID          = "hs256"
+	keyRotationInterval = time.Minute      // 检查轮换 / 重新加载密钥目录的间隔
+	keyReloadMinGap     = 30 * time.Second // 遇到未知 kid 时重新加载的最小间隔
+	keyRetainBuffer     = time.Hour        // 旧密钥在 token 有效期之外额外保留的时间
+)
+
+var (
+	jwtKeys *keySet
+
+	errKeysNotReady = errors.New("jwt keys not initialized")
+)
+
+type signingKey struct {
+	kid       string
+	method    jwt.SigningMethod
+	private   any // HS256 为 []byte，其余为 crypto.Signer
+	public    any
+	createdAt time.Time
+}
+
+type keySet struct {
+	mu         sync.RWMutex
+	alg        string
+	dir        string
+	rotation   time.Duration
+	retain     time.Duration
+	keys       []*signingKey // 按创建时间升序，最后一把同算法的密钥用于签名
+	lastReload time.Time
+
+	legacy      *signingKey // 切换算法前的 HS256 密钥，只用于验证
+	legacyUntil time.Time
+}
+
+// InitJWTKeys 根据配置加载签名密钥，非对称算法下目录为空时自动生成第一把密钥
+func InitJWTKeys() {
+	cfg := config.AppConfig.JWT
+	alg := cfg.Algorithm
+	if alg == "" {
+		alg = jwt.SigningMethodHS256.Alg()
+	}
+
+	ks := &keySet{
+		alg:      alg,
+		dir:      cfg.KeyDir,
+		rotation: time.Duration(cfg.RotationHours) * time.Hour,
+		retain:   time.Duration(cfg.ExpireHours)*time.Hour + keyRetainBuffer,
+	}
+
+	if alg == jwt.SigningMethodHS256.Alg() {
+		ks.keys = []*signingKey{{
+			kid:     hs256KeyID,
+			method:  jwt.SigningMethodHS256,
+			private: []byte(cfg.Secret),
+			public:  []byte(cfg.Secret),
+		}}
+		jwtKeys = ks
+		return
+	}
+
+	if _, err := signingMethod(alg); err != nil {
+		log.Fatalf("Invalid jwt algorithm: %v", err)
+	}
+	if err := os.MkdirAll(ks.dir, 0o700); err != nil {
+		log.Fatalf("Failed to create jwt key dir: %v", err)
+	}
+	now := time.Now()
+	if err := ks.rotateIfDue(now); err != nil {
+		log.Fatalf("Failed to prepare jwt keys: %v", err)
+	}
+	ks.initLegacy(cfg.Secret, now)
+	jwtKeys = ks
+}
+
+// initLegacy 以最早一把非对称密钥的创建时间作为切换时间，切换前的 HS256 token 最晚在
+// 切换时间 + retain 后全部过期；各实例共享密钥目录，因此算出的截止时间一致
+func (ks *keySet) initLegacy(secret string, now time.Time) {
+	if secret == "" || len(ks.keys) == 0 {
+		return
+	}
+	until := ks.keys[0].createdAt.Add(ks.retain)
+	if !now.Before(until) {
+		return
+	}
+	ks.legacy = &signingKey{
+		kid:     hs256KeyID,
+		method:  jwt.SigningMethodHS256,
+		private: nil, // 不用于签名
+		public:  []byte(secret),
+	}
+	ks.legacyUntil = until
+	log.Printf("legacy HS256 tokens accepted until %s", until.Format(time.RFC3339))
+}
+
+// legacyKey 返回仍在过渡期内的 HS256 验证密钥
+func (ks *keySet) legacyKey(now time.Time) (*signingKey, bool) {
+	if ks.legacy == nil || !now.Before(ks.legacyUntil) {
+		return nil, false
+	}
+	return ks.legacy, true
+}
+
+// StartJWTKeyRotation 启动后台轮换任务（HS256 无需轮换）
+func StartJWTKeyRotation() {
+	ks := jwtKeys
+	if ks == nil || ks.dir == "" || ks.alg == jwt.SigningMethodHS256.Alg() {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(keyRotationInterval)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			if err := ks.rotateIfDue(now); err != nil {
+				log.Printf("jwt key rotation failed: %v", err)
+			}
+		}
+	}()
+}
+
+// PublicJWKS 返回当前可用于验证的公钥集合（供 /.well-known/jwks.json 发布）
+func PublicJWKS() oidc.JSONWebKeySet {
+	set := oidc.JSONWebKeySet{Keys: []oidc.JSONWebKey{}}
+	ks := jwtKeys
+	if ks == nil {
+		return set
+	}
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	for _, key := range ks.keys {
+		if key.method == jwt.SigningMethodHS256 {
+			continue // 对称密钥不能公开
+		}
+		jwk, err := oidc.NewJSONWebKey(key.kid, key.method.Alg(), key.public)
+		if err != nil {
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// current 返回当前用于签名的密钥
+func (ks *keySet) current() (*signingKey, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	for i := len(ks.keys) - 1; i >= 0; i-- {
+		if ks.keys[i].method.Alg() == ks.alg {
+			return ks.keys[i], nil
+		}
+	}
+	return nil, errKeysNotReady
+}
+
+// lookup 按 kid 查找验证密钥，未命中时（其他实例刚轮换）限频重新加载目录
+func (ks *keySet) lookup(kid string) (*signingKey, bool) {
+	if key, ok := ks.find(kid); ok {
+		return key, true
+	}
+	if kid == "" || kid == hs256KeyID {
+		return ks.legacyKey(time.Now())
+	}
+	if ks.dir == "" || ks.alg == jwt.SigningMethodHS256.Alg() {
+		return nil, false
+	}
+
+	ks.mu.Lock()
+	if time.Since(ks.lastReload) < keyReloadMinGap {
+		ks.mu.Unlock()
+		return nil, false
+	}
+	err := ks.reloadLocked(time.Now())
+	ks.mu.Unlock()
+	if err != nil {
+		return nil, false
+	}
+	return ks.find(kid)
+}
+
+func (ks *keySet) find(kid string) (*signingKey, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	for _, key := range ks.keys {
+		// 兼容轮换前未携带 kid 的 HS256 token
+		if key.kid == kid || (kid == "" && key.kid == hs256KeyID) {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// validMethods 当前密钥集合涉及的全部算法，用于严格限制 token 的 alg
+func (ks *keySet) validMethods() []string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	seen := map[string]bool{}
+	var methods []string
+	for _, key := range ks.keys {
+		if alg := key.method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	if key, ok := ks.legacyKey(time.Now()); ok && !seen[key.method.Alg()] {
+		methods = append(methods, key.method.Alg())
+	}
+	return methods
+}
+
+// rotateIfDue 重新加载目录，必要时生成新密钥并清理过期的旧密钥
+func (ks *keySet) rotateIfDue(now time.Time) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if err := ks.reloadLocked(now); err != nil {
+		return err
+	}
+
+	var newest *signingKey
+	for _, key := range ks.keys {
+		if key.method.Alg() == ks.alg {
+			newest = key
+		}
+	}
+	if newest != nil && (ks.rotation <= 0 || now.Sub(newest.createdAt) < ks.rotation) {
+		return nil
+	}
+
+	key, err := generateSigningKey(ks.alg, now)
+	if err != nil {
+		return err
+	}
+	if err := writeSigningKey(ks.dir, key); err != nil {
+		return err
+	}
+	ks.keys = append(ks.keys, key)
+	log.Printf("jwt signing key rotated, kid=%s", key.kid)
+	return nil
+}
+
+// reloadLocked 从目录加载全部密钥，并删除已经不再需要的旧密钥；调用方需持有写锁
+func (ks *keySet) reloadLocked(now time.Time) error {
+	entries, err := os.ReadDir(ks.dir)
+	if err != nil {
+		return err
+	}
+
+	var keys []*signingKey
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".pem") {
+			continue
+		}
+		key, err := readSigningKey(filepath.Join(ks.dir, entry.Name()))
+		if err != nil {
+			log.Printf("skip invalid jwt key %s: %v", entry.Name(), err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].createdAt.Before(keys[j].createdAt) })
+
+	// 一把密钥被下一把取代后，再经过 token 最长有效期即可删除
+	kept := keys[:0]
+	for i, key := range keys {
+		if i < len(keys)-1 && now.Sub(keys[i+1].createdAt) > ks.retain {
+			_ = os.Remove(filepath.Join(ks.dir, key.kid+".pem"))
+			continue
+		}
+		kept = append(kept, key)
+	}
+
+	ks.keys = kept
+	ks.lastReload = now
+	return nil
+}
+
+// generateSigningKey 生成新密钥，kid 形如 "<unix 时间>-<随机串>"，便于从文件名恢复创建时间
+func generateSigningKey(alg string, now time.Time) (*signingKey, error) {
+	method, err := signingMethod(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	var private crypto.Signer
+	switch alg {
+	case jwt.SigningMethodRS256.Alg():
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case jwt.SigningMethodES256.Alg():
+		private, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case jwt.SigningMethodEdDSA.Alg():
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	return &signingKey{
+		kid:       strconv.FormatInt(now.Unix(), 10) + "-" + hex.EncodeToString(suffix),
+		method:    method,
+		private:   private,
+		public:    private.Public(),
+		createdAt: now,
+	}, nil
+}
+
+func writeSigningKey(dir string, key *signingKey) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key.private)
+	if err != nil {
+		return err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	// 先写临时文件再改名，避免其他实例读到不完整的文件
+	tmp := filepath.Join(dir, "."+key.kid+".tmp")
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, key.kid+".pem"))
+}
+
+func readSigningKey(path string) (*signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid pem")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	kid := strings.TrimSuffix(filepath.Base(path), ".pem")
+	created, err := strconv.ParseInt(strings.SplitN(kid, "-", 2)[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kid %q", kid)
+	}
+
+	key := &signingKey{kid: kid, createdAt: time.Unix(created, 0)}
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodRS256, private, private.Public()
+	case *ecdsa.PrivateKey:
+		if private.Curve != elliptic.P256() {
+			return nil, errors.New("unsupported ecdsa curve")
+		}
+		key.method, key.private, key.public = jwt.SigningMethodES256, private, private.Public()
+	case ed25519.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodEdDSA, private, private.Public()
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+	return key, nil
+}
+
+func signingMethod(alg string) (jwt.SigningMethod, error) {
+	switch alg {
+	case jwt.SigningMethodRS256.Alg():
+		return jwt.SigningMethodRS256, nil
+	case jwt.SigningMethodES256.Alg():
+		return jwt.SigningMethodES256, nil
+	case jwt.SigningMethodEdDSA.Alg():
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", alg)
+	}
+}
@@ -1,6 +1,8 @@
 package util
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -13,8 +15,12 @@ type Response struct {
 	Data    any    `json:"data"`    // 返回数据，无数据时为 null
 }
 
+// responseDataKey gin.Context 中保存成功响应数据的 key，供条件请求中间件读取
+const responseDataKey = "responseData"
+
 // Success 构建成功响应
 func Success(c *gin.Context, data interface{}) {
+	c.Set(responseDataKey, data)
 	c.JSON(http.StatusOK, Response{
 		Code:    0,
 		Message: "success",
@@ -22,6 +28,11 @@ func Success(c *gin.Context, data interface{}) {
 	})
 }
 
+// ResponseData 返回本次请求通过 Success 写出的数据；未调用 Success 时返回 false
+func ResponseData(c *gin.Context) (any, bool) {
+	return c.Get(responseDataKey)
+}
+
 // Error 构建错误响应
 func Error(c *gin.Context, httpCode int, errCode int, msg string) {
 	c.JSON(httpCode, Response{
@@ -33,6 +44,10 @@ func Error(c *gin.Context, httpCode int, errCode int, msg string) {
 
 // HandleError 统一错误处理，自动识别 BizError 类型
 func HandleError(c *gin.Context, err error) {
+	// 请求已超时：业务层可能把超时包装成了其他错误（数据库错误、资源不存在等），统一返回 504
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(c.Request.Context().Err(), context.DeadlineExceeded) {
+		err = ErrTimeout
+	}
 	if bizErr, ok := err.(*BizError); ok {
 		c.JSON(bizErr.HttpCode, Response{
 			Code:    bizErr.Code,
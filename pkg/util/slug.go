@@ -0,0 +1,65 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+)
+
+// MaxSlugLength slug 最大长度（按字节），超出时在单词边界截断
+const MaxSlugLength = 80
+
+var (
+	slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	pinyinArgs  = pinyin.NewArgs()
+)
+
+// Slugify 由标题生成 URL 友好的 slug：汉字转为不带声调的拼音，其他字母数字转小写，
+// 其余字符作为分隔符；无法转写的标题（如纯符号、日文假名）返回空字符串，由调用方兜底
+func Slugify(title string) string {
+	words := make([]string, 0, 8)
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range title {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word.WriteRune(unicode.ToLower(r))
+		case unicode.Is(unicode.Han, r):
+			flush()
+			if py := pinyin.SinglePinyin(r, pinyinArgs); len(py) > 0 {
+				words = append(words, py[0])
+			}
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	slug := ""
+	for _, w := range words {
+		if len(slug)+len(w)+1 > MaxSlugLength {
+			if slug == "" {
+				slug = w[:MaxSlugLength]
+			}
+			break
+		}
+		if slug != "" {
+			slug += "-"
+		}
+		slug += w
+	}
+	return slug
+}
+
+// ValidSlug 判断是否为合法 slug（小写字母、数字和单个连字符）
+func ValidSlug(slug string) bool {
+	return len(slug) <= MaxSlugLength && slugPattern.MatchString(slug)
+}